@@ -7,6 +7,7 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/stretchr/testify v1.6.1
 	golang.org/x/net v0.26.0
+	golang.org/x/text v0.16.0
 )
 
 require (
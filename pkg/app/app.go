@@ -29,8 +29,8 @@ const (
 )
 
 var (
-	routes = makeRouter()
-	window = newBrowserWindow()
+	routes               = makeRouter()
+	window BrowserWindow = newBrowserWindow()
 )
 
 // Getenv retrieves the value of the environment variable named by the key. It
@@ -91,6 +91,11 @@ func RunWhenOnBrowser() {
 		return
 	}
 
+	if isWebWorker() {
+		runWebWorker()
+		return
+	}
+
 	defer func() {
 		err := recover()
 		displayLoadError(err)
@@ -13,6 +13,7 @@ type browser struct {
 	appUpdate        Func
 	appInstallChange Func
 	appResize        Func
+	share            Func
 	resizeTimer      *time.Timer
 }
 
@@ -23,6 +24,7 @@ func (b *browser) HandleEvents(ctx Context, notifyComponentEvent func(any)) {
 	b.handleAppUpdate(ctx, notifyComponentEvent)
 	b.handleAppInstallChange(ctx, notifyComponentEvent)
 	b.handleAppResize(ctx, notifyComponentEvent)
+	b.handleShare(ctx, notifyComponentEvent)
 }
 
 func (b *browser) handleAnchorClick(ctx Context) {
@@ -147,3 +149,26 @@ func (b *browser) handleAppResize(ctx Context, notifyComponentEvent func(any)) {
 	})
 	Window().Set("onresize", b.appResize)
 }
+
+func (b *browser) handleShare(ctx Context, notifyComponentEvent func(any)) {
+	share := func(v Value) {
+		data := makeSharedData(v)
+		ctx.dispatch(func() {
+			notifyComponentEvent(data)
+		})
+	}
+
+	b.share = FuncOf(func(this Value, args []Value) any {
+		if len(args) != 0 {
+			share(args[0])
+		}
+		return nil
+	})
+	Window().Set("goappOnShare", b.share)
+
+	if shared := Window().Get("goappSharedBeforeWasmLoaded"); shared.Truthy() {
+		for i := 0; i < shared.Length(); i++ {
+			share(shared.Index(i))
+		}
+	}
+}
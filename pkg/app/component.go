@@ -37,6 +37,8 @@ type Composer interface {
 	parent() UI
 	root() UI
 	setRoot(UI) Composer
+	styleScopes() []string
+	setStyleScopes([]string)
 }
 
 // Initializer describes a component that requires initialization
@@ -81,6 +83,17 @@ type Dismounter interface {
 	OnDismount()
 }
 
+// Leaver describes components that delay their removal from the DOM, e.g. to
+// play an exit animation. A leaver stays in the DOM after it is removed from
+// its parent, until it reports that it has left. A leaver that is still
+// leaving when its parent is dismounted is removed and dismounted with it.
+type Leaver interface {
+	// OnLeave is invoked when the component is about to be removed from the
+	// DOM. The component is removed and dismounted once done is called.
+	// This function, as well as done, is executed within the UI goroutine.
+	OnLeave(ctx Context, done func())
+}
+
 // Navigator characterizes components that need to perform specific
 // actions or initializations when they become the target of navigation.
 // By adopting the Navigator interface, components can specify behaviors
@@ -135,6 +148,32 @@ type AppInstaller interface {
 	OnAppInstallChange(Context)
 }
 
+// Styler describes components that declare their own CSS. The styles only
+// apply to the HTML elements rendered by the component, not to the ones
+// rendered by its child components. The component root element is given the
+// class returned by StyleScope, and every element it renders a data attribute
+// named after that class. See ScopeCSS for how selectors are scoped.
+//
+// The styles of routed components and of the components listed in
+// Handler.Stylers are served in a stylesheet loaded with the page. The styles
+// of other components are injected into the page when they are first mounted.
+type Styler interface {
+	// Styles returns the CSS code of the component.
+	Styles() string
+}
+
+// ShareReceiver designates components that receive the data shared with the
+// installed app through the operating system share dialog, or the files opened
+// with the app. The app must be registered as a share target or a file handler
+// with Handler.ShareTarget or Handler.FileHandlers.
+type ShareReceiver interface {
+	// OnShare is called when data is shared with the app or when files are
+	// opened with it. Data received before the app is loaded is delivered to
+	// the components mounted on the first page.
+	// This method is always executed in the UI goroutine context.
+	OnShare(Context, SharedData)
+}
+
 // Resizer identifies components that respond to size alterations within the
 // application. These components can dynamically adjust to diverse size
 // scenarios, ensuring they maintain both a visually appealing and functional
@@ -154,6 +193,12 @@ type Compo struct {
 	ref           Composer
 	parentElement UI
 	rootElement   UI
+
+	// The style scopes of the parent components the component is the root of.
+	parentStyleScopes []string
+
+	// The previous roots that are leaving the DOM.
+	leavingRoots map[UI]func()
 }
 
 // JSValue retrieves the JavaScript value associated with the component's root.
@@ -238,3 +283,26 @@ func (c *Compo) setRoot(v UI) Composer {
 	c.rootElement = v
 	return c.ref
 }
+
+func (c *Compo) styleScopes() []string {
+	return c.parentStyleScopes
+}
+
+func (c *Compo) setStyleScopes(v []string) {
+	c.parentStyleScopes = v
+}
+
+func (c *Compo) leavingChildren() map[UI]func() {
+	return c.leavingRoots
+}
+
+func (c *Compo) setLeavingChild(child UI, remove func()) {
+	if remove == nil {
+		delete(c.leavingRoots, child)
+		return
+	}
+	if c.leavingRoots == nil {
+		c.leavingRoots = make(map[UI]func())
+	}
+	c.leavingRoots[child] = remove
+}
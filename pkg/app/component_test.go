@@ -8,6 +8,7 @@ type hello struct {
 	appUpdated   bool
 	appInstalled bool
 	appResized   bool
+	sharedData   SharedData
 
 	mounted     bool
 	preRendered bool
@@ -33,6 +34,10 @@ func (h *hello) OnResize(ctx Context) {
 	h.appResized = true
 }
 
+func (h *hello) OnShare(ctx Context, d SharedData) {
+	h.sharedData = d
+}
+
 func (h *hello) OnPreRender(ctx Context) {
 	h.preRendered = true
 	// ctx.Page().SetTitle("world")
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/whale1017/go-app/v10/pkg/errors"
+	"github.com/whale1017/go-app/v10/pkg/i18n"
 )
 
 // Context represents a UI element-associated environment enabling interactions
@@ -16,6 +17,7 @@ import (
 type Context struct {
 	context.Context
 
+	window                BrowserWindow
 	page                  func() Page
 	appUpdatable          bool
 	resolveURL            func(string) string
@@ -25,6 +27,8 @@ type Context struct {
 	dispatch              func(func())
 	defere                func(func())
 	async                 func(func())
+	now                   func() time.Time
+	after                 func(time.Duration, func()) func()
 	addComponentUpdate    func(Composer, int)
 	removeComponentUpdate func(Composer)
 	handleAction          func(string, UI, bool, ActionHandler)
@@ -38,6 +42,15 @@ type Context struct {
 	notifyComponentEvent func(Context, UI, any)
 }
 
+// currentWindow returns the window of the engine the context comes from, which
+// is a dedicated in-memory window for test engines.
+func (ctx Context) currentWindow() BrowserWindow {
+	if ctx.window != nil {
+		return ctx.window
+	}
+	return Window()
+}
+
 // Src retrieves the linked UI element of the context.
 func (ctx Context) Src() UI {
 	return ctx.sourceElement
@@ -55,8 +68,8 @@ func (ctx Context) AppUpdateAvailable() bool {
 
 // IsAppInstallable verifies if the app is eligible for installation.
 func (ctx Context) IsAppInstallable() bool {
-	if Window().Get("goappIsAppInstallable").Truthy() {
-		return Window().Call("goappIsAppInstallable").Bool()
+	if ctx.currentWindow().Get("goappIsAppInstallable").Truthy() {
+		return ctx.currentWindow().Call("goappIsAppInstallable").Bool()
 	}
 	return false
 }
@@ -64,7 +77,7 @@ func (ctx Context) IsAppInstallable() bool {
 // ShowAppInstallPrompt initiates the app installation process.
 func (ctx Context) ShowAppInstallPrompt() {
 	if ctx.IsAppInstallable() {
-		Window().Call("goappShowInstallPrompt")
+		ctx.currentWindow().Call("goappShowInstallPrompt")
 	}
 }
 
@@ -95,7 +108,7 @@ func (ctx Context) Reload() {
 	if IsServer {
 		return
 	}
-	Window().Get("location").Call("reload")
+	ctx.currentWindow().Get("location").Call("reload")
 }
 
 // Navigate transitions to the given URL string.
@@ -115,6 +128,57 @@ func (ctx Context) NavigateTo(u *url.URL) {
 	ctx.navigate(u, true)
 }
 
+// T returns the message with the given key translated in the current locale,
+// using the catalog set with SetCatalog. When the first argument is an
+// integer, it selects the plural form of the message. The key is returned
+// when no translation is found.
+func (ctx Context) T(key string, args ...any) string {
+	return catalog.Translate(ctx.Locale(), key, args...)
+}
+
+// Locale returns the locale of the current page.
+func (ctx Context) Locale() string {
+	return ctx.Page().Lang()
+}
+
+// SetLocale changes the locale of the app and navigates to the version of the
+// current page in that locale. The locale is persisted with the state system
+// and used for pages without a locale prefix on later visits.
+func (ctx Context) SetLocale(locale string) {
+	ctx.SetState(localeState, locale).Persist()
+	ctx.Page().SetLang(locale)
+
+	root := strings.TrimSuffix(Getenv("GOAPP_ROOT_PREFIX"), "/")
+	current := ctx.Page().URL()
+	u := *current
+	u.Path = root + localizedPath(
+		strings.TrimPrefix(u.Path, root),
+		locale,
+		appLocales(),
+		Getenv("GOAPP_DEFAULT_LOCALE"),
+	)
+	if u.String() == current.String() {
+		ctx.Reload()
+		return
+	}
+	ctx.navigate(&u, true)
+}
+
+// Format returns a formatter for numbers, currencies and dates in the current
+// locale. It uses the JavaScript Intl API in the browser and the Go
+// implementation of the i18n package, backed by CLDR data, during server
+// prerendering.
+func (ctx Context) Format() i18n.Formatter {
+	return i18n.Formatter{Lang: ctx.Locale()}
+}
+
+// LocalePath returns the given path prefixed with the current locale, or as
+// is when the current locale is the default one. It is intended to build
+// links that keep the user in their locale.
+func (ctx Context) LocalePath(path string) string {
+	return localizedPath(path, ctx.Locale(), appLocales(), Getenv("GOAPP_DEFAULT_LOCALE"))
+}
+
 // ResolveStaticResource adjusts a given path to point to the correct static
 // resource location.
 func (ctx Context) ResolveStaticResource(v string) string {
@@ -124,7 +188,7 @@ func (ctx Context) ResolveStaticResource(v string) string {
 // ScrollTo adjusts the scrollbar to target an HTML element by its ID.
 func (ctx Context) ScrollTo(id string) {
 	ctx.Defer(func(ctx Context) {
-		Window().ScrollToID(id)
+		ctx.currentWindow().ScrollToID(id)
 	})
 }
 
@@ -216,8 +280,7 @@ func (ctx Context) Async(v func()) {
 
 // After pauses for a determined span, then triggers a specified function.
 func (ctx Context) After(d time.Duration, f func(Context)) {
-	ctx.async(func() {
-		time.Sleep(d)
+	ctx.after(d, func() {
 		ctx.Dispatch(f)
 	})
 }
@@ -371,19 +371,24 @@ func makeTestContext() Context {
 		localStorage = newMemoryStorage()
 		sessionStorage = newMemoryStorage()
 	} else {
-		localStorage = newJSStorage("localStorage")
-		sessionStorage = newJSStorage("sessionStorage")
+		localStorage = newJSStorage(Window(), "localStorage")
+		sessionStorage = newJSStorage(Window(), "sessionStorage")
 	}
 
 	return Context{
-		Context:               context.Background(),
-		page:                  func() Page { return page },
-		resolveURL:            resolveURL,
-		localStorage:          localStorage,
-		sessionStorage:        sessionStorage,
-		dispatch:              func(f func()) { f() },
-		defere:                func(f func()) { f() },
-		async:                 func(f func()) { f() },
+		Context:        context.Background(),
+		page:           func() Page { return page },
+		resolveURL:     resolveURL,
+		localStorage:   localStorage,
+		sessionStorage: sessionStorage,
+		dispatch:       func(f func()) { f() },
+		defere:         func(f func()) { f() },
+		async:          func(f func()) { f() },
+		now:            time.Now,
+		after: func(d time.Duration, f func()) func() {
+			t := time.AfterFunc(d, f)
+			return func() { t.Stop() }
+		},
 		addComponentUpdate:    func(Composer, int) {},
 		removeComponentUpdate: func(Composer) {},
 		handleAction:          func(string, UI, bool, ActionHandler) {},
@@ -14,7 +14,8 @@ import (
 )
 
 type engineX struct {
-	ctx context.Context
+	ctx    context.Context
+	window BrowserWindow
 
 	localStorage   BrowserStorage
 	sessionStorage BrowserStorage
@@ -37,6 +38,9 @@ type engineX struct {
 	asynchronousActionHandlers map[string]ActionHandler
 	actions                    actionManager
 	states                     stateManager
+
+	// Posted action recording and fake time, only set on test engines.
+	test *testHarness
 }
 
 func newEngine(ctx context.Context, routes *router, resolveURL func(string) string, originPage *requestPage, actionHandlers map[string]ActionHandler) *engineX {
@@ -46,8 +50,8 @@ func newEngine(ctx context.Context, routes *router, resolveURL func(string) stri
 		localStorage = newMemoryStorage()
 		sessionStorage = newMemoryStorage()
 	} else {
-		localStorage = newJSStorage("localStorage")
-		sessionStorage = newJSStorage("sessionStorage")
+		localStorage = newJSStorage(Window(), "localStorage")
+		sessionStorage = newJSStorage(Window(), "sessionStorage")
 	}
 
 	if resolveURL == nil {
@@ -57,6 +61,7 @@ func newEngine(ctx context.Context, routes *router, resolveURL func(string) stri
 
 	engine := &engineX{
 		ctx:                        ctx,
+		window:                     Window(),
 		routes:                     routes,
 		resolveURL:                 resolveURL,
 		originPage:                 originPage,
@@ -76,6 +81,7 @@ func newEngine(ctx context.Context, routes *router, resolveURL func(string) stri
 func (e *engineX) baseContext() Context {
 	return Context{
 		Context:               e.ctx,
+		window:                e.window,
 		resolveURL:            e.resolveURL,
 		appUpdatable:          e.browser.AppUpdatable,
 		page:                  e.page,
@@ -85,10 +91,12 @@ func (e *engineX) baseContext() Context {
 		dispatch:              e.dispatch,
 		defere:                e.defere,
 		async:                 e.async,
+		now:                   e.now,
+		after:                 e.after,
 		addComponentUpdate:    e.updates.Add,
 		removeComponentUpdate: e.updates.Done,
 		handleAction:          e.actions.Handle,
-		postAction:            e.actions.Post,
+		postAction:            e.postAction,
 		observeState:          e.states.Observe,
 		getState:              e.states.Get,
 		setState:              e.states.Set,
@@ -110,11 +118,11 @@ func (e *engineX) Navigate(destination *url.URL, updateHistory bool) {
 	switch {
 	case e.internalURL(destination),
 		e.mailTo(destination):
-		Window().Get("location").Set("href", destination.String())
+		e.window.Get("location").Set("href", destination.String())
 		return
 
 	case e.externalNavigation(destination):
-		Window().Call("open", destination.String())
+		e.window.Call("open", destination.String())
 		return
 
 	case destination.String() == e.lastVisitedURL.String():
@@ -123,7 +131,7 @@ func (e *engineX) Navigate(destination *url.URL, updateHistory bool) {
 
 	defer func() {
 		if updateHistory {
-			Window().addHistory(destination)
+			e.window.addHistory(destination)
 		}
 		e.lastVisitedURL = destination
 
@@ -131,7 +139,7 @@ func (e *engineX) Navigate(destination *url.URL, updateHistory bool) {
 
 		if destination.Fragment != "" {
 			e.defere(func() {
-				Window().ScrollToID(destination.Fragment)
+				e.window.ScrollToID(destination.Fragment)
 			})
 		}
 	}()
@@ -145,6 +153,7 @@ func (e *engineX) Navigate(destination *url.URL, updateHistory bool) {
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
+	path = e.localize(path)
 	root, ok := e.routes.createComponent(path)
 	if !ok {
 		root = &notFound{}
@@ -200,10 +209,18 @@ func (e *engineX) page() Page {
 func (e *engineX) Load(v Composer) error {
 	if e.body == nil {
 		body := Body()
-		body = body.setJSElement(Window().Get("document").Get("body")).(HTMLBody)
+		body = body.setJSElement(e.window.Get("document").Get("body")).(HTMLBody)
+
+		// The body can be left without element by components previously
+		// loaded into the same document, such as in tests.
+		root := body.JSValue().firstElementChild()
+		if !root.Truthy() {
+			root, _ = e.window.createElement("div", "")
+			body.JSValue().appendChild(root)
+		}
 
 		firstChild := Div()
-		firstChild = firstChild.setJSElement(body.JSValue().firstElementChild()).(HTMLDiv)
+		firstChild = firstChild.setJSElement(root).(HTMLDiv)
 		firstChild = firstChild.setParent(body).(HTMLDiv)
 
 		body = body.setBody([]UI{firstChild}).(HTMLBody)
@@ -360,3 +377,42 @@ func (e *engineX) async(v func()) {
 		e.goroutines.Done()
 	}()
 }
+
+func (e *engineX) now() time.Time {
+	if e.test != nil && e.test.usesFakeClock() {
+		return e.test.now()
+	}
+	return time.Now()
+}
+
+// after calls the given function after the given duration, unless the
+// returned function is called before to stop the timer. Pending timers are
+// waited for like goroutines.
+func (e *engineX) after(d time.Duration, f func()) func() {
+	if e.test != nil && e.test.usesFakeClock() {
+		return e.test.after(d, f)
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(e.goroutines.Done)
+	}
+
+	e.goroutines.Add(1)
+	timer := time.AfterFunc(d, func() {
+		defer release()
+		f()
+	})
+	return func() {
+		if timer.Stop() {
+			release()
+		}
+	}
+}
+
+func (e *engineX) postAction(ctx Context, a Action) {
+	if e.test != nil {
+		e.test.recordAction(a)
+	}
+	e.actions.Post(ctx, a)
+}
@@ -0,0 +1,1739 @@
+//go:build !wasm && fakedom
+// +build !wasm,fakedom
+
+package app
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	fakeElementNode  = 1
+	fakeTextNode     = 3
+	fakeCommentNode  = 8
+	fakeDocumentNode = 9
+	fakeFragmentNode = 11
+
+	htmlNamespace   = "http://www.w3.org/1999/xhtml"
+	svgNamespace    = "http://www.w3.org/2000/svg"
+	mathMLNamespace = "http://www.w3.org/1998/Math/MathML"
+)
+
+// fakeNode is a node of the in-memory DOM.
+type fakeNode struct {
+	window    *fakeWindow
+	object    *fakeObject
+	events    fakeEventTarget
+	nodeType  int
+	tag       string
+	namespace string
+	attrs     []fakeAttr
+	data      string
+	parent    *fakeNode
+	children  []*fakeNode
+
+	value    *string
+	checked  *bool
+	selected *bool
+}
+
+type fakeAttr struct {
+	name  string
+	value string
+}
+
+func newFakeNode(w *fakeWindow, nodeType int) *fakeNode {
+	n := &fakeNode{
+		window:   w,
+		nodeType: nodeType,
+	}
+	n.object = &fakeObject{host: n}
+	return n
+}
+
+func newFakeElement(w *fakeWindow, tag, namespace string) *fakeNode {
+	n := newFakeNode(w, fakeElementNode)
+	if namespace == "" {
+		namespace = htmlNamespace
+	}
+	if namespace == htmlNamespace {
+		tag = strings.ToLower(tag)
+	}
+	n.tag = tag
+	n.namespace = namespace
+	return n
+}
+
+func newFakeText(w *fakeWindow, data string) *fakeNode {
+	n := newFakeNode(w, fakeTextNode)
+	n.data = data
+	return n
+}
+
+// fakeNodeOf returns the node that backs the given value, or nil when the value
+// is not a node.
+func fakeNodeOf(v Value) *fakeNode {
+	if fv, ok := v.(fakeValue); ok && fv.obj != nil {
+		n, _ := fv.obj.host.(*fakeNode)
+		return n
+	}
+	return nil
+}
+
+func (n *fakeNode) jsValue() Value {
+	if n == nil {
+		return fakeNull
+	}
+	return fakeValue{typ: TypeObject, obj: n.object}
+}
+
+func (n *fakeNode) isElement() bool {
+	return n.nodeType == fakeElementNode
+}
+
+func (n *fakeNode) isHTML() bool {
+	return n.isElement() && n.namespace == htmlNamespace
+}
+
+func (n *fakeNode) attr(name string) (string, bool) {
+	if n.isHTML() {
+		name = strings.ToLower(name)
+	}
+	for _, a := range n.attrs {
+		if a.name == name {
+			return a.value, true
+		}
+	}
+	return "", false
+}
+
+func (n *fakeNode) setAttr(name, value string) {
+	if !n.isElement() {
+		return
+	}
+	if n.isHTML() {
+		name = strings.ToLower(name)
+	}
+	for i, a := range n.attrs {
+		if a.name == name {
+			n.attrs[i].value = value
+			return
+		}
+	}
+	n.attrs = append(n.attrs, fakeAttr{name: name, value: value})
+}
+
+func (n *fakeNode) removeAttr(name string) {
+	if n.isHTML() {
+		name = strings.ToLower(name)
+	}
+	for i, a := range n.attrs {
+		if a.name == name {
+			n.attrs = append(n.attrs[:i:i], n.attrs[i+1:]...)
+			break
+		}
+	}
+
+	switch name {
+	case "value":
+		n.value = nil
+
+	case "checked":
+		n.checked = nil
+
+	case "selected":
+		n.selected = nil
+	}
+}
+
+func (n *fakeNode) toggleAttr(name string, v bool) {
+	if v {
+		if _, ok := n.attr(name); !ok {
+			n.setAttr(name, "")
+		}
+		return
+	}
+	n.removeAttr(name)
+}
+
+func (n *fakeNode) hasAttr(name string) bool {
+	_, ok := n.attr(name)
+	return ok
+}
+
+func (n *fakeNode) contains(c *fakeNode) bool {
+	for ; c != nil; c = c.parent {
+		if c == n {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *fakeNode) root() *fakeNode {
+	for n.parent != nil {
+		n = n.parent
+	}
+	return n
+}
+
+func (n *fakeNode) isConnected() bool {
+	return n.root() == n.window.document
+}
+
+func (n *fakeNode) indexOf(c *fakeNode) int {
+	for i, child := range n.children {
+		if child == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// insert inserts the given node at the given index, moving it from its current
+// parent.
+func (n *fakeNode) insert(c *fakeNode, i int) {
+	if c == nil {
+		panic(errors.New("inserting dom node failed").
+			Wrap(errors.New("node is not a fake dom node")))
+	}
+	if c.contains(n) {
+		panic(errors.New("inserting dom node failed").
+			WithTag("parent", n.nodeName()).
+			WithTag("node", c.nodeName()).
+			Wrap(errors.New("node is an ancestor of its new parent")))
+	}
+
+	if c.nodeType == fakeFragmentNode {
+		children := c.children
+		c.children = nil
+		for j, child := range children {
+			child.parent = nil
+			n.insert(child, i+j)
+		}
+		return
+	}
+
+	if c.parent != nil {
+		if c.parent == n && c.parent.indexOf(c) < i {
+			i--
+		}
+		c.parent.detach(c)
+	}
+
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+	c.parent = n
+}
+
+func (n *fakeNode) detach(c *fakeNode) {
+	i := n.indexOf(c)
+	if i < 0 {
+		return
+	}
+
+	if active := n.window.activeElement; active != nil && c.contains(active) {
+		n.window.activeElement = nil
+	}
+	n.children = append(n.children[:i:i], n.children[i+1:]...)
+	c.parent = nil
+}
+
+func (n *fakeNode) appendChild(c *fakeNode) {
+	n.insert(c, len(n.children))
+}
+
+func (n *fakeNode) insertBefore(c, ref *fakeNode) {
+	if ref == nil {
+		n.appendChild(c)
+		return
+	}
+	n.insert(c, n.childIndex(ref, "inserting dom node failed"))
+}
+
+func (n *fakeNode) replaceChild(c, old *fakeNode) {
+	n.childIndex(old, "replacing dom node failed")
+	if c == old {
+		return
+	}
+	n.insertBefore(c, old)
+	n.detach(old)
+}
+
+func (n *fakeNode) removeChild(c *fakeNode) {
+	n.childIndex(c, "removing dom node failed")
+	n.detach(c)
+}
+
+// childIndex returns the index of the given child, or panics with the given
+// message when it is not a child of the node.
+func (n *fakeNode) childIndex(c *fakeNode, msg string) int {
+	i := -1
+	if c != nil {
+		i = n.indexOf(c)
+	}
+	if i < 0 {
+		panic(errors.New(msg).
+			WithTag("parent", n.nodeName()).
+			Wrap(errors.New("node is not a child of the parent")))
+	}
+	return i
+}
+
+func (n *fakeNode) elementChildren() []*fakeNode {
+	var children []*fakeNode
+	for _, c := range n.children {
+		if c.isElement() {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+func (n *fakeNode) firstElementChild() *fakeNode {
+	for _, c := range n.children {
+		if c.isElement() {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *fakeNode) lastElementChild() *fakeNode {
+	for i := len(n.children) - 1; i >= 0; i-- {
+		if c := n.children[i]; c.isElement() {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *fakeNode) sibling(offset int, element bool) *fakeNode {
+	if n.parent == nil {
+		return nil
+	}
+
+	siblings := n.parent.children
+	for i := n.parent.indexOf(n) + offset; i >= 0 && i < len(siblings); i += offset {
+		if !element || siblings[i].isElement() {
+			return siblings[i]
+		}
+	}
+	return nil
+}
+
+func (n *fakeNode) parentElement() *fakeNode {
+	if n.parent != nil && n.parent.isElement() {
+		return n.parent
+	}
+	return nil
+}
+
+func (n *fakeNode) nodeName() string {
+	switch n.nodeType {
+	case fakeTextNode:
+		return "#text"
+
+	case fakeCommentNode:
+		return "#comment"
+
+	case fakeDocumentNode:
+		return "#document"
+
+	case fakeFragmentNode:
+		return "#document-fragment"
+
+	default:
+		if n.isHTML() {
+			return strings.ToUpper(n.tag)
+		}
+		return n.tag
+	}
+}
+
+// walk calls the given function on the descendants of the node, in document
+// order, until it returns false.
+func (n *fakeNode) walk(f func(*fakeNode) bool) bool {
+	for _, c := range n.children {
+		if !f(c) || !c.walk(f) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *fakeNode) textContent() string {
+	switch n.nodeType {
+	case fakeTextNode, fakeCommentNode:
+		return n.data
+
+	case fakeDocumentNode:
+		return ""
+	}
+
+	var b strings.Builder
+	n.walk(func(c *fakeNode) bool {
+		if c.nodeType == fakeTextNode {
+			b.WriteString(c.data)
+		}
+		return true
+	})
+	return b.String()
+}
+
+func (n *fakeNode) setTextContent(v string) {
+	switch n.nodeType {
+	case fakeTextNode, fakeCommentNode:
+		n.data = v
+		return
+
+	case fakeDocumentNode:
+		return
+	}
+
+	for len(n.children) != 0 {
+		n.detach(n.children[0])
+	}
+	if v != "" {
+		n.appendChild(newFakeText(n.window, v))
+	}
+}
+
+func (n *fakeNode) setNodeValue(v string) {
+	if n.nodeType == fakeTextNode || n.nodeType == fakeCommentNode {
+		n.data = v
+	}
+}
+
+func (n *fakeNode) getElementByID(id string) *fakeNode {
+	var elem *fakeNode
+	n.walk(func(c *fakeNode) bool {
+		if v, ok := c.attr("id"); ok && v == id && c.isElement() {
+			elem = c
+			return false
+		}
+		return true
+	})
+	return elem
+}
+
+func (n *fakeNode) querySelectorAll(s string, first bool) []*fakeNode {
+	sel, err := parseSelector(s)
+	if err != nil {
+		panic(err)
+	}
+
+	scope := selectorNode(nil)
+	if n.isElement() {
+		scope = n
+	}
+
+	var elems []*fakeNode
+	n.walk(func(c *fakeNode) bool {
+		if c.isElement() && sel.match(c, scope) {
+			elems = append(elems, c)
+			return !first
+		}
+		return true
+	})
+	return elems
+}
+
+func (n *fakeNode) matches(s string) bool {
+	sel, err := parseSelector(s)
+	if err != nil {
+		panic(err)
+	}
+	return n.isElement() && sel.match(n, n)
+}
+
+func (n *fakeNode) closest(s string) *fakeNode {
+	sel, err := parseSelector(s)
+	if err != nil {
+		panic(err)
+	}
+	for c := n; c != nil && c.isElement(); c = c.parent {
+		if sel.match(c, n) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *fakeNode) cloneNode(deep bool) *fakeNode {
+	c := newFakeNode(n.window, n.nodeType)
+	c.tag = n.tag
+	c.namespace = n.namespace
+	c.data = n.data
+	c.attrs = append([]fakeAttr(nil), n.attrs...)
+	if deep {
+		for _, child := range n.children {
+			c.appendChild(child.cloneNode(true))
+		}
+	}
+	return c
+}
+
+func (n *fakeNode) selectorTag() string {
+	return strings.ToLower(n.tag)
+}
+
+func (n *fakeNode) selectorAttr(name string) (string, bool) {
+	return n.attr(name)
+}
+
+func (n *fakeNode) selectorParent() selectorNode {
+	if p := n.parentElement(); p != nil {
+		return p
+	}
+	return nil
+}
+
+func (n *fakeNode) selectorPrevious() selectorNode {
+	if s := n.sibling(-1, true); s != nil {
+		return s
+	}
+	return nil
+}
+
+func (n *fakeNode) selectorLastChild() selectorNode {
+	if c := n.lastElementChild(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// reflectedBoolAttrs are the boolean properties that reflect an attribute,
+// indexed by property name.
+var reflectedBoolAttrs = map[string]string{
+	"async":          "async",
+	"autofocus":      "autofocus",
+	"autoplay":       "autoplay",
+	"controls":       "controls",
+	"default":        "default",
+	"defer":          "defer",
+	"disabled":       "disabled",
+	"formNoValidate": "formnovalidate",
+	"hidden":         "hidden",
+	"isMap":          "ismap",
+	"loop":           "loop",
+	"multiple":       "multiple",
+	"muted":          "muted",
+	"noValidate":     "novalidate",
+	"open":           "open",
+	"readOnly":       "readonly",
+	"required":       "required",
+	"reversed":       "reversed",
+}
+
+func (n *fakeNode) fakeGet(this fakeValue, name string) (Value, bool) {
+	if v, ok := n.getNodeProperty(name); ok {
+		return v, true
+	}
+
+	switch n.nodeType {
+	case fakeElementNode:
+		return n.getElementProperty(name)
+
+	case fakeDocumentNode:
+		return n.getDocumentProperty(name)
+
+	default:
+		return nil, false
+	}
+}
+
+func (n *fakeNode) getNodeProperty(name string) (Value, bool) {
+	switch name {
+	case "nodeType":
+		return fakeNumber(float64(n.nodeType)), true
+
+	case "nodeName":
+		return fakeString(n.nodeName()), true
+
+	case "parentNode":
+		return n.parent.jsValue(), true
+
+	case "parentElement":
+		return n.parentElement().jsValue(), true
+
+	case "childNodes":
+		return fakeNodeArray(n.children), true
+
+	case "firstChild":
+		if len(n.children) != 0 {
+			return n.children[0].jsValue(), true
+		}
+		return fakeNull, true
+
+	case "lastChild":
+		if len(n.children) != 0 {
+			return n.children[len(n.children)-1].jsValue(), true
+		}
+		return fakeNull, true
+
+	case "nextSibling":
+		return n.sibling(1, false).jsValue(), true
+
+	case "previousSibling":
+		return n.sibling(-1, false).jsValue(), true
+
+	case "ownerDocument":
+		if n.nodeType == fakeDocumentNode {
+			return fakeNull, true
+		}
+		return n.window.document.jsValue(), true
+
+	case "isConnected":
+		return fakeBool(n.isConnected()), true
+
+	case "textContent":
+		if n.nodeType == fakeDocumentNode {
+			return fakeNull, true
+		}
+		return fakeString(n.textContent()), true
+
+	case "nodeValue":
+		if n.nodeType == fakeTextNode || n.nodeType == fakeCommentNode {
+			return fakeString(n.data), true
+		}
+		return fakeNull, true
+
+	case "data", "wholeText":
+		if n.nodeType == fakeTextNode || n.nodeType == fakeCommentNode {
+			return fakeString(n.data), true
+		}
+		return nil, false
+
+	case "appendChild":
+		return fakeMethod(func(args []Value) any {
+			c := fakeNodeOf(fakeArg(args, 0))
+			n.appendChild(c)
+			return c.jsValue()
+		}), true
+
+	case "insertBefore":
+		return fakeMethod(func(args []Value) any {
+			c := fakeNodeOf(fakeArg(args, 0))
+			n.insertBefore(c, fakeNodeOf(fakeArg(args, 1)))
+			return c.jsValue()
+		}), true
+
+	case "replaceChild":
+		return fakeMethod(func(args []Value) any {
+			old := fakeNodeOf(fakeArg(args, 1))
+			n.replaceChild(fakeNodeOf(fakeArg(args, 0)), old)
+			return old.jsValue()
+		}), true
+
+	case "removeChild":
+		return fakeMethod(func(args []Value) any {
+			c := fakeNodeOf(fakeArg(args, 0))
+			n.removeChild(c)
+			return c.jsValue()
+		}), true
+
+	case "append":
+		return fakeMethod(func(args []Value) any {
+			for _, c := range n.nodesOf(args) {
+				n.appendChild(c)
+			}
+			return nil
+		}), true
+
+	case "prepend":
+		return fakeMethod(func(args []Value) any {
+			for i, c := range n.nodesOf(args) {
+				n.insert(c, i)
+			}
+			return nil
+		}), true
+
+	case "remove":
+		return fakeMethod(func(args []Value) any {
+			if n.parent != nil {
+				n.parent.detach(n)
+			}
+			return nil
+		}), true
+
+	case "replaceWith":
+		return fakeMethod(func(args []Value) any {
+			if p := n.parent; p != nil {
+				for _, c := range n.nodesOf(args) {
+					p.insertBefore(c, n)
+				}
+				p.detach(n)
+			}
+			return nil
+		}), true
+
+	case "contains":
+		return fakeMethod(func(args []Value) any {
+			return n.contains(fakeNodeOf(fakeArg(args, 0)))
+		}), true
+
+	case "hasChildNodes":
+		return fakeMethod(func(args []Value) any {
+			return len(n.children) != 0
+		}), true
+
+	case "cloneNode":
+		return fakeMethod(func(args []Value) any {
+			return n.cloneNode(fakeArg(args, 0).Truthy()).jsValue()
+		}), true
+
+	case "getRootNode":
+		return fakeMethod(func(args []Value) any {
+			return n.root().jsValue()
+		}), true
+
+	case "querySelector":
+		return fakeMethod(func(args []Value) any {
+			elems := n.querySelectorAll(fakeToString(fakeArg(args, 0)), true)
+			if len(elems) == 0 {
+				return nil
+			}
+			return elems[0].jsValue()
+		}), true
+
+	case "querySelectorAll":
+		return fakeMethod(func(args []Value) any {
+			return fakeNodeArray(n.querySelectorAll(fakeToString(fakeArg(args, 0)), false))
+		}), true
+
+	case "getElementsByTagName":
+		return fakeMethod(func(args []Value) any {
+			return fakeNodeArray(n.querySelectorAll(fakeToString(fakeArg(args, 0)), false))
+		}), true
+
+	case "getElementsByClassName":
+		return fakeMethod(func(args []Value) any {
+			classes := strings.Fields(fakeToString(fakeArg(args, 0)))
+			if len(classes) == 0 {
+				return fakeArray()
+			}
+			return fakeNodeArray(n.querySelectorAll("."+strings.Join(classes, "."), false))
+		}), true
+
+	case "addEventListener":
+		return fakeMethod(func(args []Value) any {
+			n.events.add(fakeToString(fakeArg(args, 0)), fakeArg(args, 1), fakeArg(args, 2))
+			return nil
+		}), true
+
+	case "removeEventListener":
+		return fakeMethod(func(args []Value) any {
+			n.events.remove(fakeToString(fakeArg(args, 0)), fakeArg(args, 1), fakeArg(args, 2))
+			return nil
+		}), true
+
+	case "dispatchEvent":
+		return fakeMethod(func(args []Value) any {
+			return n.window.dispatchEvent(n, fakeEventOf(fakeArg(args, 0)))
+		}), true
+
+	default:
+		return nil, false
+	}
+}
+
+func (n *fakeNode) getElementProperty(name string) (Value, bool) {
+	if attr, ok := reflectedBoolAttrs[name]; ok {
+		return fakeBool(n.hasAttr(attr)), true
+	}
+
+	switch name {
+	case "tagName":
+		return fakeString(n.nodeName()), true
+
+	case "localName":
+		return fakeString(n.tag), true
+
+	case "namespaceURI":
+		return fakeString(n.namespace), true
+
+	case "id":
+		v, _ := n.attr("id")
+		return fakeString(v), true
+
+	case "className":
+		v, _ := n.attr("class")
+		return fakeString(v), true
+
+	case "classList":
+		return fakeObjectOf(fakeClassList{node: n}), true
+
+	case "dataset":
+		return fakeObjectOf(fakeDataset{node: n}), true
+
+	case "style":
+		return fakeObjectOf(fakeStyle{node: n}), true
+
+	case "children":
+		return fakeNodeArray(n.elementChildren()), true
+
+	case "childElementCount":
+		return fakeNumber(float64(len(n.elementChildren()))), true
+
+	case "firstElementChild":
+		return n.firstElementChild().jsValue(), true
+
+	case "lastElementChild":
+		return n.lastElementChild().jsValue(), true
+
+	case "nextElementSibling":
+		return n.sibling(1, true).jsValue(), true
+
+	case "previousElementSibling":
+		return n.sibling(-1, true).jsValue(), true
+
+	case "innerHTML":
+		return fakeString(n.innerHTML()), true
+
+	case "outerHTML":
+		var b strings.Builder
+		n.writeHTML(&b)
+		return fakeString(b.String()), true
+
+	case "innerText", "outerText":
+		return fakeString(n.textContent()), true
+
+	case "value":
+		if v, ok := n.formValue(); ok {
+			return fakeString(v), true
+		}
+		return nil, false
+
+	case "defaultValue":
+		v, _ := n.attr("value")
+		return fakeString(v), true
+
+	case "checked":
+		if n.checked != nil {
+			return fakeBool(*n.checked), true
+		}
+		return fakeBool(n.hasAttr("checked")), true
+
+	case "selected":
+		if n.selected != nil {
+			return fakeBool(*n.selected), true
+		}
+		return fakeBool(n.hasAttr("selected")), true
+
+	case "type":
+		switch n.tag {
+		case "input":
+			v, _ := n.attr("type")
+			if v == "" {
+				v = "text"
+			}
+			return fakeString(strings.ToLower(v)), true
+
+		case "button":
+			v, _ := n.attr("type")
+			if v == "" {
+				v = "submit"
+			}
+			return fakeString(strings.ToLower(v)), true
+		}
+		return nil, false
+
+	case "form":
+		if f := n.form(); f != nil {
+			return f.jsValue(), true
+		}
+		return fakeNull, true
+
+	case "tabIndex":
+		return fakeNumber(float64(n.tabIndex())), true
+
+	case "contentEditable":
+		v, ok := n.attr("contenteditable")
+		if !ok {
+			return fakeString("inherit"), true
+		}
+		return fakeString(v), true
+
+	case "isContentEditable":
+		return fakeBool(n.isContentEditable()), true
+
+	case "offsetWidth", "offsetHeight", "offsetTop", "offsetLeft",
+		"clientWidth", "clientHeight", "clientTop", "clientLeft",
+		"scrollWidth", "scrollHeight", "scrollTop", "scrollLeft":
+		return fakeNumber(0), true
+
+	case "getAttribute":
+		return fakeMethod(func(args []Value) any {
+			if v, ok := n.attr(fakeToString(fakeArg(args, 0))); ok {
+				return v
+			}
+			return nil
+		}), true
+
+	case "setAttribute":
+		return fakeMethod(func(args []Value) any {
+			n.setAttr(fakeToString(fakeArg(args, 0)), fakeToString(fakeArg(args, 1)))
+			return nil
+		}), true
+
+	case "removeAttribute":
+		return fakeMethod(func(args []Value) any {
+			n.removeAttr(fakeToString(fakeArg(args, 0)))
+			return nil
+		}), true
+
+	case "hasAttribute":
+		return fakeMethod(func(args []Value) any {
+			return n.hasAttr(fakeToString(fakeArg(args, 0)))
+		}), true
+
+	case "toggleAttribute":
+		return fakeMethod(func(args []Value) any {
+			name := fakeToString(fakeArg(args, 0))
+			force := fakeArg(args, 1)
+			v := !n.hasAttr(name)
+			if !force.IsUndefined() {
+				v = force.Truthy()
+			}
+			n.toggleAttr(name, v)
+			return v
+		}), true
+
+	case "getAttributeNames":
+		return fakeMethod(func(args []Value) any {
+			names := make([]any, len(n.attrs))
+			for i, a := range n.attrs {
+				names[i] = a.name
+			}
+			return names
+		}), true
+
+	case "matches":
+		return fakeMethod(func(args []Value) any {
+			return n.matches(fakeToString(fakeArg(args, 0)))
+		}), true
+
+	case "closest":
+		return fakeMethod(func(args []Value) any {
+			return n.closest(fakeToString(fakeArg(args, 0))).jsValue()
+		}), true
+
+	case "focus":
+		return fakeMethod(func(args []Value) any {
+			n.window.focus(n)
+			return nil
+		}), true
+
+	case "blur":
+		return fakeMethod(func(args []Value) any {
+			n.window.blur(n)
+			return nil
+		}), true
+
+	case "click":
+		return fakeMethod(func(args []Value) any {
+			n.window.click(n)
+			return nil
+		}), true
+
+	case "getBoundingClientRect":
+		return fakeMethod(func(args []Value) any {
+			return map[string]any{
+				"x":      0,
+				"y":      0,
+				"width":  0,
+				"height": 0,
+				"top":    0,
+				"right":  0,
+				"bottom": 0,
+				"left":   0,
+			}
+		}), true
+
+	case "getAnimations":
+		return fakeMethod(func(args []Value) any {
+			return fakeArray()
+		}), true
+
+	case "scrollIntoView", "scrollTo", "scrollBy", "select",
+		"setPointerCapture", "releasePointerCapture":
+		return fakeMethod(func(args []Value) any {
+			return nil
+		}), true
+
+	default:
+		return nil, false
+	}
+}
+
+func (n *fakeNode) getDocumentProperty(name string) (Value, bool) {
+	w := n.window
+
+	switch name {
+	case "documentElement":
+		return n.firstElementChild().jsValue(), true
+
+	case "head":
+		return w.headElement().jsValue(), true
+
+	case "body":
+		return w.bodyElement().jsValue(), true
+
+	case "activeElement":
+		if w.activeElement != nil {
+			return w.activeElement.jsValue(), true
+		}
+		return w.bodyElement().jsValue(), true
+
+	case "title":
+		return fakeString(w.title), true
+
+	case "defaultView":
+		return w.fakeValue, true
+
+	case "location":
+		return w.fakeGet(w.fakeValue, "location")
+
+	case "children":
+		return fakeNodeArray(n.elementChildren()), true
+
+	case "firstElementChild":
+		return n.firstElementChild().jsValue(), true
+
+	case "createElement":
+		return fakeMethod(func(args []Value) any {
+			return newFakeElement(w, fakeToString(fakeArg(args, 0)), "").jsValue()
+		}), true
+
+	case "createElementNS":
+		return fakeMethod(func(args []Value) any {
+			return newFakeElement(w, fakeToString(fakeArg(args, 1)), fakeToString(fakeArg(args, 0))).jsValue()
+		}), true
+
+	case "createTextNode":
+		return fakeMethod(func(args []Value) any {
+			return newFakeText(w, fakeToString(fakeArg(args, 0))).jsValue()
+		}), true
+
+	case "createComment":
+		return fakeMethod(func(args []Value) any {
+			c := newFakeNode(w, fakeCommentNode)
+			c.data = fakeToString(fakeArg(args, 0))
+			return c.jsValue()
+		}), true
+
+	case "createDocumentFragment":
+		return fakeMethod(func(args []Value) any {
+			return newFakeNode(w, fakeFragmentNode).jsValue()
+		}), true
+
+	case "getElementById":
+		return fakeMethod(func(args []Value) any {
+			return n.getElementByID(fakeToString(fakeArg(args, 0))).jsValue()
+		}), true
+
+	default:
+		return nil, false
+	}
+}
+
+func (n *fakeNode) fakeSet(name string, v Value) bool {
+	switch name {
+	case "textContent", "innerText", "nodeValue", "data":
+		if v.IsNull() {
+			n.setTextContent("")
+		} else {
+			n.setTextContent(fakeToString(v))
+		}
+		return true
+	}
+
+	if n.nodeType == fakeDocumentNode && name == "title" {
+		n.window.title = fakeToString(v)
+		return true
+	}
+
+	if !n.isElement() {
+		return false
+	}
+
+	if attr, ok := reflectedBoolAttrs[name]; ok {
+		n.toggleAttr(attr, v.Truthy())
+		return true
+	}
+
+	switch name {
+	case "id":
+		n.setAttr("id", fakeToString(v))
+
+	case "className":
+		n.setAttr("class", fakeToString(v))
+
+	case "innerHTML":
+		n.setInnerHTML(fakeToString(v))
+
+	case "value":
+		return n.setFormValue(fakeToString(v))
+
+	case "defaultValue":
+		n.setAttr("value", fakeToString(v))
+
+	case "checked":
+		n.setChecked(v.Truthy())
+
+	case "selected":
+		n.setSelected(v.Truthy())
+
+	case "tabIndex":
+		n.setAttr("tabindex", strconv.Itoa(v.Int()))
+
+	case "contentEditable":
+		n.setAttr("contenteditable", fakeToString(v))
+
+	default:
+		return false
+	}
+	return true
+}
+
+// nodesOf converts the given values to nodes, creating text nodes for strings.
+func (n *fakeNode) nodesOf(values []Value) []*fakeNode {
+	nodes := make([]*fakeNode, len(values))
+	for i, v := range values {
+		if c := fakeNodeOf(v); c != nil {
+			nodes[i] = c
+		} else {
+			nodes[i] = newFakeText(n.window, fakeToString(v))
+		}
+	}
+	return nodes
+}
+
+func fakeNodeArray(nodes []*fakeNode) fakeValue {
+	items := make([]Value, len(nodes))
+	for i, n := range nodes {
+		items[i] = n.jsValue()
+	}
+	return fakeArray(items...)
+}
+
+// formValue returns the value property of form elements.
+func (n *fakeNode) formValue() (string, bool) {
+	switch n.tag {
+	case "input":
+		if n.value != nil {
+			return *n.value, true
+		}
+		if v, ok := n.attr("value"); ok {
+			return v, true
+		}
+		if t, _ := n.attr("type"); t == "checkbox" || t == "radio" {
+			return "on", true
+		}
+		return "", true
+
+	case "textarea":
+		if n.value != nil {
+			return *n.value, true
+		}
+		return n.textContent(), true
+
+	case "select":
+		options := n.querySelectorAll("option", false)
+		for _, o := range options {
+			if o.isSelected() {
+				v, _ := o.formValue()
+				return v, true
+			}
+		}
+		if len(options) != 0 && !n.hasAttr("multiple") {
+			v, _ := options[0].formValue()
+			return v, true
+		}
+		return "", true
+
+	case "option":
+		if v, ok := n.attr("value"); ok {
+			return v, true
+		}
+		return strings.TrimSpace(n.textContent()), true
+
+	default:
+		if v, ok := n.attr("value"); ok {
+			return v, true
+		}
+		return "", false
+	}
+}
+
+func (n *fakeNode) setFormValue(v string) bool {
+	switch n.tag {
+	case "input", "textarea":
+		n.value = &v
+
+	case "select":
+		for _, o := range n.querySelectorAll("option", false) {
+			ov, _ := o.formValue()
+			selected := ov == v
+			o.selected = &selected
+		}
+
+	default:
+		n.setAttr("value", v)
+	}
+	return true
+}
+
+func (n *fakeNode) inputType() string {
+	if n.tag != "input" {
+		return ""
+	}
+	t, _ := n.attr("type")
+	return strings.ToLower(t)
+}
+
+func (n *fakeNode) isChecked() bool {
+	if n.checked != nil {
+		return *n.checked
+	}
+	return n.hasAttr("checked")
+}
+
+// setChecked sets the checkedness of an input, unchecking the other radio
+// buttons of its group.
+func (n *fakeNode) setChecked(v bool) {
+	n.checked = &v
+	if !v || n.inputType() != "radio" {
+		return
+	}
+
+	name, _ := n.attr("name")
+	if name == "" {
+		return
+	}
+
+	group := n.root()
+	if f := n.form(); f != nil {
+		group = f
+	}
+	for _, r := range group.querySelectorAll("input", false) {
+		if rname, _ := r.attr("name"); r != n && r.inputType() == "radio" && rname == name {
+			unchecked := false
+			r.checked = &unchecked
+		}
+	}
+}
+
+func (n *fakeNode) isSelected() bool {
+	if n.selected != nil {
+		return *n.selected
+	}
+	return n.hasAttr("selected")
+}
+
+func (n *fakeNode) setSelected(v bool) {
+	n.selected = &v
+	if !v {
+		return
+	}
+
+	if s := n.closest("select"); s != nil && !s.hasAttr("multiple") {
+		for _, o := range s.querySelectorAll("option", false) {
+			if o != n {
+				unselected := false
+				o.selected = &unselected
+			}
+		}
+	}
+}
+
+func (n *fakeNode) form() *fakeNode {
+	if id, ok := n.attr("form"); ok && id != "" {
+		return n.window.document.getElementByID(id)
+	}
+	for p := n.parent; p != nil; p = p.parent {
+		if p.isHTML() && p.tag == "form" {
+			return p
+		}
+	}
+	return nil
+}
+
+func (n *fakeNode) isDisabled() bool {
+	switch n.tag {
+	case "button", "input", "select", "textarea", "option", "fieldset":
+		return n.hasAttr("disabled")
+	}
+	return false
+}
+
+func (n *fakeNode) isContentEditable() bool {
+	for p := n; p != nil && p.isElement(); p = p.parent {
+		if v, ok := p.attr("contenteditable"); ok {
+			return v != "false"
+		}
+	}
+	return false
+}
+
+func (n *fakeNode) tabIndex() int {
+	if v, ok := n.attr("tabindex"); ok {
+		if i, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return i
+		}
+	}
+
+	switch n.tag {
+	case "a", "area":
+		if n.hasAttr("href") {
+			return 0
+		}
+
+	case "button", "input", "select", "textarea", "iframe", "summary":
+		return 0
+	}
+
+	if n.isContentEditable() {
+		return 0
+	}
+	return -1
+}
+
+// focusable reports whether the element can receive the focus.
+func (n *fakeNode) focusable() bool {
+	if !n.isElement() || !n.isConnected() || n.isDisabled() {
+		return false
+	}
+	return n.hasAttr("tabindex") || n.tabIndex() >= 0
+}
+
+// fakeClassList is the classList property of an element.
+type fakeClassList struct {
+	node *fakeNode
+}
+
+func (l fakeClassList) classes() []string {
+	v, _ := l.node.attr("class")
+	return strings.Fields(v)
+}
+
+func (l fakeClassList) setClasses(classes []string) {
+	l.node.setAttr("class", strings.Join(classes, " "))
+}
+
+func (l fakeClassList) add(class string) {
+	classes := l.classes()
+	if !containsString(classes, class) {
+		l.setClasses(append(classes, class))
+	}
+}
+
+func (l fakeClassList) remove(class string) {
+	classes := l.classes()
+	filtered := classes[:0]
+	for _, c := range classes {
+		if c != class {
+			filtered = append(filtered, c)
+		}
+	}
+	if _, ok := l.node.attr("class"); ok {
+		l.setClasses(filtered)
+	}
+}
+
+func (l fakeClassList) fakeGet(this fakeValue, name string) (Value, bool) {
+	switch name {
+	case "length":
+		return fakeNumber(float64(len(l.classes()))), true
+
+	case "value":
+		v, _ := l.node.attr("class")
+		return fakeString(v), true
+
+	case "add":
+		return fakeMethod(func(args []Value) any {
+			for _, a := range args {
+				l.add(fakeToString(a))
+			}
+			return nil
+		}), true
+
+	case "remove":
+		return fakeMethod(func(args []Value) any {
+			for _, a := range args {
+				l.remove(fakeToString(a))
+			}
+			return nil
+		}), true
+
+	case "contains":
+		return fakeMethod(func(args []Value) any {
+			return containsString(l.classes(), fakeToString(fakeArg(args, 0)))
+		}), true
+
+	case "toggle":
+		return fakeMethod(func(args []Value) any {
+			class := fakeToString(fakeArg(args, 0))
+			force := fakeArg(args, 1)
+			v := !containsString(l.classes(), class)
+			if !force.IsUndefined() {
+				v = force.Truthy()
+			}
+			if v {
+				l.add(class)
+			} else {
+				l.remove(class)
+			}
+			return v
+		}), true
+
+	case "replace":
+		return fakeMethod(func(args []Value) any {
+			old := fakeToString(fakeArg(args, 0))
+			classes := l.classes()
+			for i, c := range classes {
+				if c == old {
+					classes[i] = fakeToString(fakeArg(args, 1))
+					l.setClasses(classes)
+					return true
+				}
+			}
+			return false
+		}), true
+
+	case "item":
+		return fakeMethod(func(args []Value) any {
+			classes := l.classes()
+			if i := fakeArg(args, 0).Int(); i >= 0 && i < len(classes) {
+				return classes[i]
+			}
+			return nil
+		}), true
+
+	default:
+		return nil, false
+	}
+}
+
+func (l fakeClassList) fakeSet(name string, v Value) bool {
+	if name == "value" {
+		l.node.setAttr("class", fakeToString(v))
+		return true
+	}
+	return false
+}
+
+// fakeDataset is the dataset property of an element.
+type fakeDataset struct {
+	node *fakeNode
+}
+
+func (d fakeDataset) attrName(name string) string {
+	var b strings.Builder
+	b.WriteString("data-")
+	for _, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('-')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (d fakeDataset) fakeGet(this fakeValue, name string) (Value, bool) {
+	if v, ok := d.node.attr(d.attrName(name)); ok {
+		return fakeString(v), true
+	}
+	return fakeUndefined, true
+}
+
+func (d fakeDataset) fakeSet(name string, v Value) bool {
+	d.node.setAttr(d.attrName(name), fakeToString(v))
+	return true
+}
+
+// fakeStyle is the style property of an element. It reads and writes the
+// style attribute.
+type fakeStyle struct {
+	node *fakeNode
+}
+
+func (s fakeStyle) properties() []fakeAttr {
+	v, _ := s.node.attr("style")
+
+	var props []fakeAttr
+	for _, decl := range strings.Split(v, ";") {
+		name, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		props = append(props, fakeAttr{
+			name:  strings.ToLower(strings.TrimSpace(name)),
+			value: strings.TrimSpace(value),
+		})
+	}
+	return props
+}
+
+func (s fakeStyle) setProperties(props []fakeAttr) {
+	decls := make([]string, len(props))
+	for i, p := range props {
+		decls[i] = p.name + ": " + p.value + ";"
+	}
+	if len(decls) == 0 {
+		s.node.removeAttr("style")
+		return
+	}
+	s.node.setAttr("style", strings.Join(decls, " "))
+}
+
+func (s fakeStyle) property(name string) string {
+	for _, p := range s.properties() {
+		if p.name == name {
+			return p.value
+		}
+	}
+	return ""
+}
+
+func (s fakeStyle) setProperty(name, value string) {
+	props := s.properties()
+	for i, p := range props {
+		if p.name == name {
+			if value == "" {
+				props = append(props[:i], props[i+1:]...)
+			} else {
+				props[i].value = value
+			}
+			s.setProperties(props)
+			return
+		}
+	}
+	if value != "" {
+		s.setProperties(append(props, fakeAttr{name: name, value: value}))
+	}
+}
+
+// cssPropertyName converts a camel case style property to its CSS name.
+func cssPropertyName(name string) string {
+	if strings.HasPrefix(name, "--") {
+		return name
+	}
+
+	var b strings.Builder
+	for _, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('-')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (s fakeStyle) fakeGet(this fakeValue, name string) (Value, bool) {
+	switch name {
+	case "cssText":
+		v, _ := s.node.attr("style")
+		return fakeString(v), true
+
+	case "getPropertyValue":
+		return fakeMethod(func(args []Value) any {
+			return s.property(fakeToString(fakeArg(args, 0)))
+		}), true
+
+	case "setProperty":
+		return fakeMethod(func(args []Value) any {
+			s.setProperty(fakeToString(fakeArg(args, 0)), fakeToString(fakeArg(args, 1)))
+			return nil
+		}), true
+
+	case "removeProperty":
+		return fakeMethod(func(args []Value) any {
+			name := fakeToString(fakeArg(args, 0))
+			v := s.property(name)
+			s.setProperty(name, "")
+			return v
+		}), true
+
+	default:
+		return fakeString(s.property(cssPropertyName(name))), true
+	}
+}
+
+func (s fakeStyle) fakeSet(name string, v Value) bool {
+	if name == "cssText" {
+		s.node.setAttr("style", fakeToString(v))
+		return true
+	}
+	s.setProperty(cssPropertyName(name), fakeToString(v))
+	return true
+}
+
+func (n *fakeNode) innerHTML() string {
+	var b strings.Builder
+	for _, c := range n.children {
+		c.writeHTML(&b)
+	}
+	return b.String()
+}
+
+var (
+	fakeHTMLTextEscaper = strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		"\u00a0", "&nbsp;",
+	)
+
+	fakeHTMLAttrEscaper = strings.NewReplacer(
+		"&", "&amp;",
+		"\"", "&quot;",
+		"\u00a0", "&nbsp;",
+	)
+)
+
+// writeHTML writes the HTML serialization of the node.
+func (n *fakeNode) writeHTML(b *strings.Builder) {
+	switch n.nodeType {
+	case fakeTextNode:
+		if p := n.parent; p != nil && p.isHTML() && isRawTextElement(p.tag) {
+			b.WriteString(n.data)
+			return
+		}
+		b.WriteString(fakeHTMLTextEscaper.Replace(n.data))
+
+	case fakeCommentNode:
+		b.WriteString("<!--")
+		b.WriteString(n.data)
+		b.WriteString("-->")
+
+	case fakeElementNode:
+		b.WriteByte('<')
+		b.WriteString(n.tag)
+		for _, a := range n.attrs {
+			b.WriteByte(' ')
+			b.WriteString(a.name)
+			b.WriteString(`="`)
+			b.WriteString(fakeHTMLAttrEscaper.Replace(a.value))
+			b.WriteByte('"')
+		}
+		b.WriteByte('>')
+		if n.isHTML() && isVoidElement(n.tag) {
+			return
+		}
+		for _, c := range n.children {
+			c.writeHTML(b)
+		}
+		b.WriteString("</")
+		b.WriteString(n.tag)
+		b.WriteByte('>')
+
+	default:
+		for _, c := range n.children {
+			c.writeHTML(b)
+		}
+	}
+}
+
+func isRawTextElement(tag string) bool {
+	switch tag {
+	case "script", "style", "xmp", "iframe", "noembed", "noframes", "plaintext":
+		return true
+	}
+	return false
+}
+
+func isVoidElement(tag string) bool {
+	switch tag {
+	case "area", "base", "br", "col", "embed", "hr", "img", "input", "link",
+		"meta", "source", "track", "wbr":
+		return true
+	}
+	return false
+}
+
+// setInnerHTML replaces the children of the node with the parsed HTML.
+func (n *fakeNode) setInnerHTML(v string) {
+	context := &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	}
+	if n.isElement() {
+		context.Data = n.tag
+		context.DataAtom = atom.Lookup([]byte(n.tag))
+		switch n.namespace {
+		case svgNamespace:
+			context.Namespace = "svg"
+
+		case mathMLNamespace:
+			context.Namespace = "math"
+		}
+	}
+
+	nodes, err := html.ParseFragment(strings.NewReader(v), context)
+	if err != nil {
+		panic(errors.New("parsing html failed").Wrap(err))
+	}
+
+	for len(n.children) != 0 {
+		n.detach(n.children[0])
+	}
+	for _, node := range nodes {
+		if c := n.window.importHTMLNode(node); c != nil {
+			n.appendChild(c)
+		}
+	}
+}
+
+func (w *fakeWindow) importHTMLNode(node *html.Node) *fakeNode {
+	var n *fakeNode
+
+	switch node.Type {
+	case html.TextNode:
+		return newFakeText(w, node.Data)
+
+	case html.CommentNode:
+		n = newFakeNode(w, fakeCommentNode)
+		n.data = node.Data
+		return n
+
+	case html.ElementNode:
+		namespace := htmlNamespace
+		switch node.Namespace {
+		case "svg":
+			namespace = svgNamespace
+
+		case "math":
+			namespace = mathMLNamespace
+		}
+
+		n = newFakeElement(w, node.Data, namespace)
+		for _, a := range node.Attr {
+			name := a.Key
+			if a.Namespace != "" {
+				name = a.Namespace + ":" + a.Key
+			}
+			n.setAttr(name, a.Val)
+		}
+
+	default:
+		return nil
+	}
+
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if child := w.importHTMLNode(c); child != nil {
+			n.appendChild(child)
+		}
+	}
+	return n
+}
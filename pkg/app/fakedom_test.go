@@ -0,0 +1,543 @@
+//go:build !wasm && fakedom
+// +build !wasm,fakedom
+
+package app
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeValue(t *testing.T) {
+	t.Run("primitives", func(t *testing.T) {
+		require.Equal(t, TypeNull, fakeValueOf(nil).Type())
+		require.True(t, fakeValueOf(true).Bool())
+		require.Equal(t, 42, fakeValueOf(42).Int())
+		require.Equal(t, 4.2, fakeValueOf(4.2).Float())
+		require.Equal(t, "hello", fakeValueOf("hello").String())
+		require.Equal(t, 5, fakeValueOf("hello").Length())
+		require.Equal(t, "<number: 42>", fakeValueOf(42).String())
+		require.Equal(t, "<undefined>", fakeUndefined.String())
+		require.False(t, fakeValueOf("").Truthy())
+		require.True(t, fakeValueOf("hello").Equal(fakeValueOf("hello")))
+		require.Panics(t, func() { fakeNull.Get("foo") })
+	})
+
+	t.Run("objects", func(t *testing.T) {
+		obj := fakeValueOf(map[string]any{
+			"foo":   "bar",
+			"items": []any{1, "two", nil},
+		})
+		require.Equal(t, TypeObject, obj.Type())
+		require.Equal(t, "bar", obj.Get("foo").String())
+		require.True(t, obj.Get("missing").IsUndefined())
+
+		items := obj.Get("items")
+		require.Equal(t, 3, items.Length())
+		require.Equal(t, "two", items.Index(1).String())
+		require.True(t, items.Index(2).IsNull())
+		require.Equal(t, "1,two,", fakeToString(items))
+
+		obj.Set("foo", 21)
+		require.Equal(t, 21, obj.Get("foo").Int())
+		obj.Delete("foo")
+		require.True(t, obj.Get("foo").IsUndefined())
+		require.True(t, obj.Equal(obj))
+		require.False(t, obj.Equal(fakeValueOf(map[string]any{})))
+	})
+
+	t.Run("functions", func(t *testing.T) {
+		var this Value
+		f := FuncOf(func(t Value, args []Value) any {
+			this = t
+			return args[0].Int() + args[1].Int()
+		})
+		defer f.Release()
+
+		require.Equal(t, TypeFunction, f.Type())
+		require.Equal(t, 3, f.Invoke(1, 2).Int())
+
+		obj := fakeValueOf(map[string]any{"sum": f})
+		require.Equal(t, 5, obj.Call("sum", 2, 3).Int())
+		require.True(t, obj.Equal(this))
+		require.Panics(t, func() { obj.Call("missing") })
+	})
+
+	t.Run("promises", func(t *testing.T) {
+		w := newFakeWindow()
+
+		var resolved []Value
+		w.Get("Promise").Call("resolve", 42).Then(func(v Value) {
+			resolved = append(resolved, v)
+		})
+		require.Len(t, resolved, 1)
+		require.Equal(t, 42, resolved[0].Int())
+
+		promise, p := newFakePromise()
+		w.Get("Promise").Call("allSettled", []any{promise, "foo"}).Then(func(v Value) {
+			resolved = append(resolved, v)
+		})
+		require.Len(t, resolved, 1)
+
+		p.resolve(fakeValueOf("bar"))
+		require.Len(t, resolved, 2)
+		require.Equal(t, "fulfilled", resolved[1].Index(0).Get("status").String())
+		require.Equal(t, "bar", resolved[1].Index(0).Get("value").String())
+		require.Equal(t, "foo", resolved[1].Index(1).Get("value").String())
+	})
+}
+
+func TestFakeDOMNodes(t *testing.T) {
+	w := newFakeWindow()
+	doc := w.Get("document")
+	body := doc.Get("body")
+
+	t.Run("document", func(t *testing.T) {
+		require.Equal(t, "HTML", doc.Get("documentElement").Get("tagName").String())
+		require.Equal(t, "HEAD", doc.Get("head").Get("nodeName").String())
+		require.Equal(t, "DIV", body.firstElementChild().Get("tagName").String())
+		require.True(t, doc.Get("activeElement").Equal(body))
+		require.True(t, doc.Get("defaultView").Equal(w))
+
+		doc.Set("title", "hello")
+		require.Equal(t, "hello", doc.Get("title").String())
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		list, _ := w.createElement("ul", "")
+		a, _ := w.createElement("li", "")
+		b, _ := w.createElement("li", "")
+		c, _ := w.createElement("li", "")
+
+		list.appendChild(a)
+		list.appendChild(c)
+		list.insertBefore(b, c)
+		require.Equal(t, 3, list.Get("childElementCount").Int())
+		require.True(t, list.firstChild().Equal(a))
+		require.True(t, a.Get("nextSibling").Equal(b))
+		require.True(t, c.Get("previousElementSibling").Equal(b))
+		require.True(t, b.Get("parentNode").Equal(list))
+		require.True(t, list.Call("contains", c).Bool())
+		require.False(t, list.Get("isConnected").Bool())
+
+		list.appendChild(a)
+		require.True(t, list.Get("lastChild").Equal(a))
+		require.Equal(t, 3, list.Get("children").Length())
+
+		d, _ := w.createElement("li", "")
+		list.replaceChild(d, b)
+		require.True(t, b.Get("parentNode").IsNull())
+		require.True(t, list.Get("children").Index(0).Equal(d))
+
+		list.removeChild(c)
+		require.Equal(t, 2, list.Get("childNodes").Length())
+		require.Panics(t, func() { list.removeChild(c) })
+		require.Panics(t, func() { a.appendChild(list) })
+
+		body.appendChild(list)
+		require.True(t, list.Get("isConnected").Bool())
+		body.removeChild(list)
+	})
+
+	t.Run("attributes", func(t *testing.T) {
+		elem, _ := w.createElement("DIV", "")
+		elem.setAttr("ID", "foo")
+		elem.Set("className", "a b")
+		require.Equal(t, "foo", elem.Get("id").String())
+		require.Equal(t, "foo", elem.getAttr("id"))
+		require.Equal(t, "a b", elem.Call("getAttribute", "class").String())
+		require.True(t, elem.Call("getAttribute", "title").IsNull())
+
+		elem.Get("classList").Call("add", "c")
+		elem.Get("classList").Call("remove", "a")
+		require.True(t, elem.Get("classList").Call("contains", "c").Bool())
+		require.Equal(t, "b c", elem.getAttr("class"))
+		require.False(t, elem.Get("classList").Call("toggle", "b").Bool())
+		require.Equal(t, "c", elem.getAttr("class"))
+
+		elem.Get("dataset").Set("userId", "42")
+		require.Equal(t, "42", elem.getAttr("data-user-id"))
+		require.Equal(t, "42", elem.Get("dataset").Get("userId").String())
+
+		elem.Get("style").Call("setProperty", "color", "red")
+		elem.Get("style").Set("backgroundColor", "blue")
+		require.Equal(t, "color: red; background-color: blue;", elem.getAttr("style"))
+		require.Equal(t, "red", elem.Get("style").Get("color").String())
+
+		elem.Set("hidden", true)
+		require.True(t, elem.Call("hasAttribute", "hidden").Bool())
+		elem.Set("hidden", false)
+		require.False(t, elem.Call("hasAttribute", "hidden").Bool())
+
+		elem.delAttr("id")
+		require.Equal(t, "", elem.Get("id").String())
+	})
+
+	t.Run("go-app attributes", func(t *testing.T) {
+		input, _ := w.createElement("input", "")
+		setJSAttribute(input, "type", "checkbox")
+		setJSAttribute(input, "value", "foo")
+		setJSAttribute(input, "checked", "true")
+		setJSAttribute(input, "disabled", "true")
+		setJSAttribute(input, "readonly", "true")
+
+		require.Equal(t, "checkbox", input.Get("type").String())
+		require.Equal(t, "foo", input.Get("value").String())
+		require.True(t, input.Get("checked").Bool())
+		require.True(t, input.Get("disabled").Bool())
+		require.True(t, input.Get("readOnly").Bool())
+		require.Equal(t, `<input type="checkbox" disabled="" readonly="">`, input.Get("outerHTML").String())
+
+		deleteJSAttribute(input, "value")
+		deleteJSAttribute(input, "disabled")
+		require.Equal(t, "on", input.Get("value").String())
+		require.False(t, input.Get("disabled").Bool())
+	})
+
+	t.Run("form values", func(t *testing.T) {
+		form, _ := w.createElement("form", "")
+		form.setInnerHTML(`
+			<textarea>hello</textarea>
+			<select>
+				<option value="a">A</option>
+				<option selected>B</option>
+			</select>
+			<input type="radio" name="r" value="1" checked>
+			<input type="radio" name="r" value="2">
+		`)
+
+		textarea := form.Call("querySelector", "textarea")
+		require.Equal(t, "hello", textarea.Get("value").String())
+		textarea.Set("value", "bye")
+		require.Equal(t, "bye", textarea.Get("value").String())
+
+		selectElem := form.Call("querySelector", "select")
+		require.Equal(t, "B", selectElem.Get("value").String())
+		selectElem.Set("value", "a")
+		require.Equal(t, "a", selectElem.Get("value").String())
+		require.True(t, selectElem.Get("firstElementChild").Get("selected").Bool())
+
+		radios := form.Call("querySelectorAll", "[type=radio]")
+		radios.Index(1).Set("checked", true)
+		require.False(t, radios.Index(0).Get("checked").Bool())
+		require.True(t, radios.Index(1).Get("checked").Bool())
+	})
+
+	t.Run("text", func(t *testing.T) {
+		text := w.createTextNode("hello")
+		require.Equal(t, 3, text.Get("nodeType").Int())
+		text.setNodeValue("bye")
+		require.Equal(t, "bye", text.Get("nodeValue").String())
+
+		p, _ := w.createElement("p", "")
+		p.appendChild(text)
+		require.Equal(t, "bye", p.Get("textContent").String())
+
+		p.setInnerText("<b>hi</b>")
+		require.Equal(t, 1, p.Get("childNodes").Length())
+		require.Equal(t, "&lt;b&gt;hi&lt;/b&gt;", p.Get("innerHTML").String())
+	})
+
+	t.Run("inner html", func(t *testing.T) {
+		elem, _ := w.createElement("div", "")
+		elem.setInnerHTML(`<p class="x" title="a &quot;b&quot;">Hello <b>world</b><br></p><!--c-->text`)
+		require.Equal(t, 3, elem.Get("childNodes").Length())
+		require.Equal(t, "Hello world", elem.Get("firstChild").Get("textContent").String())
+		require.Equal(t, "#comment", elem.Get("childNodes").Index(1).Get("nodeName").String())
+		require.Equal(t, `<p class="x" title="a &quot;b&quot;">Hello <b>world</b><br></p><!--c-->text`, elem.Get("innerHTML").String())
+
+		svg, _ := w.createElement("svg", svgNamespace)
+		svg.setInnerHTML(`<path d="M0"></path>`)
+		path := svg.firstChild()
+		require.Equal(t, svgNamespace, path.Get("namespaceURI").String())
+		require.Equal(t, "path", path.Get("tagName").String())
+	})
+
+	t.Run("selectors", func(t *testing.T) {
+		elem, _ := w.createElement("div", "")
+		elem.setInnerHTML(`<ul id="list"><li class="a">1</li><li class="b" hidden>2</li><li class="a b">3</li></ul>`)
+		body.appendChild(elem)
+		defer body.removeChild(elem)
+
+		require.Equal(t, "1", elem.Call("querySelector", "li").Get("textContent").String())
+		require.Equal(t, 2, elem.Call("querySelectorAll", ".a").Length())
+		require.Equal(t, 2, elem.Call("querySelectorAll", "li:not([hidden])").Length())
+		require.Equal(t, 1, elem.Call("querySelectorAll", ":scope > ul").Length())
+		require.True(t, elem.Call("querySelector", "span").IsNull())
+
+		third := w.GetElementByID("list").Get("lastElementChild")
+		require.True(t, third.Call("matches", ".a.b").Bool())
+		require.True(t, third.Call("closest", "ul").Equal(w.GetElementByID("list")))
+		require.True(t, w.GetElementByID("missing").IsNull())
+		require.Panics(t, func() { elem.Call("querySelector", "li:hover") })
+	})
+}
+
+func TestFakeDOMEvents(t *testing.T) {
+	w := newFakeWindow()
+	body := w.Get("document").Get("body")
+
+	parent, _ := w.createElement("div", "")
+	child, _ := w.createElement("button", "")
+	parent.appendChild(child)
+	body.appendChild(parent)
+
+	var calls []string
+	listener := func(name string) Func {
+		return FuncOf(func(this Value, args []Value) any {
+			e := args[0]
+			calls = append(calls, name+":"+e.Get("type").String()+":"+strconv.Itoa(e.Get("eventPhase").Int()))
+			return nil
+		})
+	}
+
+	t.Run("capture and bubble", func(t *testing.T) {
+		calls = nil
+		w.Call("addEventListener", "click", listener("window"))
+		parent.Call("addEventListener", "click", listener("parent-capture"), true)
+		parent.addEventListener("click", listener("parent"), nil)
+		child.addEventListener("click", listener("child"), nil)
+
+		child.Call("click")
+		require.Equal(t, []string{
+			"parent-capture:click:1",
+			"child:click:2",
+			"parent:click:3",
+			"window:click:3",
+		}, calls)
+	})
+
+	t.Run("remove listener", func(t *testing.T) {
+		calls = nil
+		l := listener("removed")
+		child.addEventListener("custom", l, nil)
+		child.removeEventListener("custom", l)
+		child.Call("dispatchEvent", w.Get("Event").New("custom"))
+		require.Empty(t, calls)
+	})
+
+	t.Run("once", func(t *testing.T) {
+		calls = nil
+		child.Call("addEventListener", "custom", listener("once"), map[string]any{"once": true})
+		child.Call("dispatchEvent", w.Get("Event").New("custom"))
+		child.Call("dispatchEvent", w.Get("Event").New("custom"))
+		require.Equal(t, []string{"once:custom:2"}, calls)
+	})
+
+	t.Run("stop propagation and prevent default", func(t *testing.T) {
+		calls = nil
+		child.Call("addEventListener", "keydown", FuncOf(func(this Value, args []Value) any {
+			Event{Value: args[0]}.PreventDefault()
+			Event{Value: args[0]}.StopImmediatePropagation()
+			return nil
+		}))
+		child.Call("addEventListener", "keydown", listener("stopped"))
+		parent.Call("addEventListener", "keydown", listener("parent"))
+
+		e := w.Get("KeyboardEvent").New("keydown", map[string]any{
+			"key":        "Escape",
+			"bubbles":    true,
+			"cancelable": true,
+		})
+		require.True(t, e.InstanceOf(w.Get("KeyboardEvent")))
+		require.Equal(t, "Escape", e.Get("key").String())
+		require.False(t, e.Get("altKey").Bool())
+
+		require.False(t, child.Call("dispatchEvent", e).Bool())
+		require.True(t, e.Get("defaultPrevented").Bool())
+		require.True(t, e.Get("target").Equal(child))
+		require.Empty(t, calls)
+	})
+
+	t.Run("event handler property", func(t *testing.T) {
+		calls = nil
+		w.Set("onpopstate", listener("onpopstate"))
+		w.Get("history").Call("pushState", nil, "", "/foo")
+		w.Get("history").Call("back")
+		require.Equal(t, []string{"onpopstate:popstate:2"}, calls)
+	})
+
+	t.Run("focus", func(t *testing.T) {
+		calls = nil
+		input, _ := w.createElement("input", "")
+		parent.appendChild(input)
+		for _, event := range []string{"focus", "blur", "focusin", "focusout"} {
+			input.addEventListener(event, listener("input"), nil)
+			child.addEventListener(event, listener("button"), nil)
+		}
+
+		div, _ := w.createElement("div", "")
+		parent.appendChild(div)
+		div.Call("focus")
+		require.True(t, w.Get("document").Get("activeElement").Equal(body))
+
+		child.Call("focus")
+		input.Call("focus")
+		require.True(t, w.Get("document").Get("activeElement").Equal(input))
+		require.Equal(t, []string{
+			"button:focus:2",
+			"button:focusin:2",
+			"button:blur:2",
+			"button:focusout:2",
+			"input:focus:2",
+			"input:focusin:2",
+		}, calls)
+
+		parent.removeChild(input)
+		require.True(t, w.Get("document").Get("activeElement").Equal(body))
+	})
+
+	t.Run("click default actions", func(t *testing.T) {
+		calls = nil
+		form, _ := w.createElement("form", "")
+		form.setInnerHTML(`<input type="checkbox"><button>Send</button><button disabled>Disabled</button>`)
+		body.appendChild(form)
+		form.addEventListener("submit", listener("form"), nil)
+		form.addEventListener("change", listener("form"), nil)
+
+		checkbox := form.Call("querySelector", "input")
+		checkbox.Call("click")
+		require.True(t, checkbox.Get("checked").Bool())
+
+		buttons := form.Call("querySelectorAll", "button")
+		buttons.Index(0).Call("click")
+		buttons.Index(1).Call("click")
+		require.Equal(t, []string{
+			"window:click:3",
+			"form:change:3",
+			"window:click:3",
+			"form:submit:2",
+		}, calls)
+	})
+}
+
+func TestFakeWindow(t *testing.T) {
+	w := newFakeWindow()
+
+	t.Run("history", func(t *testing.T) {
+		require.Equal(t, "http://localhost/", w.URL().String())
+
+		u, _ := w.URL().Parse("/foo?bar=1#baz")
+		w.addHistory(u)
+		require.Equal(t, "/foo", w.Get("location").Get("pathname").String())
+		require.Equal(t, "?bar=1", w.Get("location").Get("search").String())
+		require.Equal(t, "#baz", w.Get("location").Get("hash").String())
+		require.Equal(t, 2, w.Get("history").Get("length").Int())
+
+		u, _ = w.URL().Parse("/replaced")
+		w.replaceHistory(u)
+		require.Equal(t, "http://localhost/replaced", w.URL().String())
+		require.Equal(t, 2, w.Get("history").Get("length").Int())
+
+		w.Get("history").Call("pushState", map[string]any{"n": 3}, "", "/third")
+		w.Get("history").Call("back")
+		require.Equal(t, "/replaced", w.URL().Path)
+		w.Get("history").Call("forward")
+		require.Equal(t, 3, w.Get("history").Get("state").Get("n").Int())
+
+		w.Get("location").Set("href", "other")
+		require.Equal(t, "http://localhost/other", w.URL().String())
+		require.Equal(t, 4, w.Get("history").Get("length").Int())
+	})
+
+	t.Run("open", func(t *testing.T) {
+		w.Call("open", "https://goapp.dev")
+		require.Equal(t, []string{"https://goapp.dev"}, w.opened)
+	})
+
+	t.Run("storage", func(t *testing.T) {
+		storage := w.Get("localStorage")
+		storage.Call("setItem", "foo", 42)
+		require.Equal(t, "42", storage.Call("getItem", "foo").String())
+		require.Equal(t, 1, storage.Get("length").Int())
+		require.Equal(t, "foo", storage.Call("key", 0).String())
+		require.True(t, storage.Call("getItem", "bar").IsNull())
+
+		storage.Call("removeItem", "foo")
+		require.Equal(t, 0, storage.Get("length").Int())
+		require.True(t, w.Get("sessionStorage").Call("getItem", "foo").IsNull())
+	})
+
+	t.Run("size", func(t *testing.T) {
+		width, height := w.Size()
+		require.Equal(t, 1280, width)
+		require.Equal(t, 720, height)
+	})
+}
+
+func TestFakeJSStorage(t *testing.T) {
+	NewTestEngine()
+	testBrowserStorage(t, newJSStorage(Window(), "localStorage"))
+}
+
+type fakeDOMCounter struct {
+	Compo
+
+	count int
+}
+
+func (c *fakeDOMCounter) Render() UI {
+	return Div().Body(
+		Span().
+			Class("count").
+			Text(c.count),
+		Button().
+			ID("increment").
+			OnClick(func(ctx Context, e Event) {
+				c.count++
+			}).
+			Text("+"),
+	)
+}
+
+func TestTestEngineFakeDOM(t *testing.T) {
+	e := NewTestEngine()
+	compo := &fakeDOMCounter{}
+	require.NoError(t, e.Load(compo))
+	e.ConsumeAll()
+
+	w := e.(*engineX).window
+	doc := w.Get("document")
+	count := doc.Call("querySelector", "span.count")
+	require.Equal(t, "0", count.Get("textContent").String())
+	require.Equal(t, `<div><span class="count">0</span><button id="increment">+</button></div>`, doc.Get("body").Get("innerHTML").String())
+
+	button := w.GetElementByID("increment")
+	button.Call("click")
+	button.Call("click")
+	e.ConsumeAll()
+	require.Equal(t, 2, compo.count)
+	require.Equal(t, "2", count.Get("textContent").String())
+
+	require.NoError(t, e.Load(&hello{}))
+	e.ConsumeAll()
+	require.False(t, button.Get("isConnected").Bool())
+	require.Equal(t, 0, fakeNodeOf(button).events.count("click"))
+
+	e.(*engineX).Navigate(&url.URL{Path: "/foo"}, true)
+	e.ConsumeAll()
+	require.Equal(t, "/foo", w.URL().Path)
+}
+
+func TestTestEngineMatchSnapshot(t *testing.T) {
+	testSnapshotDir(t)
+
+	e := NewTestEngine()
+	require.Error(t, e.MatchSnapshot("counter"))
+
+	require.NoError(t, e.Load(&fakeDOMCounter{}))
+	e.ConsumeAll()
+
+	t.Setenv("GOAPP_UPDATE_SNAPSHOTS", "true")
+	require.NoError(t, e.MatchSnapshot("counter"))
+
+	os.Unsetenv("GOAPP_UPDATE_SNAPSHOTS")
+	require.NoError(t, e.Click("#increment"))
+	err := e.MatchSnapshot("counter")
+	require.Error(t, err)
+	t.Log(err)
+}
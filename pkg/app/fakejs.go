@@ -0,0 +1,1001 @@
+//go:build !wasm && fakedom
+// +build !wasm,fakedom
+
+package app
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// fakeValue is a JavaScript value of the in-memory DOM that test engines use on
+// non-wasm architectures.
+type fakeValue struct {
+	typ Type
+	b   bool
+	n   float64
+	s   string
+	obj *fakeObject
+}
+
+var (
+	fakeUndefined = fakeValue{typ: TypeUndefined}
+	fakeNull      = fakeValue{typ: TypeNull}
+)
+
+// fakeObject is a JavaScript object, array or function.
+type fakeObject struct {
+	props map[string]Value
+	array bool
+	items []Value
+	class *fakeObject
+	host  fakeHost
+	fn    func(this Value, args []Value) any
+	ctor  func(args []Value) Value
+}
+
+// fakeHost is the interface implemented by the Go types that back JavaScript
+// objects with computed properties and methods.
+type fakeHost interface {
+	// Returns the named property and whether it is computed by the host.
+	fakeGet(this fakeValue, name string) (Value, bool)
+
+	// Sets the named property and reports whether it is handled by the host.
+	fakeSet(name string, v Value) bool
+}
+
+func fakeBool(v bool) fakeValue {
+	return fakeValue{typ: TypeBoolean, b: v}
+}
+
+func fakeNumber(v float64) fakeValue {
+	return fakeValue{typ: TypeNumber, n: v}
+}
+
+func fakeString(v string) fakeValue {
+	return fakeValue{typ: TypeString, s: v}
+}
+
+func fakeObjectOf(host fakeHost) fakeValue {
+	return fakeValue{
+		typ: TypeObject,
+		obj: &fakeObject{host: host},
+	}
+}
+
+func fakeMap(props map[string]any) fakeValue {
+	v := fakeObjectOf(nil)
+	for k, p := range props {
+		v.Set(k, p)
+	}
+	return v
+}
+
+func fakeArray(items ...Value) fakeValue {
+	return fakeValue{
+		typ: TypeObject,
+		obj: &fakeObject{
+			array: true,
+			items: items,
+		},
+	}
+}
+
+func fakeFunc(fn func(this Value, args []Value) any) fakeValue {
+	return fakeValue{
+		typ: TypeFunction,
+		obj: &fakeObject{fn: fn},
+	}
+}
+
+// fakeMethod returns a function that ignores the value of "this".
+func fakeMethod(fn func(args []Value) any) fakeValue {
+	return fakeFunc(func(this Value, args []Value) any {
+		return fn(args)
+	})
+}
+
+// fakeValueOf returns x as a fake JavaScript value, following the same mapping
+// as ValueOf.
+func fakeValueOf(x any) Value {
+	switch x := x.(type) {
+	case nil:
+		return fakeNull
+
+	case Value:
+		return x
+
+	case Wrapper:
+		return fakeValueOf(x.JSValue())
+
+	case bool:
+		return fakeBool(x)
+
+	case string:
+		return fakeString(x)
+
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64:
+		return fakeNumber(reflect.ValueOf(x).Convert(reflect.TypeOf(float64(0))).Float())
+
+	case []any:
+		items := make([]Value, len(x))
+		for i, item := range x {
+			items[i] = fakeValueOf(item)
+		}
+		return fakeArray(items...)
+
+	case map[string]any:
+		return fakeMap(x)
+
+	default:
+		panic(errors.New("unsupported javascript value").
+			WithTag("type", reflect.TypeOf(x)))
+	}
+}
+
+func fakeValuesOf(args []any) []Value {
+	values := make([]Value, len(args))
+	for i, arg := range args {
+		values[i] = fakeValueOf(arg)
+	}
+	return values
+}
+
+// fakeArg returns the argument at the given index, or undefined when it is
+// missing.
+func fakeArg(args []Value, i int) Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return fakeUndefined
+}
+
+// fakeCall calls the function f with the given value of "this".
+func fakeCall(f Value, this Value, args ...Value) Value {
+	switch f := f.(type) {
+	case fakeValue:
+		if f.obj != nil && f.obj.fn != nil {
+			return fakeValueOf(f.obj.fn(this, args))
+		}
+
+	case function:
+		if f.obj.fn != nil {
+			return fakeValueOf(f.obj.fn(this, args))
+		}
+		return fakeUndefined
+	}
+
+	panic(errors.New("javascript value is not a function").
+		WithTag("type", f.Type()))
+}
+
+// fakeSame reports whether the given values are the same, according to
+// JavaScript's === operator.
+func fakeSame(a, b Value) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if fa, ok := a.(fakeValue); ok {
+		return fa.Equal(b)
+	}
+	return a == b
+}
+
+// fakeToString converts the given value to a string, like JavaScript's String()
+// function.
+func fakeToString(v Value) string {
+	switch v.Type() {
+	case TypeUndefined:
+		return "undefined"
+
+	case TypeNull:
+		return "null"
+
+	case TypeBoolean:
+		return strconv.FormatBool(v.Bool())
+
+	case TypeNumber:
+		return jsNumberString(v.Float())
+
+	case TypeString:
+		return v.String()
+
+	case TypeFunction:
+		return "function () { [native code] }"
+	}
+
+	if fv, ok := v.(fakeValue); ok && fv.obj.array {
+		items := make([]string, len(fv.obj.items))
+		for i, item := range fv.obj.items {
+			if item.Type() != TypeUndefined && item.Type() != TypeNull {
+				items[i] = fakeToString(item)
+			}
+		}
+		return strings.Join(items, ",")
+	}
+	return "[object Object]"
+}
+
+func jsNumberString(n float64) string {
+	switch {
+	case math.IsNaN(n):
+		return "NaN"
+
+	case math.IsInf(n, 1):
+		return "Infinity"
+
+	case math.IsInf(n, -1):
+		return "-Infinity"
+
+	case n == math.Trunc(n) && math.Abs(n) < 1e21:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+
+	default:
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	}
+}
+
+func (v fakeValue) Bool() bool {
+	if v.typ == TypeBoolean {
+		return v.b
+	}
+	return v.Truthy()
+}
+
+func (v fakeValue) Call(m string, args ...any) Value {
+	f := v.Get(m)
+	if f.Type() != TypeFunction {
+		panic(errors.New("calling javascript method failed").
+			WithTag("method", m).
+			WithTag("type", v.typ).
+			Wrap(errors.New("method is not a function")))
+	}
+	return fakeCall(f, v, fakeValuesOf(args)...)
+}
+
+func (v fakeValue) Delete(p string) {
+	v.object(p)
+	delete(v.obj.props, p)
+}
+
+func (v fakeValue) Equal(w Value) bool {
+	if w == nil {
+		return false
+	}
+	fw, ok := w.JSValue().(fakeValue)
+	if !ok || v.typ != fw.typ {
+		return false
+	}
+
+	switch v.typ {
+	case TypeBoolean:
+		return v.b == fw.b
+
+	case TypeNumber:
+		return v.n == fw.n
+
+	case TypeString:
+		return v.s == fw.s
+
+	case TypeObject, TypeFunction:
+		return v.obj == fw.obj
+
+	default:
+		return true
+	}
+}
+
+func (v fakeValue) Float() float64 {
+	switch v.typ {
+	case TypeNumber:
+		return v.n
+
+	case TypeBoolean:
+		if v.b {
+			return 1
+		}
+		return 0
+
+	case TypeString:
+		n, _ := strconv.ParseFloat(strings.TrimSpace(v.s), 64)
+		return n
+
+	default:
+		return 0
+	}
+}
+
+func (v fakeValue) Get(p string) Value {
+	switch v.typ {
+	case TypeString:
+		if p == "length" {
+			return fakeNumber(float64(len(utf16.Encode([]rune(v.s)))))
+		}
+		return fakeUndefined
+
+	case TypeObject, TypeFunction:
+		return v.obj.get(v, p)
+
+	case TypeUndefined, TypeNull:
+		panic(errors.New("getting javascript property failed").
+			WithTag("property", p).
+			WithTag("type", v.typ))
+
+	default:
+		return fakeUndefined
+	}
+}
+
+func (v fakeValue) Index(i int) Value {
+	switch {
+	case v.typ == TypeString:
+		runes := []rune(v.s)
+		if i >= 0 && i < len(runes) {
+			return fakeString(string(runes[i]))
+		}
+		return fakeUndefined
+
+	case v.obj != nil && v.obj.array:
+		if i >= 0 && i < len(v.obj.items) {
+			return v.obj.items[i]
+		}
+		return fakeUndefined
+
+	default:
+		return v.Get(strconv.Itoa(i))
+	}
+}
+
+func (v fakeValue) InstanceOf(t Value) bool {
+	ft, ok := t.(fakeValue)
+	return ok && v.obj != nil && ft.obj != nil && v.obj.class == ft.obj
+}
+
+func (v fakeValue) Int() int {
+	return int(v.Float())
+}
+
+func (v fakeValue) Invoke(args ...any) Value {
+	if v.typ != TypeFunction {
+		panic(errors.New("invoking javascript value failed").
+			WithTag("type", v.typ).
+			Wrap(errors.New("value is not a function")))
+	}
+	return fakeCall(v, fakeUndefined, fakeValuesOf(args)...)
+}
+
+func (v fakeValue) IsNaN() bool {
+	return v.typ == TypeNumber && math.IsNaN(v.n)
+}
+
+func (v fakeValue) IsNull() bool {
+	return v.typ == TypeNull
+}
+
+func (v fakeValue) IsUndefined() bool {
+	return v.typ == TypeUndefined
+}
+
+func (v fakeValue) JSValue() Value {
+	return v
+}
+
+func (v fakeValue) Length() int {
+	return v.Get("length").Int()
+}
+
+func (v fakeValue) New(args ...any) Value {
+	if v.typ != TypeFunction || v.obj.ctor == nil {
+		panic(errors.New("creating javascript object failed").
+			WithTag("type", v.typ).
+			Wrap(errors.New("value is not a constructor")))
+	}
+	return v.obj.ctor(fakeValuesOf(args))
+}
+
+func (v fakeValue) Set(p string, x any) {
+	v.object(p)
+	v.obj.set(p, fakeValueOf(x))
+}
+
+func (v fakeValue) SetIndex(i int, x any) {
+	v.object(strconv.Itoa(i))
+	if !v.obj.array || i < 0 {
+		v.obj.set(strconv.Itoa(i), fakeValueOf(x))
+		return
+	}
+
+	for len(v.obj.items) <= i {
+		v.obj.items = append(v.obj.items, fakeUndefined)
+	}
+	v.obj.items[i] = fakeValueOf(x)
+}
+
+func (v fakeValue) String() string {
+	switch v.typ {
+	case TypeString:
+		return v.s
+
+	case TypeBoolean:
+		return "<boolean: " + strconv.FormatBool(v.b) + ">"
+
+	case TypeNumber:
+		return "<number: " + jsNumberString(v.n) + ">"
+
+	default:
+		return "<" + v.typ.String() + ">"
+	}
+}
+
+func (v fakeValue) Truthy() bool {
+	switch v.typ {
+	case TypeBoolean:
+		return v.b
+
+	case TypeNumber:
+		return v.n != 0 && !math.IsNaN(v.n)
+
+	case TypeString:
+		return v.s != ""
+
+	case TypeObject, TypeFunction, TypeSymbol:
+		return true
+
+	default:
+		return false
+	}
+}
+
+func (v fakeValue) Type() Type {
+	return v.typ
+}
+
+func (v fakeValue) Then(f func(Value)) {
+	if v.obj == nil {
+		return
+	}
+	if p, ok := v.obj.host.(*fakePromise); ok {
+		p.then(f)
+	}
+}
+
+func (v fakeValue) getAttr(k string) string {
+	if n := fakeNodeOf(v); n != nil {
+		attr, _ := n.attr(k)
+		return attr
+	}
+	return ""
+}
+
+func (v fakeValue) setAttr(k, val string) {
+	if n := fakeNodeOf(v); n != nil {
+		n.setAttr(k, val)
+	}
+}
+
+func (v fakeValue) delAttr(k string) {
+	if n := fakeNodeOf(v); n != nil {
+		n.removeAttr(k)
+	}
+}
+
+func (v fakeValue) firstChild() Value {
+	if n := fakeNodeOf(v); n != nil && len(n.children) != 0 {
+		return n.children[0].jsValue()
+	}
+	return fakeNull
+}
+
+func (v fakeValue) appendChild(c Wrapper) {
+	if n := fakeNodeOf(v); n != nil {
+		n.appendChild(fakeNodeOf(c.JSValue()))
+	}
+}
+
+func (v fakeValue) replaceChild(new, old Wrapper) {
+	if n := fakeNodeOf(v); n != nil {
+		n.replaceChild(fakeNodeOf(new.JSValue()), fakeNodeOf(old.JSValue()))
+	}
+}
+
+func (v fakeValue) insertBefore(new, ref Wrapper) {
+	if n := fakeNodeOf(v); n != nil {
+		n.insertBefore(fakeNodeOf(new.JSValue()), fakeNodeOf(ref.JSValue()))
+	}
+}
+
+func (v fakeValue) removeChild(c Wrapper) {
+	if n := fakeNodeOf(v); n != nil {
+		n.removeChild(fakeNodeOf(c.JSValue()))
+	}
+}
+
+func (v fakeValue) firstElementChild() Value {
+	if n := fakeNodeOf(v); n != nil {
+		return n.firstElementChild().jsValue()
+	}
+	return fakeNull
+}
+
+func (v fakeValue) addEventListener(event string, fn Func, options map[string]any) {
+	if t := fakeTargetOf(v); t != nil {
+		t.targetEvents().add(event, fn, fakeValueOf(options))
+	}
+}
+
+func (v fakeValue) removeEventListener(event string, fn Func) {
+	if t := fakeTargetOf(v); t != nil {
+		t.targetEvents().remove(event, fn, fakeUndefined)
+	}
+}
+
+func (v fakeValue) setNodeValue(val string) {
+	if n := fakeNodeOf(v); n != nil {
+		n.setNodeValue(val)
+	}
+}
+
+func (v fakeValue) setInnerHTML(val string) {
+	if n := fakeNodeOf(v); n != nil {
+		n.setInnerHTML(val)
+	}
+}
+
+func (v fakeValue) setInnerText(val string) {
+	if n := fakeNodeOf(v); n != nil {
+		n.setTextContent(val)
+	}
+}
+
+// object panics when the value is not an object.
+func (v fakeValue) object(p string) {
+	if v.obj == nil {
+		panic(errors.New("accessing javascript property failed").
+			WithTag("property", p).
+			WithTag("type", v.typ))
+	}
+}
+
+func (o *fakeObject) get(this fakeValue, p string) Value {
+	if o.host != nil {
+		if v, ok := o.host.fakeGet(this, p); ok {
+			return v
+		}
+	}
+	if v, ok := o.props[p]; ok {
+		return v
+	}
+	if o.array && p == "length" {
+		return fakeNumber(float64(len(o.items)))
+	}
+	return fakeUndefined
+}
+
+func (o *fakeObject) set(p string, v Value) {
+	if o.host != nil && o.host.fakeSet(p, v) {
+		return
+	}
+	if o.props == nil {
+		o.props = make(map[string]Value)
+	}
+	o.props[p] = v
+}
+
+// fakePromise is a JavaScript promise that is settled from Go.
+type fakePromise struct {
+	mu       sync.Mutex
+	settled  bool
+	rejected bool
+	value    Value
+	waiting  []func(rejected bool, v Value)
+}
+
+func newFakePromise() (fakeValue, *fakePromise) {
+	p := &fakePromise{}
+	return fakeObjectOf(p), p
+}
+
+func fakeResolvedPromise(v Value) fakeValue {
+	promise, p := newFakePromise()
+	p.resolve(v)
+	return promise
+}
+
+func fakeRejectedPromise(v Value) fakeValue {
+	promise, p := newFakePromise()
+	p.reject(v)
+	return promise
+}
+
+// fakePromiseOf returns the promise that backs the given value.
+func fakePromiseOf(v Value) (*fakePromise, bool) {
+	if fv, ok := v.(fakeValue); ok && fv.obj != nil {
+		p, ok := fv.obj.host.(*fakePromise)
+		return p, ok
+	}
+	return nil, false
+}
+
+// fakePromiseConstructor returns the Promise constructor and its static
+// methods.
+func fakePromiseConstructor() fakeValue {
+	ctor := fakeFunc(func(this Value, args []Value) any {
+		return nil
+	})
+	ctor.obj.ctor = func(args []Value) Value {
+		promise, p := newFakePromise()
+		fakeCall(fakeArg(args, 0), fakeUndefined,
+			fakeMethod(func(args []Value) any {
+				p.resolve(fakeArg(args, 0))
+				return nil
+			}),
+			fakeMethod(func(args []Value) any {
+				p.reject(fakeArg(args, 0))
+				return nil
+			}),
+		)
+		return promise
+	}
+
+	ctor.Set("resolve", fakeMethod(func(args []Value) any {
+		return fakeResolvedPromise(fakeArg(args, 0))
+	}))
+	ctor.Set("reject", fakeMethod(func(args []Value) any {
+		return fakeRejectedPromise(fakeArg(args, 0))
+	}))
+	ctor.Set("all", fakeMethod(func(args []Value) any {
+		return fakePromiseAll(fakeArg(args, 0), false)
+	}))
+	ctor.Set("allSettled", fakeMethod(func(args []Value) any {
+		return fakePromiseAll(fakeArg(args, 0), true)
+	}))
+	return ctor
+}
+
+func (p *fakePromise) fakeGet(this fakeValue, name string) (Value, bool) {
+	switch name {
+	case "then":
+		return fakeMethod(func(args []Value) any {
+			return p.chain(fakeArg(args, 0), fakeArg(args, 1))
+		}), true
+
+	case "catch":
+		return fakeMethod(func(args []Value) any {
+			return p.chain(fakeUndefined, fakeArg(args, 0))
+		}), true
+
+	default:
+		return nil, false
+	}
+}
+
+func (p *fakePromise) fakeSet(name string, v Value) bool {
+	return false
+}
+
+// chain returns a promise settled with the result of the given callbacks,
+// like Promise.prototype.then.
+func (p *fakePromise) chain(onFulfilled, onRejected Value) fakeValue {
+	next, np := newFakePromise()
+	p.subscribe(func(rejected bool, v Value) {
+		f := onFulfilled
+		if rejected {
+			f = onRejected
+		}
+
+		switch {
+		case f.Type() == TypeFunction:
+			np.resolve(fakeCall(f, fakeUndefined, v))
+
+		case rejected:
+			np.reject(v)
+
+		default:
+			np.resolve(v)
+		}
+	})
+	return next
+}
+
+func (p *fakePromise) resolve(v Value) {
+	if vp, ok := fakePromiseOf(v); ok {
+		vp.subscribe(p.settle)
+		return
+	}
+	p.settle(false, v)
+}
+
+func (p *fakePromise) reject(v Value) {
+	p.settle(true, v)
+}
+
+func (p *fakePromise) settle(rejected bool, v Value) {
+	p.mu.Lock()
+	if p.settled {
+		p.mu.Unlock()
+		return
+	}
+	p.settled = true
+	p.rejected = rejected
+	p.value = v
+	waiting := p.waiting
+	p.waiting = nil
+	p.mu.Unlock()
+
+	for _, f := range waiting {
+		f(rejected, v)
+	}
+}
+
+func (p *fakePromise) subscribe(f func(rejected bool, v Value)) {
+	p.mu.Lock()
+	if !p.settled {
+		p.waiting = append(p.waiting, f)
+		p.mu.Unlock()
+		return
+	}
+	rejected, v := p.rejected, p.value
+	p.mu.Unlock()
+
+	f(rejected, v)
+}
+
+func (p *fakePromise) then(f func(Value)) {
+	p.subscribe(func(rejected bool, v Value) {
+		if !rejected {
+			f(v)
+		}
+	})
+}
+
+// fakePromiseAll returns a promise resolved with the results of the given
+// values once they are all settled. When allSettled is false, the promise is
+// rejected as soon as one of the values is rejected. Otherwise, results are
+// described with objects, like Promise.allSettled does.
+func fakePromiseAll(values Value, allSettled bool) fakeValue {
+	promise, p := newFakePromise()
+
+	count := values.Length()
+	results := make([]Value, count)
+	remaining := count
+	if remaining == 0 {
+		p.resolve(fakeArray())
+		return promise
+	}
+
+	var mu sync.Mutex
+	for i := 0; i < count; i++ {
+		i := i
+		item := values.Index(i)
+		settle := func(rejected bool, v Value) {
+			switch {
+			case allSettled && rejected:
+				v = fakeMap(map[string]any{
+					"status": "rejected",
+					"reason": v,
+				})
+
+			case allSettled:
+				v = fakeMap(map[string]any{
+					"status": "fulfilled",
+					"value":  v,
+				})
+
+			case rejected:
+				p.reject(v)
+				return
+			}
+
+			mu.Lock()
+			results[i] = v
+			remaining--
+			done := remaining == 0
+			mu.Unlock()
+
+			if done {
+				p.resolve(fakeArray(results...))
+			}
+		}
+
+		if ip, ok := fakePromiseOf(item); ok {
+			ip.subscribe(settle)
+			continue
+		}
+		settle(false, item)
+	}
+	return promise
+}
+
+// fakeErrorConstructor returns the Error constructor.
+func fakeErrorConstructor() fakeValue {
+	ctor := fakeFunc(func(this Value, args []Value) any {
+		return nil
+	})
+	ctor.obj.ctor = func(args []Value) Value {
+		message := ""
+		if m := fakeArg(args, 0); m.Type() != TypeUndefined {
+			message = fakeToString(m)
+		}
+
+		err := fakeMap(map[string]any{
+			"name":    "Error",
+			"message": message,
+		})
+		err.obj.class = ctor.obj
+		return err
+	}
+	return ctor
+}
+
+// fakeObjectConstructor returns the Object constructor and its static
+// methods.
+func fakeObjectConstructor() fakeValue {
+	ctor := fakeFunc(func(this Value, args []Value) any {
+		return nil
+	})
+	ctor.obj.ctor = func(args []Value) Value {
+		return fakeObjectOf(nil)
+	}
+
+	ctor.Set("keys", fakeMethod(func(args []Value) any {
+		v, ok := fakeArg(args, 0).(fakeValue)
+		if !ok || v.obj == nil {
+			return fakeArray()
+		}
+
+		var keys []string
+		if v.obj.array {
+			for i := range v.obj.items {
+				keys = append(keys, strconv.Itoa(i))
+			}
+		}
+		props := make([]string, 0, len(v.obj.props))
+		for k := range v.obj.props {
+			props = append(props, k)
+		}
+		sort.Strings(props)
+		keys = append(keys, props...)
+
+		items := make([]Value, len(keys))
+		for i, k := range keys {
+			items[i] = fakeString(k)
+		}
+		return fakeArray(items...)
+	}))
+	return ctor
+}
+
+// fakeDate is a JavaScript Date.
+type fakeDate struct {
+	ms float64
+}
+
+// fakeDateConstructor returns the Date constructor.
+func fakeDateConstructor() fakeValue {
+	ctor := fakeFunc(func(this Value, args []Value) any {
+		return nil
+	})
+	ctor.obj.ctor = func(args []Value) Value {
+		d := &fakeDate{ms: float64(time.Now().UnixMilli())}
+		switch arg := fakeArg(args, 0); arg.Type() {
+		case TypeNumber:
+			d.ms = arg.Float()
+
+		case TypeString:
+			if t, err := time.Parse(time.RFC3339Nano, arg.String()); err == nil {
+				d.ms = float64(t.UnixMilli())
+			} else {
+				d.ms = math.NaN()
+			}
+		}
+
+		date := fakeObjectOf(d)
+		date.obj.class = ctor.obj
+		return date
+	}
+
+	ctor.Set("now", fakeMethod(func(args []Value) any {
+		return float64(time.Now().UnixMilli())
+	}))
+	return ctor
+}
+
+func (d *fakeDate) fakeGet(this fakeValue, name string) (Value, bool) {
+	switch name {
+	case "getTime", "valueOf":
+		return fakeMethod(func(args []Value) any {
+			return d.ms
+		}), true
+
+	case "toISOString", "toJSON":
+		return fakeMethod(func(args []Value) any {
+			return time.UnixMilli(int64(d.ms)).UTC().Format("2006-01-02T15:04:05.000Z")
+		}), true
+
+	default:
+		return nil, false
+	}
+}
+
+func (d *fakeDate) fakeSet(name string, v Value) bool {
+	return false
+}
+
+// fakeBytes is a JavaScript Uint8Array.
+type fakeBytes struct {
+	b []byte
+}
+
+// fakeUint8ArrayConstructor returns the Uint8Array constructor.
+func fakeUint8ArrayConstructor() fakeValue {
+	ctor := fakeFunc(func(this Value, args []Value) any {
+		return nil
+	})
+	ctor.obj.ctor = func(args []Value) Value {
+		b := &fakeBytes{}
+		switch arg := fakeArg(args, 0); {
+		case arg.Type() == TypeNumber:
+			b.b = make([]byte, arg.Int())
+
+		case arg.Type() == TypeObject:
+			if src, ok := fakeBytesOf(arg); ok {
+				b.b = append([]byte{}, src.b...)
+				break
+			}
+			b.b = make([]byte, arg.Length())
+			for i := range b.b {
+				b.b[i] = byte(arg.Index(i).Int())
+			}
+		}
+
+		array := fakeObjectOf(b)
+		array.obj.class = ctor.obj
+		return array
+	}
+	return ctor
+}
+
+// fakeBytesOf returns the bytes that back the given Uint8Array.
+func fakeBytesOf(v Value) (*fakeBytes, bool) {
+	if fv, ok := v.(fakeValue); ok && fv.obj != nil {
+		b, ok := fv.obj.host.(*fakeBytes)
+		return b, ok
+	}
+	return nil, false
+}
+
+func (b *fakeBytes) fakeGet(this fakeValue, name string) (Value, bool) {
+	switch name {
+	case "length", "byteLength":
+		return fakeNumber(float64(len(b.b))), true
+	}
+
+	if i, err := strconv.Atoi(name); err == nil {
+		if i >= 0 && i < len(b.b) {
+			return fakeNumber(float64(b.b[i])), true
+		}
+		return fakeUndefined, true
+	}
+	return nil, false
+}
+
+func (b *fakeBytes) fakeSet(name string, v Value) bool {
+	i, err := strconv.Atoi(name)
+	if err != nil {
+		return false
+	}
+	if i >= 0 && i < len(b.b) {
+		b.b[i] = byte(v.Int())
+	}
+	return true
+}
@@ -0,0 +1,1079 @@
+//go:build !wasm && fakedom
+// +build !wasm,fakedom
+
+package app
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// fakeWindow is an in-memory implementation of the browser window, used by
+// test engines on non-wasm architectures. It tracks the DOM nodes, attributes,
+// texts, event listeners, history and storages, without layout nor rendering.
+//
+// Events are dispatched synchronously, including the popstate events triggered
+// by history navigation.
+type fakeWindow struct {
+	fakeValue
+
+	events         fakeEventTarget
+	document       *fakeNode
+	activeElement  *fakeNode
+	title          string
+	history        []fakeHistoryEntry
+	historyIndex   int
+	localStorage   fakeValue
+	sessionStorage fakeValue
+	width          int
+	height         int
+	cursorX        int
+	cursorY        int
+	opened         []string
+	body           UI
+	constructors   map[string]fakeValue
+}
+
+type fakeHistoryEntry struct {
+	url   *url.URL
+	state Value
+}
+
+func newFakeWindow() *fakeWindow {
+	w := &fakeWindow{
+		width:  1280,
+		height: 720,
+	}
+	w.fakeValue = fakeObjectOf(w)
+
+	u, _ := url.Parse("http://localhost/")
+	w.history = []fakeHistoryEntry{{url: u, state: fakeNull}}
+
+	w.document = newFakeNode(w, fakeDocumentNode)
+	html := newFakeElement(w, "html", "")
+	html.appendChild(newFakeElement(w, "head", ""))
+	body := newFakeElement(w, "body", "")
+	body.appendChild(newFakeElement(w, "div", ""))
+	html.appendChild(body)
+	w.document.appendChild(html)
+
+	w.localStorage = fakeObjectOf(&fakeStorage{})
+	w.sessionStorage = fakeObjectOf(&fakeStorage{})
+
+	w.constructors = map[string]fakeValue{}
+	for _, name := range []string{
+		"Event",
+		"CustomEvent",
+		"UIEvent",
+		"FocusEvent",
+		"MouseEvent",
+		"PointerEvent",
+		"KeyboardEvent",
+		"InputEvent",
+		"SubmitEvent",
+		"PopStateEvent",
+	} {
+		w.constructors[name] = w.eventConstructor(name)
+	}
+	w.constructors["Promise"] = fakePromiseConstructor()
+	w.constructors["Error"] = fakeErrorConstructor()
+	w.constructors["Object"] = fakeObjectConstructor()
+	w.constructors["Date"] = fakeDateConstructor()
+	w.constructors["Uint8Array"] = fakeUint8ArrayConstructor()
+	return w
+}
+
+func (w *fakeWindow) headElement() *fakeNode {
+	for _, c := range w.document.firstElementChild().children {
+		if c.isHTML() && c.tag == "head" {
+			return c
+		}
+	}
+	return nil
+}
+
+func (w *fakeWindow) bodyElement() *fakeNode {
+	for _, c := range w.document.firstElementChild().children {
+		if c.isHTML() && c.tag == "body" {
+			return c
+		}
+	}
+	return nil
+}
+
+func (w *fakeWindow) currentURL() *url.URL {
+	u := *w.history[w.historyIndex].url
+	return &u
+}
+
+func (w *fakeWindow) URL() *url.URL {
+	return w.currentURL()
+}
+
+func (w *fakeWindow) Size() (width, height int) {
+	return w.width, w.height
+}
+
+func (w *fakeWindow) CursorPosition() (x, y int) {
+	return w.cursorX, w.cursorY
+}
+
+func (w *fakeWindow) setCursorPosition(x, y int) {
+	w.cursorX = x
+	w.cursorY = y
+}
+
+func (w *fakeWindow) GetElementByID(id string) Value {
+	return w.document.getElementByID(id).jsValue()
+}
+
+func (w *fakeWindow) ScrollToID(id string) {
+}
+
+func (w *fakeWindow) setBody(body UI) {
+	w.body = body
+}
+
+func (w *fakeWindow) createElement(tag, xmlns string) (Value, error) {
+	return newFakeElement(w, tag, xmlns).jsValue(), nil
+}
+
+func (w *fakeWindow) createTextNode(v string) Value {
+	return newFakeText(w, v).jsValue()
+}
+
+func (w *fakeWindow) addHistory(u *url.URL) {
+	current := w.currentURL()
+	u.Scheme = current.Scheme
+	u.Host = current.Host
+	w.pushState(fakeNull, u.String())
+}
+
+func (w *fakeWindow) replaceHistory(u *url.URL) {
+	current := w.currentURL()
+	u.Scheme = current.Scheme
+	u.Host = current.Host
+	w.replaceState(fakeNull, u.String())
+}
+
+// resolveURL resolves the given URL against the current one.
+func (w *fakeWindow) resolveURL(rawurl string) *url.URL {
+	u, err := w.currentURL().Parse(rawurl)
+	if err != nil {
+		return w.currentURL()
+	}
+	return u
+}
+
+func (w *fakeWindow) pushState(state Value, rawurl string) {
+	u := w.currentURL()
+	if rawurl != "" {
+		u = w.resolveURL(rawurl)
+	}
+
+	w.history = append(w.history[:w.historyIndex+1], fakeHistoryEntry{
+		url:   u,
+		state: state,
+	})
+	w.historyIndex = len(w.history) - 1
+}
+
+func (w *fakeWindow) replaceState(state Value, rawurl string) {
+	u := w.currentURL()
+	if rawurl != "" {
+		u = w.resolveURL(rawurl)
+	}
+
+	w.history[w.historyIndex] = fakeHistoryEntry{
+		url:   u,
+		state: state,
+	}
+}
+
+// goHistory moves through the history by the given offset, then dispatches a
+// popstate event.
+func (w *fakeWindow) goHistory(delta int) {
+	i := w.historyIndex + delta
+	if delta == 0 || i < 0 || i >= len(w.history) {
+		return
+	}
+	w.historyIndex = i
+
+	e := w.createEvent("PopStateEvent", "popstate", map[string]Value{
+		"state": w.history[i].state,
+	})
+	w.dispatchEvent(w, e)
+}
+
+func (w *fakeWindow) fakeGet(this fakeValue, name string) (Value, bool) {
+	if c, ok := w.constructors[name]; ok {
+		return c, true
+	}
+
+	switch name {
+	case "window", "self", "globalThis", "top", "parent":
+		return w.fakeValue, true
+
+	case "document":
+		return w.document.jsValue(), true
+
+	case "location":
+		return fakeObjectOf(fakeLocation{window: w}), true
+
+	case "history":
+		return fakeObjectOf(fakeHistory{window: w}), true
+
+	case "localStorage":
+		return w.localStorage, true
+
+	case "sessionStorage":
+		return w.sessionStorage, true
+
+	case "innerWidth", "outerWidth":
+		return fakeNumber(float64(w.width)), true
+
+	case "innerHeight", "outerHeight":
+		return fakeNumber(float64(w.height)), true
+
+	case "devicePixelRatio":
+		return fakeNumber(1), true
+
+	case "scrollX", "scrollY", "pageXOffset", "pageYOffset":
+		return fakeNumber(0), true
+
+	case "navigator":
+		return fakeMap(map[string]any{
+			"userAgent": "go-app",
+			"language":  "en-US",
+			"languages": []any{"en-US"},
+			"onLine":    true,
+		}), true
+
+	case "matchMedia":
+		return fakeMethod(func(args []Value) any {
+			noop := fakeMethod(func([]Value) any { return nil })
+			return fakeMap(map[string]any{
+				"matches":             false,
+				"media":               fakeToString(fakeArg(args, 0)),
+				"addEventListener":    noop,
+				"removeEventListener": noop,
+				"addListener":         noop,
+				"removeListener":      noop,
+			})
+		}), true
+
+	case "open":
+		return fakeMethod(func(args []Value) any {
+			w.opened = append(w.opened, fakeToString(fakeArg(args, 0)))
+			return nil
+		}), true
+
+	case "scrollTo", "scrollBy", "focus", "blur":
+		return fakeMethod(func(args []Value) any {
+			return nil
+		}), true
+
+	case "addEventListener":
+		return fakeMethod(func(args []Value) any {
+			w.events.add(fakeToString(fakeArg(args, 0)), fakeArg(args, 1), fakeArg(args, 2))
+			return nil
+		}), true
+
+	case "removeEventListener":
+		return fakeMethod(func(args []Value) any {
+			w.events.remove(fakeToString(fakeArg(args, 0)), fakeArg(args, 1), fakeArg(args, 2))
+			return nil
+		}), true
+
+	case "dispatchEvent":
+		return fakeMethod(func(args []Value) any {
+			return w.dispatchEvent(w, fakeEventOf(fakeArg(args, 0)))
+		}), true
+
+	default:
+		return nil, false
+	}
+}
+
+func (w *fakeWindow) fakeSet(name string, v Value) bool {
+	switch name {
+	case "location":
+		w.navigate(fakeToString(v))
+		return true
+
+	default:
+		return false
+	}
+}
+
+// navigate simulates the loading of the given URL.
+func (w *fakeWindow) navigate(rawurl string) {
+	w.pushState(fakeNull, w.resolveURL(rawurl).String())
+}
+
+// fakeLocation is the window.location object.
+type fakeLocation struct {
+	window *fakeWindow
+}
+
+func (l fakeLocation) fakeGet(this fakeValue, name string) (Value, bool) {
+	u := l.window.currentURL()
+
+	switch name {
+	case "href":
+		return fakeString(u.String()), true
+
+	case "protocol":
+		return fakeString(u.Scheme + ":"), true
+
+	case "host":
+		return fakeString(u.Host), true
+
+	case "hostname":
+		return fakeString(u.Hostname()), true
+
+	case "port":
+		return fakeString(u.Port()), true
+
+	case "origin":
+		return fakeString(u.Scheme + "://" + u.Host), true
+
+	case "pathname":
+		return fakeString(u.EscapedPath()), true
+
+	case "search":
+		if u.RawQuery == "" {
+			return fakeString(""), true
+		}
+		return fakeString("?" + u.RawQuery), true
+
+	case "hash":
+		if u.Fragment == "" {
+			return fakeString(""), true
+		}
+		return fakeString("#" + u.EscapedFragment()), true
+
+	case "assign":
+		return fakeMethod(func(args []Value) any {
+			l.window.navigate(fakeToString(fakeArg(args, 0)))
+			return nil
+		}), true
+
+	case "replace":
+		return fakeMethod(func(args []Value) any {
+			l.window.replaceState(fakeNull, l.window.resolveURL(fakeToString(fakeArg(args, 0))).String())
+			return nil
+		}), true
+
+	case "reload":
+		return fakeMethod(func(args []Value) any {
+			return nil
+		}), true
+
+	case "toString":
+		return fakeMethod(func(args []Value) any {
+			return u.String()
+		}), true
+
+	default:
+		return nil, false
+	}
+}
+
+func (l fakeLocation) fakeSet(name string, v Value) bool {
+	u := l.window.currentURL()
+	s := fakeToString(v)
+
+	switch name {
+	case "href":
+		l.window.navigate(s)
+		return true
+
+	case "pathname":
+		u.Path = "/" + strings.TrimPrefix(s, "/")
+		u.RawPath = ""
+
+	case "search":
+		u.RawQuery = strings.TrimPrefix(s, "?")
+
+	case "hash":
+		u.Fragment = strings.TrimPrefix(s, "#")
+		u.RawFragment = ""
+
+	default:
+		return false
+	}
+
+	l.window.navigate(u.String())
+	return true
+}
+
+// fakeHistory is the window.history object.
+type fakeHistory struct {
+	window *fakeWindow
+}
+
+func (h fakeHistory) fakeGet(this fakeValue, name string) (Value, bool) {
+	w := h.window
+
+	switch name {
+	case "length":
+		return fakeNumber(float64(len(w.history))), true
+
+	case "state":
+		return w.history[w.historyIndex].state, true
+
+	case "pushState":
+		return fakeMethod(func(args []Value) any {
+			w.pushState(fakeArg(args, 0), h.urlArg(args))
+			return nil
+		}), true
+
+	case "replaceState":
+		return fakeMethod(func(args []Value) any {
+			w.replaceState(fakeArg(args, 0), h.urlArg(args))
+			return nil
+		}), true
+
+	case "back":
+		return fakeMethod(func(args []Value) any {
+			w.goHistory(-1)
+			return nil
+		}), true
+
+	case "forward":
+		return fakeMethod(func(args []Value) any {
+			w.goHistory(1)
+			return nil
+		}), true
+
+	case "go":
+		return fakeMethod(func(args []Value) any {
+			w.goHistory(fakeArg(args, 0).Int())
+			return nil
+		}), true
+
+	default:
+		return nil, false
+	}
+}
+
+func (h fakeHistory) fakeSet(name string, v Value) bool {
+	return false
+}
+
+func (h fakeHistory) urlArg(args []Value) string {
+	u := fakeArg(args, 2)
+	if u.IsUndefined() || u.IsNull() {
+		return ""
+	}
+	return fakeToString(u)
+}
+
+// fakeStorage is a Web Storage object, such as window.localStorage.
+type fakeStorage struct {
+	keys  []string
+	items map[string]string
+}
+
+func (s *fakeStorage) setItem(k, v string) {
+	if s.items == nil {
+		s.items = make(map[string]string)
+	}
+	if _, ok := s.items[k]; !ok {
+		s.keys = append(s.keys, k)
+	}
+	s.items[k] = v
+}
+
+func (s *fakeStorage) removeItem(k string) {
+	if _, ok := s.items[k]; !ok {
+		return
+	}
+	delete(s.items, k)
+	for i, key := range s.keys {
+		if key == k {
+			s.keys = append(s.keys[:i:i], s.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *fakeStorage) fakeGet(this fakeValue, name string) (Value, bool) {
+	switch name {
+	case "length":
+		return fakeNumber(float64(len(s.keys))), true
+
+	case "getItem":
+		return fakeMethod(func(args []Value) any {
+			if v, ok := s.items[fakeToString(fakeArg(args, 0))]; ok {
+				return v
+			}
+			return nil
+		}), true
+
+	case "setItem":
+		return fakeMethod(func(args []Value) any {
+			s.setItem(fakeToString(fakeArg(args, 0)), fakeToString(fakeArg(args, 1)))
+			return nil
+		}), true
+
+	case "removeItem":
+		return fakeMethod(func(args []Value) any {
+			s.removeItem(fakeToString(fakeArg(args, 0)))
+			return nil
+		}), true
+
+	case "clear":
+		return fakeMethod(func(args []Value) any {
+			s.keys = nil
+			s.items = nil
+			return nil
+		}), true
+
+	case "key":
+		return fakeMethod(func(args []Value) any {
+			if i := fakeArg(args, 0).Int(); i >= 0 && i < len(s.keys) {
+				return s.keys[i]
+			}
+			return nil
+		}), true
+
+	default:
+		if v, ok := s.items[name]; ok {
+			return fakeString(v), true
+		}
+		return nil, false
+	}
+}
+
+func (s *fakeStorage) fakeSet(name string, v Value) bool {
+	s.setItem(name, fakeToString(v))
+	return true
+}
+
+// fakeTarget is the interface that describes an object that receives events.
+type fakeTarget interface {
+	targetValue() Value
+	targetEvents() *fakeEventTarget
+	targetParent() fakeTarget
+}
+
+// fakeTargetOf returns the event target that backs the given value, or nil when
+// the value is not an event target.
+func fakeTargetOf(v Value) fakeTarget {
+	fv, ok := v.(fakeValue)
+	if !ok || fv.obj == nil {
+		return nil
+	}
+
+	switch host := fv.obj.host.(type) {
+	case *fakeNode:
+		return host
+
+	case *fakeWindow:
+		return host
+
+	default:
+		return nil
+	}
+}
+
+func (n *fakeNode) targetValue() Value {
+	return n.jsValue()
+}
+
+func (n *fakeNode) targetEvents() *fakeEventTarget {
+	return &n.events
+}
+
+func (n *fakeNode) targetParent() fakeTarget {
+	if n.parent != nil {
+		return n.parent
+	}
+	if n.nodeType == fakeDocumentNode {
+		return n.window
+	}
+	return nil
+}
+
+func (w *fakeWindow) targetValue() Value {
+	return w.fakeValue
+}
+
+func (w *fakeWindow) targetEvents() *fakeEventTarget {
+	return &w.events
+}
+
+func (w *fakeWindow) targetParent() fakeTarget {
+	return nil
+}
+
+// fakeEventTarget stores the event listeners of an event target.
+type fakeEventTarget struct {
+	listeners map[string][]*fakeListener
+}
+
+type fakeListener struct {
+	fn      Value
+	capture bool
+	once    bool
+	passive bool
+	removed bool
+}
+
+func (t *fakeEventTarget) add(event string, fn Value, options Value) {
+	if fn == nil || fn.Type() != TypeFunction {
+		return
+	}
+
+	l := &fakeListener{fn: fn}
+	switch options.Type() {
+	case TypeBoolean:
+		l.capture = options.Bool()
+
+	case TypeObject:
+		l.capture = options.Get("capture").Truthy()
+		l.once = options.Get("once").Truthy()
+		l.passive = options.Get("passive").Truthy()
+	}
+
+	for _, listener := range t.listeners[event] {
+		if fakeSame(listener.fn, fn) && listener.capture == l.capture {
+			return
+		}
+	}
+
+	if t.listeners == nil {
+		t.listeners = make(map[string][]*fakeListener)
+	}
+	t.listeners[event] = append(t.listeners[event], l)
+}
+
+func (t *fakeEventTarget) remove(event string, fn Value, options Value) {
+	capture := options.Truthy()
+	if options.Type() == TypeObject {
+		capture = options.Get("capture").Truthy()
+	}
+
+	listeners := t.listeners[event]
+	for i, l := range listeners {
+		if fakeSame(l.fn, fn) && l.capture == capture {
+			l.removed = true
+			t.listeners[event] = append(listeners[:i:i], listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// count returns the number of listeners for the given event.
+func (t *fakeEventTarget) count(event string) int {
+	return len(t.listeners[event])
+}
+
+const (
+	fakeCapturingPhase = 1
+	fakeAtTargetPhase  = 2
+	fakeBubblingPhase  = 3
+)
+
+// fakeEvent is a DOM event.
+type fakeEvent struct {
+	object            *fakeObject
+	typ               string
+	bubbles           bool
+	cancelable        bool
+	defaultPrevented  bool
+	stopped           bool
+	stoppedImmediate  bool
+	passive           bool
+	phase             int
+	target            Value
+	currentTarget     Value
+	dispatching       bool
+	composedPathItems []Value
+}
+
+// fakeEventDefaults are the default properties of events, indexed by
+// constructor name.
+var fakeEventDefaults = map[string]map[string]any{
+	"CustomEvent": {
+		"detail": nil,
+	},
+	"UIEvent": {
+		"detail": 0,
+	},
+	"FocusEvent": {
+		"detail":        0,
+		"relatedTarget": nil,
+	},
+	"MouseEvent": {
+		"detail":        0,
+		"button":        0,
+		"buttons":       0,
+		"clientX":       0,
+		"clientY":       0,
+		"screenX":       0,
+		"screenY":       0,
+		"altKey":        false,
+		"ctrlKey":       false,
+		"metaKey":       false,
+		"shiftKey":      false,
+		"relatedTarget": nil,
+	},
+	"KeyboardEvent": {
+		"key":         "",
+		"code":        "",
+		"location":    0,
+		"repeat":      false,
+		"isComposing": false,
+		"altKey":      false,
+		"ctrlKey":     false,
+		"metaKey":     false,
+		"shiftKey":    false,
+	},
+	"InputEvent": {
+		"data":        nil,
+		"inputType":   "",
+		"isComposing": false,
+	},
+	"SubmitEvent": {
+		"submitter": nil,
+	},
+	"PopStateEvent": {
+		"state": nil,
+	},
+}
+
+func init() {
+	fakeEventDefaults["PointerEvent"] = fakeEventDefaults["MouseEvent"]
+}
+
+// eventConstructor returns a constructor that creates events with the given
+// class.
+func (w *fakeWindow) eventConstructor(name string) fakeValue {
+	ctor := fakeFunc(func(this Value, args []Value) any {
+		return nil
+	})
+	ctor.obj.ctor = func(args []Value) Value {
+		props := make(map[string]Value)
+		bubbles := false
+		cancelable := false
+
+		if init := fakeArg(args, 1); init.Type() == TypeObject {
+			if fi, ok := init.(fakeValue); ok {
+				for k, v := range fi.obj.props {
+					props[k] = v
+				}
+			}
+			bubbles = init.Get("bubbles").Truthy()
+			cancelable = init.Get("cancelable").Truthy()
+		}
+		delete(props, "bubbles")
+		delete(props, "cancelable")
+		delete(props, "composed")
+
+		e := w.createEvent(name, fakeToString(fakeArg(args, 0)), props)
+		e.bubbles = bubbles
+		e.cancelable = cancelable
+		return fakeValue{typ: TypeObject, obj: e.object}
+	}
+	return ctor
+}
+
+// createEvent creates an event with the given constructor name and type. The
+// event does not bubble and is not cancelable.
+func (w *fakeWindow) createEvent(name, typ string, props map[string]Value) *fakeEvent {
+	e := &fakeEvent{
+		typ:           typ,
+		target:        fakeNull,
+		currentTarget: fakeNull,
+	}
+	e.object = &fakeObject{
+		host:  e,
+		class: w.constructors[name].obj,
+		props: make(map[string]Value),
+	}
+
+	for k, v := range fakeEventDefaults[name] {
+		e.object.props[k] = fakeValueOf(v)
+	}
+	for k, v := range props {
+		e.object.props[k] = v
+	}
+	return e
+}
+
+// fakeEventOf returns the event that backs the given value.
+func fakeEventOf(v Value) *fakeEvent {
+	if fv, ok := v.(fakeValue); ok && fv.obj != nil {
+		if e, ok := fv.obj.host.(*fakeEvent); ok {
+			return e
+		}
+	}
+	panic(errors.New("javascript value is not an event").
+		WithTag("type", v.Type()))
+}
+
+func (e *fakeEvent) jsValue() Value {
+	return fakeValue{typ: TypeObject, obj: e.object}
+}
+
+func (e *fakeEvent) fakeGet(this fakeValue, name string) (Value, bool) {
+	switch name {
+	case "type":
+		return fakeString(e.typ), true
+
+	case "bubbles":
+		return fakeBool(e.bubbles), true
+
+	case "cancelable":
+		return fakeBool(e.cancelable), true
+
+	case "defaultPrevented":
+		return fakeBool(e.defaultPrevented), true
+
+	case "eventPhase":
+		return fakeNumber(float64(e.phase)), true
+
+	case "target", "srcElement":
+		return e.target, true
+
+	case "currentTarget":
+		return e.currentTarget, true
+
+	case "isTrusted":
+		return fakeBool(false), true
+
+	case "timeStamp":
+		return fakeNumber(0), true
+
+	case "preventDefault":
+		return fakeMethod(func(args []Value) any {
+			if e.cancelable && !e.passive {
+				e.defaultPrevented = true
+			}
+			return nil
+		}), true
+
+	case "stopPropagation":
+		return fakeMethod(func(args []Value) any {
+			e.stopped = true
+			return nil
+		}), true
+
+	case "stopImmediatePropagation":
+		return fakeMethod(func(args []Value) any {
+			e.stopped = true
+			e.stoppedImmediate = true
+			return nil
+		}), true
+
+	case "composedPath":
+		return fakeMethod(func(args []Value) any {
+			return fakeArray(e.composedPathItems...)
+		}), true
+
+	default:
+		return nil, false
+	}
+}
+
+func (e *fakeEvent) fakeSet(name string, v Value) bool {
+	switch name {
+	case "type", "bubbles", "cancelable", "defaultPrevented", "eventPhase",
+		"target", "srcElement", "currentTarget", "isTrusted", "timeStamp":
+		return true
+
+	default:
+		return false
+	}
+}
+
+// dispatchEvent dispatches the given event to the target and its ancestors. It
+// returns false when the event is cancelable and its default action has been
+// prevented.
+func (w *fakeWindow) dispatchEvent(target fakeTarget, e *fakeEvent) bool {
+	if e.dispatching {
+		panic(errors.New("dispatching event failed").
+			WithTag("event", e.typ).
+			Wrap(errors.New("event is already being dispatched")))
+	}
+	e.dispatching = true
+	e.stopped = false
+	e.stoppedImmediate = false
+	e.target = target.targetValue()
+
+	var path []fakeTarget
+	for t := target; t != nil; t = t.targetParent() {
+		path = append(path, t)
+	}
+	e.composedPathItems = make([]Value, len(path))
+	for i, t := range path {
+		e.composedPathItems[i] = t.targetValue()
+	}
+
+	for i := len(path) - 1; i > 0 && !e.stopped; i-- {
+		e.invoke(path[i], fakeCapturingPhase)
+	}
+	if !e.stopped {
+		e.invoke(path[0], fakeAtTargetPhase)
+	}
+	for i := 1; i < len(path) && e.bubbles && !e.stopped; i++ {
+		e.invoke(path[i], fakeBubblingPhase)
+	}
+
+	e.phase = 0
+	e.currentTarget = fakeNull
+	e.passive = false
+	e.dispatching = false
+	return !e.defaultPrevented
+}
+
+func (e *fakeEvent) invoke(t fakeTarget, phase int) {
+	e.phase = phase
+	e.currentTarget = t.targetValue()
+
+	events := t.targetEvents()
+	listeners := append([]*fakeListener(nil), events.listeners[e.typ]...)
+	for _, l := range listeners {
+		if l.removed ||
+			phase == fakeCapturingPhase && !l.capture ||
+			phase == fakeBubblingPhase && l.capture {
+			continue
+		}
+
+		if l.once {
+			events.remove(e.typ, l.fn, fakeBool(l.capture))
+		}
+
+		e.passive = l.passive
+		fakeCall(l.fn, e.currentTarget, e.jsValue())
+		e.passive = false
+
+		if e.stoppedImmediate {
+			return
+		}
+	}
+
+	if phase == fakeCapturingPhase {
+		return
+	}
+	if h := e.currentTarget.Get("on" + e.typ); h.Type() == TypeFunction {
+		if r := fakeCall(h, e.currentTarget, e.jsValue()); r.Type() == TypeBoolean && !r.Bool() && e.cancelable {
+			e.defaultPrevented = true
+		}
+	}
+}
+
+// newEvent creates an event with the given constructor name and type.
+func (w *fakeWindow) newEvent(class, typ string, bubbles, cancelable bool, props map[string]any) *fakeEvent {
+	values := make(map[string]Value, len(props))
+	for k, v := range props {
+		values[k] = fakeValueOf(v)
+	}
+
+	e := w.createEvent(class, typ, values)
+	e.bubbles = bubbles
+	e.cancelable = cancelable
+	return e
+}
+
+// focus gives the focus to the given element when it is focusable.
+func (w *fakeWindow) focus(n *fakeNode) {
+	if !n.focusable() || w.activeElement == n {
+		return
+	}
+
+	previous := w.activeElement
+	w.activeElement = nil
+	if previous != nil {
+		w.dispatchEvent(previous, w.newEvent("FocusEvent", "blur", false, false, map[string]any{
+			"relatedTarget": n.jsValue(),
+		}))
+		w.dispatchEvent(previous, w.newEvent("FocusEvent", "focusout", true, false, map[string]any{
+			"relatedTarget": n.jsValue(),
+		}))
+	}
+
+	w.activeElement = n
+	w.dispatchEvent(n, w.newEvent("FocusEvent", "focus", false, false, map[string]any{
+		"relatedTarget": previous.jsValue(),
+	}))
+	w.dispatchEvent(n, w.newEvent("FocusEvent", "focusin", true, false, map[string]any{
+		"relatedTarget": previous.jsValue(),
+	}))
+}
+
+// blur removes the focus from the given element when it has it.
+func (w *fakeWindow) blur(n *fakeNode) {
+	if w.activeElement != n {
+		return
+	}
+
+	w.activeElement = nil
+	w.dispatchEvent(n, w.newEvent("FocusEvent", "blur", false, false, nil))
+	w.dispatchEvent(n, w.newEvent("FocusEvent", "focusout", true, false, nil))
+}
+
+// click dispatches a click event on the given element, then performs its
+// default action: toggling checkboxes and radio buttons, or submitting forms.
+func (w *fakeWindow) click(n *fakeNode) {
+	if n.isDisabled() {
+		return
+	}
+
+	inputType := n.inputType()
+	toggles := inputType == "checkbox" || inputType == "radio"
+	checked := n.isChecked()
+	if toggles {
+		n.setChecked(inputType == "radio" || !checked)
+	}
+
+	e := w.newEvent("MouseEvent", "click", true, true, map[string]any{
+		"detail": 1,
+	})
+	if !w.dispatchEvent(n, e) {
+		if toggles {
+			n.setChecked(checked)
+		}
+		return
+	}
+
+	switch {
+	case toggles:
+		if n.isChecked() != checked {
+			w.dispatchEvent(n, w.newEvent("InputEvent", "input", true, false, nil))
+			w.dispatchEvent(n, w.newEvent("Event", "change", true, false, nil))
+		}
+
+	case n.isSubmitter():
+		if f := n.form(); f != nil {
+			w.submit(f, n)
+		}
+	}
+}
+
+// isSubmitter reports whether the element submits its form when clicked.
+func (n *fakeNode) isSubmitter() bool {
+	switch n.tag {
+	case "button":
+		t, _ := n.attr("type")
+		t = strings.ToLower(t)
+		return t == "" || t == "submit"
+
+	case "input":
+		t := n.inputType()
+		return t == "submit" || t == "image"
+	}
+	return false
+}
+
+// submit dispatches a submit event on the given form.
+func (w *fakeWindow) submit(form, submitter *fakeNode) {
+	w.dispatchEvent(form, w.newEvent("SubmitEvent", "submit", true, true, map[string]any{
+		"submitter": submitter.jsValue(),
+	}))
+}
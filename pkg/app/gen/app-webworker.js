@@ -0,0 +1,47 @@
+// -----------------------------------------------------------------------------
+// go-app Web Worker
+// -----------------------------------------------------------------------------
+var goappWorkerMessages = [];
+var goappOnWorkerMessage = function (msg) {
+  goappWorkerMessages.push(msg);
+};
+
+const goappEnv = {{.Env}};
+
+self.addEventListener("message", (event) => {
+  goappOnWorkerMessage(event.data);
+});
+
+importScripts("{{.WasmExecJS}}");
+goappInitWebAssembly();
+
+// -----------------------------------------------------------------------------
+// Environment
+// -----------------------------------------------------------------------------
+function goappGetenv(k) {
+  return goappEnv[k];
+}
+
+// -----------------------------------------------------------------------------
+// Web Assembly
+// -----------------------------------------------------------------------------
+async function goappInitWebAssembly() {
+  let instantiateStreaming = WebAssembly.instantiateStreaming;
+  if (!instantiateStreaming) {
+    instantiateStreaming = async (resp, importObject) => {
+      const source = await (await resp).arrayBuffer();
+      return await WebAssembly.instantiate(source, importObject);
+    };
+  }
+
+  try {
+    const go = new Go();
+    const wasm = await instantiateStreaming(
+      fetch("{{.Wasm}}"),
+      go.importObject
+    );
+    go.run(wasm.instance);
+  } catch (err) {
+    console.error("loading wasm in web worker failed: ", err);
+  }
+}
@@ -1,8 +1,13 @@
 // -----------------------------------------------------------------------------
 // PWA
 // -----------------------------------------------------------------------------
-const cacheName = "app-" + "{{.Version}}";
+const cacheScope = "{{.CacheScope}}";
+const cacheName = "app-" + "{{.Version}}" + (cacheScope ? "#" + cacheScope : "");
 const resourcesToCache = {{.ResourcesToCache}};
+const resourcesIntegrity = {{.ResourcesIntegrity}};
+const shareTargetAction = "{{.ShareTargetAction}}";
+const sharedDataCacheName =
+  "goapp-shared-data" + (cacheScope ? "#" + cacheScope : "");
 
 self.addEventListener("install", (event) => {
   console.log("installing app worker {{.Version}}");
@@ -11,7 +16,11 @@ self.addEventListener("install", (event) => {
 
 async function installWorker() {
   const cache = await caches.open(cacheName);
-  await cache.addAll(resourcesToCache);
+  await cache.addAll(
+    resourcesToCache.map(
+      (url) => new Request(url, { integrity: resourcesIntegrity[url] || "" })
+    )
+  );
   await self.skipWaiting(); // Use this new service worker
 }
 
@@ -23,7 +32,8 @@ self.addEventListener("activate", (event) => {
 async function deletePreviousCaches() {
   keys = await caches.keys();
   keys.forEach(async (key) => {
-    if (key != cacheName) {
+    const scope = key.includes("#") ? key.slice(key.indexOf("#") + 1) : "";
+    if (key.startsWith("app-") && key != cacheName && scope == cacheScope) {
       console.log("deleting", key, "cache");
       await caches.delete(key);
     }
@@ -31,6 +41,10 @@ async function deletePreviousCaches() {
 }
 
 self.addEventListener("fetch", (event) => {
+  if (isShareTargetRequest(event.request)) {
+    event.respondWith(receiveSharedData(event.request));
+    return;
+  }
   event.respondWith(fetchWithCache(event.request));
 });
 
@@ -42,6 +56,68 @@ async function fetchWithCache(request) {
   return fetch(request);
 }
 
+// -----------------------------------------------------------------------------
+// Share Target
+// -----------------------------------------------------------------------------
+function isShareTargetRequest(request) {
+  return (
+    shareTargetAction &&
+    request.method === "POST" &&
+    new URL(request.url).pathname === shareTargetAction
+  );
+}
+
+// The shared data is stored in the Cache API since the browser can stop the
+// worker before the page asks for it.
+function sharedDataKey() {
+  return new URL(shareTargetAction + "?goapp-shared-data", self.location).href;
+}
+
+async function receiveSharedData(request) {
+  const form = await request.formData();
+  const cache = await caches.open(sharedDataCacheName);
+  await cache.put(sharedDataKey(), new Response(form));
+  return Response.redirect(shareTargetAction + "?goapp-shared=1", 303);
+}
+
+self.addEventListener("message", (event) => {
+  const msg = event.data && event.data.goapp;
+  if (!msg || msg.type !== "share-ready") {
+    return;
+  }
+  event.waitUntil(sendSharedData(event.source));
+});
+
+async function sendSharedData(client) {
+  const cache = await caches.open(sharedDataCacheName);
+  const response = await cache.match(sharedDataKey());
+  if (!response) {
+    return;
+  }
+  await cache.delete(sharedDataKey());
+
+  const form = await response.formData();
+  const files = [];
+  for (const [name, value] of form.entries()) {
+    if (value instanceof File) {
+      files.push(value);
+    }
+  }
+
+  client.postMessage({
+    goapp: {
+      type: "share",
+      data: {
+        title: form.get("title") || "",
+        text: form.get("text") || "",
+        url: form.get("url") || "",
+        files: files,
+        opened: false,
+      },
+    },
+  });
+}
+
 // -----------------------------------------------------------------------------
 // Push Notifications
 // -----------------------------------------------------------------------------
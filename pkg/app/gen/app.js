@@ -13,10 +13,16 @@ var goappOnAppInstallChange = function () {
   goappAppInstallChangedBeforeWasmLoaded = true;
 };
 
+var goappSharedBeforeWasmLoaded = [];
+var goappOnShare = function (data) {
+  goappSharedBeforeWasmLoaded.push(data);
+};
+
 const goappEnv = {{.Env}};
 const goappLoadingLabel = "{{.LoadingLabel}}";
 const goappWasmContentLength = "{{.WasmContentLength}}";
 const goappWasmContentLengthHeader = "{{.WasmContentLengthHeader}}";
+const goappShareTargetAction = "{{.ShareTargetAction}}";
 
 let goappServiceWorkerRegistration;
 let deferredPrompt = null;
@@ -24,6 +30,8 @@ let deferredPrompt = null;
 goappInitServiceWorker();
 goappWatchForUpdate();
 goappWatchForInstallable();
+goappWatchForShare();
+goappWatchForLaunchedFiles();
 goappInitWebAssembly();
 
 // -----------------------------------------------------------------------------
@@ -104,6 +112,73 @@ async function goappShowInstallPrompt() {
   deferredPrompt = null;
 }
 
+// -----------------------------------------------------------------------------
+// Share Target and File Handlers
+// -----------------------------------------------------------------------------
+function goappWatchForShare() {
+  if (
+    !goappShareTargetAction ||
+    window.location.pathname !== goappShareTargetAction
+  ) {
+    return;
+  }
+
+  const params = new URLSearchParams(window.location.search);
+  if (!params.has("goapp-shared")) {
+    if (params.has("title") || params.has("text") || params.has("url")) {
+      goappOnShare({
+        title: params.get("title") || "",
+        text: params.get("text") || "",
+        url: params.get("url") || "",
+        files: [],
+        opened: false,
+      });
+    }
+    return;
+  }
+
+  if (!("serviceWorker" in navigator)) {
+    return;
+  }
+  navigator.serviceWorker.addEventListener("message", (event) => {
+    const msg = event.data && event.data.goapp;
+    if (!msg || msg.type !== "share") {
+      return;
+    }
+    goappOnShare(msg.data);
+  });
+  navigator.serviceWorker.ready.then((registration) => {
+    registration.active.postMessage({
+      goapp: {
+        type: "share-ready",
+      },
+    });
+  });
+}
+
+function goappWatchForLaunchedFiles() {
+  if (!("launchQueue" in window)) {
+    return;
+  }
+
+  window.launchQueue.setConsumer(async (launchParams) => {
+    if (!launchParams.files || !launchParams.files.length) {
+      return;
+    }
+
+    const files = await Promise.all(
+      launchParams.files.map((handle) => handle.getFile())
+    );
+    goappOnShare({
+      title: "",
+      text: "",
+      url: "",
+      files: files,
+      opened: true,
+    });
+  });
+}
+
 // -----------------------------------------------------------------------------
 // Environment
 // -----------------------------------------------------------------------------
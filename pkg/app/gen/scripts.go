@@ -47,6 +47,10 @@ func main() {
 			Var:      "appJS",
 			Filename: "gen/app.js",
 		},
+		{
+			Var:      "appWebWorkerJS",
+			Filename: "gen/app-webworker.js",
+		},
 		{
 			Var:      "manifestJSON",
 			Filename: "gen/manifest.webmanifest",
@@ -0,0 +1,500 @@
+//go:build !wasm
+// +build !wasm
+
+package app
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// The host of the requests served by test handlers.
+const testHandlerHost = "example.com"
+
+// TestHandler encapsulates the methods required to verify a Handler end to
+// end without a browser. Requests are served in-process by the handler, and
+// pages are verified from their prerendered HTML. Test handlers are not
+// available on wasm.
+type TestHandler interface {
+	// Get serves a GET request for the given URL with the handler and returns
+	// the recorded response. It returns an error when the handler panics.
+	Get(rawURL string) (*http.Response, error)
+
+	// CheckPage fetches the page served at the given URL and verifies that it
+	// is a prerendered HTML document that links the app manifest and loads
+	// the app scripts, and that those resources are served.
+	CheckPage(rawURL string) error
+
+	// CheckManifest fetches the web app manifest and verifies that it is a
+	// JSON document that defines the app name, scope, start URL and icons.
+	CheckManifest() error
+
+	// CheckServiceWorker fetches the service worker script and verifies that
+	// it is bound to the handler version and lists the resources to cache.
+	CheckServiceWorker() error
+
+	// CheckCachedResources fetches the resources cached by the service worker
+	// and verifies that they are served by the handler, with content matching
+	// their integrity hash when one is set. Remote resources are not fetched.
+	CheckCachedResources() error
+
+	// CheckRoutes fetches the pages of the paths registered with Route, and of
+	// the local URLs listed by the routed components that implement
+	// SitemapEnumerator, and verifies that they render without error. Routes
+	// registered with RouteWithRegexp are not fetched otherwise.
+	CheckRoutes() error
+
+	// CheckLinks fetches the pages checked by CheckRoutes and verifies that
+	// the local URLs of their A().Href values are served by the handler.
+	// Links to other hosts and non-HTTP links are not followed.
+	CheckLinks() error
+
+	// Check runs CheckManifest, CheckServiceWorker, CheckCachedResources and
+	// CheckLinks, which also checks routes, and returns the first error.
+	Check() error
+}
+
+// NewTestHandler creates and returns a test handler that verifies the given
+// handler with the routes registered with Route and RouteWithRegexp.
+func NewTestHandler(h *Handler) TestHandler {
+	return &testHandler{
+		handler: h,
+		routes:  &routes,
+	}
+}
+
+type testHandler struct {
+	handler *Handler
+	routes  *router
+}
+
+func (t *testHandler) Get(rawURL string) (res *http.Response, err error) {
+	if !strings.HasPrefix(rawURL, "/") {
+		return nil, errors.New("url is not an absolute path").WithTag("url", rawURL)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("serving request panicked").
+				WithTag("url", rawURL).
+				WithTag("panic", fmt.Sprint(r))
+		}
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, rawURL, nil)
+	req.Host = testHandlerHost
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+func (t *testHandler) CheckPage(rawURL string) error {
+	_, err := t.checkPage(rawURL)
+	return err
+}
+
+func (t *testHandler) checkPage(rawURL string) (*html.Node, error) {
+	body, err := t.fetch(rawURL, "text/html")
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.New("parsing page failed").
+			WithTag("url", rawURL).
+			Wrap(err)
+	}
+	if findHTMLNode(doc, atom.Body) == nil {
+		return nil, errors.New("page does not have a body").WithTag("url", rawURL)
+	}
+
+	manifest := ""
+	var scripts []string
+	walkHTMLNodes(doc, func(n *html.Node) {
+		switch {
+		case n.DataAtom == atom.Link && htmlNodeAttr(n, "rel") == "manifest":
+			manifest = htmlNodeAttr(n, "href")
+
+		case n.DataAtom == atom.Script && htmlNodeAttr(n, "src") != "":
+			scripts = append(scripts, htmlNodeAttr(n, "src"))
+		}
+	})
+
+	if manifest == "" {
+		return nil, errors.New("page does not link the app manifest").WithTag("url", rawURL)
+	}
+	if !containsSuffix(scripts, "/wasm_exec.js") || !containsSuffix(scripts, "/app.js") {
+		return nil, errors.New("page does not load the app scripts").
+			WithTag("url", rawURL).
+			WithTag("scripts", scripts)
+	}
+
+	for _, resource := range append([]string{manifest}, scripts...) {
+		if remoteLocation(resource) {
+			continue
+		}
+		if _, err := t.fetch(resource, ""); err != nil {
+			return nil, errors.New("page resource is not served").
+				WithTag("url", rawURL).
+				Wrap(err)
+		}
+	}
+	return doc, nil
+}
+
+func (t *testHandler) CheckManifest() error {
+	body, err := t.fetch("/manifest.webmanifest", "application/manifest+json")
+	if err != nil {
+		return err
+	}
+
+	var manifest struct {
+		Name      string `json:"name"`
+		ShortName string `json:"short_name"`
+		Scope     string `json:"scope"`
+		StartURL  string `json:"start_url"`
+		Icons     []struct {
+			Src string `json:"src"`
+		} `json:"icons"`
+	}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return errors.New("decoding manifest failed").Wrap(err)
+	}
+
+	switch {
+	case manifest.Name == "" && manifest.ShortName == "":
+		return errors.New("manifest does not define the app name")
+
+	case manifest.Scope == "":
+		return errors.New("manifest does not define the app scope")
+
+	case manifest.StartURL == "":
+		return errors.New("manifest does not define the app start url")
+
+	case len(manifest.Icons) == 0:
+		return errors.New("manifest does not define app icons")
+	}
+
+	for _, icon := range manifest.Icons {
+		if icon.Src == "" {
+			return errors.New("manifest defines an icon without source")
+		}
+	}
+	return nil
+}
+
+func (t *testHandler) CheckServiceWorker() error {
+	body, err := t.fetch("/app-worker.js", "application/javascript")
+	if err != nil {
+		return err
+	}
+
+	h := t.handler
+	if !bytes.Contains(body, []byte(h.Version)) {
+		return errors.New("service worker is not bound to the handler version").
+			WithTag("version", h.Version)
+	}
+
+	for _, resource := range t.cacheableResources() {
+		if u := jsonString(h.Resources.Resolve(resource)); !bytes.Contains(body, []byte(u)) {
+			return errors.New("service worker does not cache resource").
+				WithTag("resource", resource)
+		}
+	}
+	return nil
+}
+
+func (t *testHandler) CheckCachedResources() error {
+	resources := t.cacheableResources()
+	integrity := t.handler.cacheableResources(t.handler.defaultVariant)
+
+	for _, resource := range resources {
+		if remoteLocation(resource) {
+			continue
+		}
+
+		body, err := t.fetch(resource, "")
+		if err != nil {
+			return errors.New("cached resource is not served").Wrap(err)
+		}
+
+		if integrity := integrity[resource]; integrity != "" && !matchIntegrity(integrity, body) {
+			return errors.New("cached resource does not match its integrity").
+				WithTag("resource", resource).
+				WithTag("integrity", integrity)
+		}
+	}
+	return nil
+}
+
+func (t *testHandler) CheckRoutes() error {
+	_, err := t.checkRoutes()
+	return err
+}
+
+func (t *testHandler) checkRoutes() (map[string]*html.Node, error) {
+	pages := make(map[string]*html.Node)
+	failures := make(map[string]string)
+
+	for _, path := range t.routePaths() {
+		doc, err := t.checkPage(path)
+		if err != nil {
+			failures[path] = err.Error()
+			continue
+		}
+		pages[path] = doc
+	}
+
+	if len(failures) != 0 {
+		return nil, errors.New("rendering routes failed").WithTag("routes", failures)
+	}
+	return pages, nil
+}
+
+func (t *testHandler) CheckLinks() error {
+	pages, err := t.checkRoutes()
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(pages))
+	for path := range pages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	served := make(map[string]bool)
+	broken := make(map[string][]string)
+	for _, path := range paths {
+		base, _ := url.Parse(path)
+
+		walkHTMLNodes(pages[path], func(n *html.Node) {
+			if n.DataAtom != atom.A {
+				return
+			}
+
+			href, ok := htmlNodeAttrOK(n, "href")
+			if !ok {
+				return
+			}
+
+			link, ok := t.localLink(base, href)
+			if !ok {
+				return
+			}
+
+			ok, checked := served[link]
+			if !checked {
+				_, err := t.fetch(link, "")
+				ok = err == nil
+				served[link] = ok
+			}
+			if !ok {
+				broken[path] = append(broken[path], href)
+			}
+		})
+	}
+
+	if len(broken) != 0 {
+		return errors.New("broken links found").WithTag("links", broken)
+	}
+	return nil
+}
+
+func (t *testHandler) Check() error {
+	checks := []func() error{
+		t.CheckManifest,
+		t.CheckServiceWorker,
+		t.CheckCachedResources,
+		t.CheckLinks,
+	}
+
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetch serves a GET request for the given URL and returns the body of the
+// response. It returns an error when the response status is not 200 or when
+// its content type does not start with the given content type.
+func (t *testHandler) fetch(rawURL, contentType string) ([]byte, error) {
+	res, err := t.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status code").
+			WithTag("url", rawURL).
+			WithTag("status", res.StatusCode)
+	}
+
+	if ct := res.Header.Get("Content-Type"); !strings.HasPrefix(ct, contentType) {
+		return nil, errors.New("unexpected content type").
+			WithTag("url", rawURL).
+			WithTag("expected", contentType).
+			WithTag("content-type", ct)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.New("reading response body failed").
+			WithTag("url", rawURL).
+			Wrap(err)
+	}
+	return body, nil
+}
+
+func (t *testHandler) cacheableResources() []string {
+	h := t.handler
+	h.once.Do(h.init)
+
+	resources := make([]string, 0)
+	for resource := range h.cacheableResources(h.defaultVariant) {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+func (t *testHandler) routePaths() []string {
+	var paths []string
+	t.routes.forEach(func(path string, isPattern bool, newComponent func() Composer) {
+		if enumerator, ok := newComponent().(SitemapEnumerator); ok {
+			for _, u := range enumerator.SitemapURLs() {
+				if u.Loc != "" && !remoteLocation(u.Loc) {
+					paths = append(paths, u.Loc)
+				}
+			}
+			return
+		}
+
+		if !isPattern {
+			paths = append(paths, path)
+		}
+	})
+	return paths
+}
+
+// localLink returns the URL, relative to the handler, of the given link
+// found on the page at the given base URL. It reports false when the link
+// targets another host or is not an HTTP link.
+func (t *testHandler) localLink(base *url.URL, href string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return href, true
+	}
+
+	switch u.Scheme {
+	case "", "http", "https":
+
+	default:
+		return "", false
+	}
+
+	domain := strings.TrimRight(t.handler.Domain, "/")
+	if u.Host != "" && u.Host != testHandlerHost && u.Host != domain {
+		return "", false
+	}
+
+	u = base.ResolveReference(u)
+	if u.Path == base.Path && u.RawQuery == base.RawQuery {
+		return "", false
+	}
+	u.Scheme = ""
+	u.Host = ""
+	u.Fragment = ""
+	return u.String(), true
+}
+
+// matchIntegrity reports whether one of the hashes of the given integrity
+// attribute value matches the given content.
+func matchIntegrity(integrity string, b []byte) bool {
+	for _, hash := range strings.Fields(integrity) {
+		algorithm, digest, _ := strings.Cut(hash, "-")
+
+		var sum []byte
+		switch algorithm {
+		case "sha256":
+			s := sha256.Sum256(b)
+			sum = s[:]
+
+		case "sha384":
+			s := sha512.Sum384(b)
+			sum = s[:]
+
+		case "sha512":
+			s := sha512.Sum512(b)
+			sum = s[:]
+
+		default:
+			continue
+		}
+
+		if base64.StdEncoding.EncodeToString(sum) == digest {
+			return true
+		}
+	}
+	return false
+}
+
+func walkHTMLNodes(n *html.Node, fn func(*html.Node)) {
+	if n.Type == html.ElementNode {
+		fn(n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkHTMLNodes(c, fn)
+	}
+}
+
+func findHTMLNode(n *html.Node, a atom.Atom) *html.Node {
+	var found *html.Node
+	walkHTMLNodes(n, func(n *html.Node) {
+		if found == nil && n.DataAtom == a {
+			found = n
+		}
+	})
+	return found
+}
+
+func htmlNodeAttr(n *html.Node, name string) string {
+	v, _ := htmlNodeAttrOK(n, name)
+	return v
+}
+
+func htmlNodeAttrOK(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func containsSuffix(s []string, suffix string) bool {
+	for _, v := range s {
+		if strings.HasSuffix(v, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+//go:build !wasm
+// +build !wasm
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+type handlerTestLinksCompo struct {
+	Compo
+}
+
+func (c *handlerTestLinksCompo) Render() UI {
+	return Div().Body(
+		A().Href("/handler-test/about"),
+		A().Href("about#team"),
+		A().Href("https://go-app.dev/missing"),
+		A().Href("mailto:hello@go-app.dev"),
+		A().Href("/web/doc.txt"),
+	)
+}
+
+type handlerTestBrokenLinksCompo struct {
+	Compo
+}
+
+func (c *handlerTestBrokenLinksCompo) Render() UI {
+	return Div().Body(
+		A().Href("/handler-test/about"),
+		A().Href("/handler-test/missing"),
+		A().Href("/web/missing.txt"),
+	)
+}
+
+type handlerTestPanicCompo struct {
+	Compo
+}
+
+func (c *handlerTestPanicCompo) Render() UI {
+	panic("render failed")
+}
+
+func TestTestHandler(t *testing.T) {
+	Route("/handler-test/home", NewZeroComponentFactory(&handlerTestLinksCompo{}))
+	Route("/handler-test/about", NewZeroComponentFactory(&hello{}))
+	Route("/handler-test/broken", NewZeroComponentFactory(&handlerTestBrokenLinksCompo{}))
+	Route("/handler-test/panic", NewZeroComponentFactory(&handlerTestPanicCompo{}))
+	defer func() {
+		routes.mu.Lock()
+		defer routes.mu.Unlock()
+		for path := range routes.routes {
+			if strings.HasPrefix(path, "/handler-test/") {
+				delete(routes.routes, path)
+			}
+		}
+	}()
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer os.Chdir(wd)
+
+	webDir := "web"
+	require.NoError(t, os.MkdirAll(webDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(webDir, "app.wasm"), []byte("wasm"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(webDir, "main.css"), []byte("body {}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(webDir, "doc.txt"), []byte("doc"), 0644))
+
+	h := &Handler{
+		Name:                 "Handler Test",
+		Resources:            LocalDir(""),
+		Styles:               []string{"/web/main.css"},
+		SubresourceIntegrity: true,
+	}
+	th := NewTestHandler(h).(*testHandler)
+
+	t.Run("get returns the handler response", func(t *testing.T) {
+		res, err := th.Get("/handler-test/home")
+		require.NoError(t, err)
+		require.Equal(t, 200, res.StatusCode)
+
+		_, err = th.Get("handler-test/home")
+		require.Error(t, err)
+	})
+
+	t.Run("get reports panics", func(t *testing.T) {
+		_, err := th.Get("/handler-test/panic")
+		require.Error(t, err)
+		t.Log(err)
+	})
+
+	t.Run("check page", func(t *testing.T) {
+		require.NoError(t, th.CheckPage("/handler-test/home"))
+		require.Error(t, th.CheckPage("/handler-test/unknown"))
+		require.Error(t, th.CheckPage("/web/doc.txt"))
+	})
+
+	t.Run("check manifest", func(t *testing.T) {
+		require.NoError(t, th.CheckManifest())
+	})
+
+	t.Run("check service worker", func(t *testing.T) {
+		require.NoError(t, th.CheckServiceWorker())
+	})
+
+	t.Run("check cached resources", func(t *testing.T) {
+		require.NoError(t, th.CheckCachedResources())
+	})
+
+	t.Run("check cached resources reports integrity mismatches", func(t *testing.T) {
+		th := NewTestHandler(&Handler{
+			Resources: LocalDir(""),
+			Styles:    []string{"/web/main.css integrity=sha384-invalid"},
+		})
+		err := th.CheckCachedResources()
+		require.Error(t, err)
+		t.Log(err)
+	})
+
+	t.Run("check cached resources reports missing resources", func(t *testing.T) {
+		th := NewTestHandler(&Handler{
+			Resources:          LocalDir(""),
+			CacheableResources: []string{"/web/missing.png"},
+		})
+		err := th.CheckCachedResources()
+		require.Error(t, err)
+		t.Log(err)
+	})
+
+	t.Run("check routes", func(t *testing.T) {
+		routes := makeRouter()
+		routes.route("/handler-test/home", NewZeroComponentFactory(&handlerTestLinksCompo{}))
+		routes.route("/handler-test/about", NewZeroComponentFactory(&hello{}))
+		routes.routeWithRegexp("^/handler-test/users/.*$", NewZeroComponentFactory(&hello{}))
+		th.routes = &routes
+		require.NoError(t, th.CheckRoutes())
+		require.NoError(t, th.CheckLinks())
+		require.NoError(t, th.Check())
+
+		routes.route("/handler-test/panic", NewZeroComponentFactory(&handlerTestPanicCompo{}))
+		err := th.CheckRoutes()
+		require.Error(t, err)
+		t.Log(err)
+	})
+
+	t.Run("check links reports broken links", func(t *testing.T) {
+		routes := makeRouter()
+		routes.route("/handler-test/about", NewZeroComponentFactory(&hello{}))
+		routes.route("/handler-test/broken", NewZeroComponentFactory(&handlerTestBrokenLinksCompo{}))
+		th.routes = &routes
+
+		err := th.CheckLinks()
+		require.Error(t, err)
+		require.Equal(t, map[string][]string{
+			"/handler-test/broken": {
+				"/handler-test/missing",
+				"/web/missing.txt",
+			},
+		}, errors.Tag(err, "links"))
+		t.Log(err)
+	})
+}
+
+func TestMatchIntegrity(t *testing.T) {
+	content := []byte("body {}")
+	filename := filepath.Join(t.TempDir(), "main.css")
+	require.NoError(t, os.WriteFile(filename, content, 0644))
+	integrity, err := fileIntegrity(filename)
+	require.NoError(t, err)
+
+	require.True(t, matchIntegrity(integrity, content))
+	require.True(t, matchIntegrity("sha256-invalid "+integrity, content))
+	require.False(t, matchIntegrity(integrity, []byte("body { margin: 0 }")))
+	require.False(t, matchIntegrity("md5-invalid", content))
+}
@@ -40,6 +40,7 @@ type htmlElement struct {
 	eventHandlers eventHandlers
 	parentElement UI
 	children      []UI
+	leaving       map[UI]func()
 }
 
 func (e *htmlElement) JSValue() Value {
@@ -95,3 +96,18 @@ func (e *htmlElement) parent() UI {
 func (e *htmlElement) body() []UI {
 	return e.children
 }
+
+func (e *htmlElement) leavingChildren() map[UI]func() {
+	return e.leaving
+}
+
+func (e *htmlElement) setLeavingChild(child UI, remove func()) {
+	if remove == nil {
+		delete(e.leaving, child)
+		return
+	}
+	if e.leaving == nil {
+		e.leaving = make(map[UI]func())
+	}
+	e.leaving[child] = remove
+}
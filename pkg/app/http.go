@@ -3,7 +3,10 @@ package app
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -47,6 +50,43 @@ type Handler struct {
 	// like the PWA title bar. Defaults to "#2d2c2c".
 	ThemeColor string
 
+	// DarkBackgroundColor and DarkThemeColor are the background and theme
+	// colors used when the user prefers a dark color scheme. BackgroundColor
+	// and ThemeColor are used for all color schemes when they are empty.
+	DarkBackgroundColor string
+	DarkThemeColor      string
+
+	// Shortcuts lists the entries displayed when the installed app icon is
+	// long-pressed or right-clicked.
+	Shortcuts []Shortcut
+
+	// ShareTarget registers the installed app as a target of the operating
+	// system share dialog when set. Shared data is delivered to components
+	// that implement ShareReceiver.
+	ShareTarget *ShareTarget
+
+	// FileHandlers registers the installed app as a handler for the given
+	// file types. Opened files are delivered to components that implement
+	// ShareReceiver.
+	FileHandlers []FileHandler
+
+	// ProtocolHandlers registers the installed app as a handler for the given
+	// URL protocols.
+	ProtocolHandlers []ProtocolHandler
+
+	// Screenshots lists images of the app displayed in install dialogs and
+	// app stores.
+	Screenshots []Screenshot
+
+	// DisplayOverride lists the preferred display modes in order, e.g.
+	// "window-controls-overlay" or "tabbed". The "standalone" display mode
+	// is used when none is supported.
+	DisplayOverride []string
+
+	// Categories lists the categories the app belongs to, e.g. "productivity"
+	// or "games".
+	Categories []string
+
 	// LoadingLabel shows text during page load, with "{progress}" for progress.
 	// Defaults to "{progress}%".
 	LoadingLabel string
@@ -54,9 +94,23 @@ type Handler struct {
 	// Lang defines the primary language of the app page. Defaults to "en".
 	Lang string
 
+	// Locales lists the languages the app is available in. When set, pages
+	// for the Lang locale are served as is while pages for other locales are
+	// served under a path prefixed by the locale, e.g. "/fr/about". Pages
+	// without a locale prefix are rendered in the language negotiated from
+	// the Accept-Language header, or in the locale persisted with
+	// Context.SetLocale once the app is loaded.
+	Locales []string
+
 	// Libraries are custom libraries to load with the page.
 	Libraries []Library
 
+	// Stylers are components whose scoped styles are served in the
+	// "/components.css" stylesheet along with the styles of the routed
+	// components. Listing the components that are nested in pages avoids
+	// their styles being injected only once the app is loaded.
+	Stylers []Styler
+
 	// Title sets the title of the app page.
 	Title string
 
@@ -79,25 +133,44 @@ type Handler struct {
 	Image string
 
 	// Styles lists CSS files for the page, supporting local and external
-	// resources. Attributes like crossorigin can be appended after a space.
+	// resources. Attributes like crossorigin and integrity can be appended
+	// after a space.
 	Styles []string
 
 	// Fonts lists font files to preload, improving performance and visual
-	// readiness. Attributes like crossorigin can be appended after a space.
+	// readiness. Attributes like crossorigin and integrity can be appended
+	// after a space.
 	Fonts []string
 
 	// Scripts lists JavaScript files for the page. Attributes like crossorigin,
-	// async, and defer can be appended, separated with a space.
+	// integrity, async, and defer can be appended, separated with a space.
 	Scripts []string
 
+	// SubresourceIntegrity enables computing the integrity hashes of the
+	// local /web/ Styles, Fonts and Scripts when the handler is initialized.
+	// Hashes are set on the page links and verified when the service worker
+	// caches the resources. Hashes given with the integrity attribute take
+	// precedence.
+	SubresourceIntegrity bool
+
 	// CacheableResources specifies extra static resources to cache for offline
 	// access. Icon, Styles and Scripts are already cached by default.
 	// Attributes like crossorigin can be appended after a space.
 	CacheableResources []string
 
 	// RawHeaders contains extra HTML headers for the page's <head> section.
+	// When ContentSecurityPolicy is set, occurrences of "{nonce}" are replaced
+	// by the page nonce, which allows inline scripts and styles to opt in,
+	// e.g. `<script nonce="{nonce}">...</script>`.
 	RawHeaders []string
 
+	// ContentSecurityPolicy is the policy sent with the
+	// Content-Security-Policy header of pages. When set, a nonce is generated
+	// for each request and set on the scripts and styles emitted by the
+	// handler. Occurrences of "{nonce}" in the policy are replaced by the
+	// nonce, e.g. "script-src 'nonce-{nonce}' 'wasm-unsafe-eval'".
+	ContentSecurityPolicy string
+
 	// HTML returns the page's HTML element. Defaults to app.Html().
 	HTML func() HTMLHtml
 
@@ -121,6 +194,11 @@ type Handler struct {
 	// /sitemap.xml, and /ads.txt, which are proxied by default.
 	ProxyResources []ProxyResource
 
+	// Sitemap enables the generation of /sitemap.xml and /robots.txt from the
+	// registered routes, which requires Domain. Both are proxied from their
+	// /web/ files when nil.
+	Sitemap *Sitemap
+
 	// Resources resolves paths for static resources, specifically handling
 	// paths prefixed with "/web/". Defaults to app.LocalDir("").
 	Resources ResourceResolver
@@ -143,25 +221,44 @@ type Handler struct {
 	// issues with go-app functionality.
 	ServiceWorkerTemplate string
 
+	// PageCache enables caching of prerendered pages when set. Pages are
+	// prerendered for each request by default.
+	PageCache *PageCache
+
+	// Variants lists versions of the app served for specific hosts or URL
+	// path prefixes, each with its own manifest, icons, colors and service
+	// worker scope. Requests that match no variant are served with the
+	// Handler configuration.
+	Variants []Variant
+
 	once                 sync.Once
 	etag                 string
+	noncePlaceholder     string
 	libraries            map[string][]byte
+	styleScopes          []string
 	proxyResources       map[string]ProxyResource
 	cachedProxyResources *memoryCache
-	cachedPWAResources   *memoryCache
+	integrity            map[string]string
+	defaultVariant       *appVariant
+	variants             []*appVariant
+	sitemaps             map[string]cacheItem
 }
 
 func (h *Handler) init() {
 	h.initVersion()
+	h.initContentSecurityPolicy()
 	h.initStaticResources()
 	h.initLibraries()
 	h.initLinks()
+	h.initIntegrity()
 	h.initServiceWorker()
 	h.initIcon()
 	h.initPWA()
 	h.initPageContent()
+	h.initVariants()
 	h.initPWAResources()
 	h.initProxyResources()
+	h.initSitemap()
 }
 
 func (h *Handler) initVersion() {
@@ -172,6 +269,15 @@ func (h *Handler) initVersion() {
 	h.etag = `"` + h.Version + `"`
 }
 
+// initContentSecurityPolicy generates the placeholder that marks where the
+// page nonce is written. It is random so that rendered content cannot forge
+// it.
+func (h *Handler) initContentSecurityPolicy() {
+	if h.ContentSecurityPolicy != "" {
+		h.noncePlaceholder = "goapp-csp-nonce-" + newCSPNonce()
+	}
+}
+
 func (h *Handler) initStaticResources() {
 	if h.Resources == nil {
 		h.Resources = LocalDir("")
@@ -187,6 +293,11 @@ func (h *Handler) initLibraries() {
 		}
 		libs[path] = []byte(styles)
 	}
+
+	if styles, scopes := h.componentStyles(); len(styles) != 0 {
+		libs[componentStylesPath] = styles
+		h.styleScopes = scopes
+	}
 	h.libraries = libs
 }
 
@@ -198,6 +309,48 @@ func (h *Handler) initLinks() {
 	h.Styles = append(styles, h.Styles...)
 }
 
+func (h *Handler) initIntegrity() {
+	h.integrity = make(map[string]string)
+	if !h.SubresourceIntegrity {
+		return
+	}
+
+	resources := make([]string, 0, len(h.Styles)+len(h.Fonts)+len(h.Scripts))
+	resources = append(resources, h.Styles...)
+	resources = append(resources, h.Fonts...)
+	resources = append(resources, h.Scripts...)
+
+	for _, r := range resources {
+		resource := parseHTTPResource(r)
+		if resource.URL == "" || resource.Integrity != "" {
+			continue
+		}
+
+		filename, ok := localResourceFilename(h.Resources, resource.URL)
+		if !ok {
+			continue
+		}
+
+		integrity, err := fileIntegrity(filename)
+		if err != nil {
+			Log(errors.New("computing resource integrity failed").
+				WithTag("resource", resource.URL).
+				WithTag("filename", filename).
+				Wrap(err))
+			continue
+		}
+		h.integrity[integrityKey(resource.URL)] = integrity
+	}
+}
+
+func (h *Handler) parseHTTPResource(v string) httpResource {
+	resource := parseHTTPResource(v)
+	if resource.Integrity == "" && resource.URL != "" {
+		resource.Integrity = h.integrity[integrityKey(resource.URL)]
+	}
+	return resource
+}
+
 func (h *Handler) initServiceWorker() {
 	if h.ServiceWorkerTemplate == "" {
 		h.ServiceWorkerTemplate = DefaultAppWorkerJS
@@ -258,40 +411,53 @@ func (h *Handler) initPageContent() {
 }
 
 func (h *Handler) initPWAResources() {
-	h.cachedPWAResources = newMemoryCache(5)
+	h.initVariantPWAResources(h.defaultVariant)
+	for _, v := range h.variants {
+		h.initVariantPWAResources(v)
+	}
+}
 
-	h.cachedPWAResources.Set(cacheItem{
+func (h *Handler) initVariantPWAResources(v *appVariant) {
+	v.cachedPWAResources = newMemoryCache(5)
+
+	v.cachedPWAResources.Set(cacheItem{
 		Path:        "/wasm_exec.js",
 		ContentType: "application/javascript",
 		Body:        []byte(wasmExecJS()),
 	})
 
-	h.cachedPWAResources.Set(cacheItem{
+	v.cachedPWAResources.Set(cacheItem{
 		Path:        "/app.js",
 		ContentType: "application/javascript",
-		Body:        h.makeAppJS(),
+		Body:        h.makeAppJS(v),
 	})
 
-	h.cachedPWAResources.Set(cacheItem{
+	v.cachedPWAResources.Set(cacheItem{
 		Path:        "/app-worker.js",
 		ContentType: "application/javascript",
-		Body:        h.makeAppWorkerJS(),
+		Body:        h.makeAppWorkerJS(v),
+	})
+
+	v.cachedPWAResources.Set(cacheItem{
+		Path:        "/app-webworker.js",
+		ContentType: "application/javascript",
+		Body:        h.makeAppWebWorkerJS(v),
 	})
 
-	h.cachedPWAResources.Set(cacheItem{
+	v.cachedPWAResources.Set(cacheItem{
 		Path:        "/manifest.webmanifest",
 		ContentType: "application/manifest+json",
-		Body:        h.makeManifestJSON(),
+		Body:        h.makeManifestJSON(v),
 	})
 
-	h.cachedPWAResources.Set(cacheItem{
+	v.cachedPWAResources.Set(cacheItem{
 		Path:        "/app.css",
 		ContentType: "text/css",
 		Body:        []byte(appCSS),
 	})
 }
 
-func (h *Handler) makeAppJS() []byte {
+func (h *Handler) makeAppJS(v *appVariant) []byte {
 	if h.Env == nil {
 		h.Env = make(map[string]string)
 	}
@@ -300,6 +466,10 @@ func (h *Handler) makeAppJS() []byte {
 	h.Env["GOAPP_VERSION"] = h.Version
 	h.Env["GOAPP_STATIC_RESOURCES_URL"] = h.Resources.Resolve("/web")
 	h.Env["GOAPP_ROOT_PREFIX"] = h.Resources.Resolve("/")
+	h.Env["GOAPP_LOCALES"] = jsonString(h.Locales)
+	h.Env["GOAPP_DEFAULT_LOCALE"] = h.Lang
+	h.Env["GOAPP_COMPONENT_STYLES"] = jsonString(h.styleScopes)
+	h.Env["GOAPP_WEBWORKER_URL"] = h.Resources.Resolve("/app-webworker.js")
 
 	for k, v := range h.Env {
 		if err := os.Setenv(k, v); err != nil {
@@ -320,45 +490,65 @@ func (h *Handler) makeAppJS() []byte {
 			WasmContentLength       string
 			WasmContentLengthHeader string
 			WorkerJS                string
+			ShareTargetAction       string
 		}{
-			Env:                     jsonString(h.Env),
+			Env:                     jsonString(h.variantEnv(v)),
 			LoadingLabel:            h.LoadingLabel,
 			Wasm:                    h.Resources.Resolve("/web/app.wasm"),
 			WasmContentLength:       h.WasmContentLength,
 			WasmContentLengthHeader: h.WasmContentLengthHeader,
-			WorkerJS:                h.Resources.Resolve("/app-worker.js"),
+			WorkerJS:                h.Resources.Resolve(v.PathPrefix + "/app-worker.js"),
+			ShareTargetAction:       h.shareTargetAction(v),
 		}); err != nil {
 		panic(errors.New("initializing app.js failed").Wrap(err))
 	}
 	return b.Bytes()
 }
 
-func (h *Handler) makeAppWorkerJS() []byte {
-	resources := make(map[string]struct{})
-	setResources := func(res ...string) {
-		for _, r := range res {
-			if resource := parseHTTPResource(r); resource.URL != "" {
-				resources[resource.URL] = struct{}{}
-			}
-		}
+// variantEnv returns the environment variables of the given variant. It must
+// be called after the environment variables are set by makeAppJS.
+func (h *Handler) variantEnv(v *appVariant) map[string]string {
+	if v.PathPrefix == "" {
+		return h.Env
 	}
-	setResources(
-		"/app.css",
-		"/app.js",
-		"/manifest.webmanifest",
-		"/wasm_exec.js",
-		"/",
-		"/web/app.wasm",
-	)
-	setResources(h.Icon.Default, h.Icon.Large, h.Icon.Maskable)
-	setResources(h.Styles...)
-	setResources(h.Fonts...)
-	setResources(h.Scripts...)
-	setResources(h.CacheableResources...)
+
+	env := make(map[string]string, len(h.Env))
+	for name, value := range h.Env {
+		env[name] = value
+	}
+	env["GOAPP_ROOT_PREFIX"] = h.Resources.Resolve(v.PathPrefix)
+	return env
+}
+
+func (h *Handler) makeAppWebWorkerJS(v *appVariant) []byte {
+	var b bytes.Buffer
+	if err := template.
+		Must(template.New("app-webworker.js").Parse(appWebWorkerJS)).
+		Execute(&b, struct {
+			Env        string
+			Wasm       string
+			WasmExecJS string
+		}{
+			Env:        jsonString(h.variantEnv(v)),
+			Wasm:       h.Resources.Resolve("/web/app.wasm"),
+			WasmExecJS: h.Resources.Resolve("/wasm_exec.js"),
+		}); err != nil {
+		panic(errors.New("initializing app-webworker.js failed").Wrap(err))
+	}
+	return b.Bytes()
+}
+
+func (h *Handler) makeAppWorkerJS(v *appVariant) []byte {
+	resources := h.cacheableResources(v)
 
 	resourcesTocache := make([]string, 0, len(resources))
-	for k := range resources {
-		resourcesTocache = append(resourcesTocache, h.Resources.Resolve(k))
+	resourcesIntegrity := make(map[string]string)
+	for k, integrity := range resources {
+		u := h.Resources.Resolve(k)
+		resourcesTocache = append(resourcesTocache, u)
+		if integrity != "" {
+			resourcesIntegrity[u] = integrity
+		}
 	}
 	sort.Slice(resourcesTocache, func(a, b int) bool {
 		return strings.Compare(resourcesTocache[a], resourcesTocache[b]) < 0
@@ -368,50 +558,105 @@ func (h *Handler) makeAppWorkerJS() []byte {
 	if err := template.
 		Must(template.New("app-worker.js").Parse(h.ServiceWorkerTemplate)).
 		Execute(&b, struct {
-			Version          string
-			ResourcesToCache string
+			Version            string
+			CacheScope         string
+			ResourcesToCache   string
+			ResourcesIntegrity string
+			ShareTargetAction  string
 		}{
-			Version:          h.Version,
-			ResourcesToCache: jsonString(resourcesTocache),
+			Version:            h.Version,
+			CacheScope:         v.id,
+			ShareTargetAction:  h.shareTargetAction(v),
+			ResourcesToCache:   jsonString(resourcesTocache),
+			ResourcesIntegrity: jsonString(resourcesIntegrity),
 		}); err != nil {
 		panic(errors.New("initializing app-worker.js failed").Wrap(err))
 	}
 	return b.Bytes()
 }
 
-func (h *Handler) makeManifestJSON() []byte {
-	scope := h.Resources.Resolve("/")
+// cacheableResources returns the unresolved URLs of the resources cached by
+// the service worker of the given variant, associated with their integrity.
+func (h *Handler) cacheableResources(v *appVariant) map[string]string {
+	resources := make(map[string]string)
+	setResources := func(res ...string) {
+		for _, r := range res {
+			if resource := h.parseHTTPResource(r); resource.URL != "" {
+				resources[resource.URL] = resource.Integrity
+			}
+		}
+	}
+	setResources(
+		"/app.css",
+		v.PathPrefix+"/app.js",
+		v.PathPrefix+"/manifest.webmanifest",
+		"/wasm_exec.js",
+		"/app-webworker.js",
+		v.PathPrefix+"/",
+		"/web/app.wasm",
+	)
+	setResources(v.Icon.Default, v.Icon.Large, v.Icon.Maskable)
+	setResources(h.Styles...)
+	setResources(h.Fonts...)
+	setResources(h.Scripts...)
+	setResources(h.CacheableResources...)
+	return resources
+}
+
+func (h *Handler) makeManifestJSON(v *appVariant) []byte {
+	scope := h.Resources.Resolve(v.PathPrefix + "/")
 	if scope != "/" && !strings.HasSuffix(scope, "/") {
 		scope += "/"
 	}
 
+	var svgIcon string
+	if v.Icon.SVG != "" {
+		svgIcon = h.Resources.Resolve(v.Icon.SVG)
+	}
+
 	var b bytes.Buffer
 	if err := template.
 		Must(template.New("manifest.webmanifest").Parse(manifestJSON)).
 		Execute(&b, struct {
-			ShortName       string
-			Name            string
-			Description     string
-			DefaultIcon     string
-			LargeIcon       string
-			SVGIcon         string
-			MaskableIcon    string
-			BackgroundColor string
-			ThemeColor      string
-			Scope           string
-			StartURL        string
+			ShortName        string
+			Name             string
+			Description      string
+			DefaultIcon      string
+			LargeIcon        string
+			SVGIcon          string
+			MaskableIcon     string
+			BackgroundColor  string
+			ThemeColor       string
+			UserPreferences  string
+			Scope            string
+			StartURL         string
+			Shortcuts        string
+			ShareTarget      string
+			FileHandlers     string
+			ProtocolHandlers string
+			Screenshots      string
+			DisplayOverride  string
+			Categories       string
 		}{
-			ShortName:       h.ShortName,
-			Name:            h.Name,
-			Description:     h.Description,
-			DefaultIcon:     h.Resources.Resolve(h.Icon.Default),
-			LargeIcon:       h.Resources.Resolve(h.Icon.Large),
-			SVGIcon:         h.Resources.Resolve(h.Icon.SVG),
-			MaskableIcon:    h.Resources.Resolve(h.Icon.Maskable),
-			BackgroundColor: h.BackgroundColor,
-			ThemeColor:      h.ThemeColor,
-			Scope:           scope,
-			StartURL:        h.Resources.Resolve("/"),
+			ShortName:        v.ShortName,
+			Name:             v.Name,
+			Description:      v.Description,
+			DefaultIcon:      h.Resources.Resolve(v.Icon.Default),
+			LargeIcon:        h.Resources.Resolve(v.Icon.Large),
+			SVGIcon:          svgIcon,
+			MaskableIcon:     h.Resources.Resolve(v.Icon.Maskable),
+			BackgroundColor:  v.BackgroundColor,
+			ThemeColor:       v.ThemeColor,
+			UserPreferences:  manifestUserPreferences(v),
+			Scope:            scope,
+			StartURL:         h.Resources.Resolve(v.PathPrefix + "/"),
+			Shortcuts:        h.manifestShortcuts(v),
+			ShareTarget:      h.manifestShareTarget(v),
+			FileHandlers:     h.manifestFileHandlers(v),
+			ProtocolHandlers: h.manifestProtocolHandlers(v),
+			Screenshots:      h.manifestScreenshots(),
+			DisplayOverride:  manifestStrings(h.DisplayOverride),
+			Categories:       manifestStrings(h.Categories),
 		}); err != nil {
 		panic(errors.New("initializing manifest.webmanifest failed").Wrap(err))
 	}
@@ -428,6 +673,7 @@ func (h *Handler) initProxyResources() {
 			"/goapp.js",
 			"/app.js",
 			"/app-worker.js",
+			"/app-webworker.js",
 			"/manifest.json",
 			"/manifest.webmanifest",
 			"/app.css",
@@ -488,12 +734,15 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	switch path {
+	variant := h.variant(r, path)
+	variantPath := variant.trimPathPrefix(path)
+
+	switch variantPath {
 	case "/goapp.js":
-		path = "/app.js"
+		variantPath = "/app.js"
 
 	case "/manifest.json":
-		path = "/manifest.webmanifest"
+		variantPath = "/manifest.webmanifest"
 
 	case "/app.wasm", "/goapp.wasm":
 		if isServingStaticResources {
@@ -507,11 +756,16 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	}
 
-	if res, ok := h.cachedPWAResources.Get(path); ok {
+	if res, ok := variant.cachedPWAResources.Get(variantPath); ok {
 		h.serveCachedItem(w, res)
 		return
 	}
 
+	if h.isSitemapResource(path) {
+		h.serveSitemapResource(w, r, path)
+		return
+	}
+
 	if proxyResource, ok := h.proxyResources[path]; ok {
 		h.serveProxyResource(proxyResource, w, r)
 		return
@@ -522,7 +776,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.servePage(w, r)
+	h.servePage(w, r, variant)
 }
 
 func (h *Handler) serveCachedItem(w http.ResponseWriter, i cacheItem) {
@@ -596,20 +850,59 @@ func (h *Handler) serveProxyResource(resource ProxyResource, w http.ResponseWrit
 	h.serveCachedItem(w, item)
 }
 
-func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
-	if routed := routes.routed(r.URL.Path); !routed {
+func (h *Handler) servePage(w http.ResponseWriter, r *http.Request, v *appVariant) {
+	lang, path := h.pageLocale(r, v.trimPathPrefix(r.URL.Path))
+	route, routed := routes.match(path)
+	if !routed {
 		http.NotFound(w, r)
 		return
 	}
+	if len(h.Locales) != 0 {
+		w.Header().Add("Vary", "Accept-Language")
+	}
+
+	if h.PageCache != nil {
+		if page, ok := h.PageCache.get(r, v.id, lang); ok {
+			h.writePage(w, page)
+			return
+		}
+	}
 
+	page, err := h.renderPage(r, v, lang)
+	if err != nil {
+		Log(errors.New("encoding html document failed").Wrap(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if h.PageCache != nil {
+		h.PageCache.set(r, path, route, v.id, lang, page)
+	}
+	h.writePage(w, page)
+}
+
+func (h *Handler) writePage(w http.ResponseWriter, page []byte) {
+	if h.ContentSecurityPolicy != "" {
+		nonce := newCSPNonce()
+		w.Header().Set("Content-Security-Policy", strings.ReplaceAll(h.ContentSecurityPolicy, "{nonce}", nonce))
+		page = bytes.ReplaceAll(page, []byte(h.noncePlaceholder), []byte(nonce))
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(page)))
+	w.Header().Set("Content-Type", "text/html")
+	w.Write(page)
+}
+
+func (h *Handler) renderPage(r *http.Request, v *appVariant, lang string) ([]byte, error) {
 	ctx := context.Background()
 
 	origin := *r.URL
 	origin.Scheme = "http"
+	origin.Path = v.trimPathPrefix(origin.Path)
 
 	page := makeRequestPage(&origin, h.Resources.Resolve)
 	page.SetTitle(h.Title)
-	page.SetLang(h.Lang)
+	page.SetLang(lang)
 	page.SetDescription(h.Description)
 	page.SetAuthor(h.Author)
 	page.SetKeywords(h.Keywords...)
@@ -625,9 +918,31 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 	engine.Navigate(page.URL(), false)
 	engine.ConsumeAll()
 
-	icon := h.Icon.SVG
+	icon := v.Icon.SVG
 	if icon == "" {
-		icon = h.Icon.Default
+		icon = v.Icon.Default
+	}
+
+	var nonce string
+	if h.ContentSecurityPolicy != "" {
+		nonce = h.noncePlaceholder
+	}
+
+	alternates := page.alternates
+	if len(alternates) == 0 && len(h.Locales) != 0 {
+		_, path := splitLocalePath(page.URL().Path, h.Locales)
+		alternates = h.localeAlternates(v.PathPrefix, path)
+	}
+
+	openGraph := map[string]string{
+		"og:url":         h.Resources.Resolve(v.PathPrefix + page.URL().Path),
+		"og:title":       page.Title(),
+		"og:description": page.Description(),
+		"og:type":        "website",
+		"og:image":       page.Image(),
+	}
+	for k, v := range page.openGraph {
+		openGraph[k] = v
 	}
 
 	var b bytes.Buffer
@@ -647,27 +962,44 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 						Name("keywords").
 						Content(page.Keywords())
 				}),
-				Meta().
-					Name("theme-color").
-					Content(h.ThemeColor),
+				IfSlice(page.ThemeColor() == "" && v.DarkThemeColor != "", func() []UI {
+					return []UI{
+						Meta().
+							Name("theme-color").
+							Attr("media", "(prefers-color-scheme: light)").
+							Content(v.ThemeColor),
+						Meta().
+							Name("theme-color").
+							Attr("media", "(prefers-color-scheme: dark)").
+							Content(v.DarkThemeColor),
+					}
+				}).Else(func() UI {
+					color := page.ThemeColor()
+					if color == "" {
+						color = v.ThemeColor
+					}
+					return Meta().
+						Name("theme-color").
+						Content(color)
+				}),
 				Meta().
 					Name("viewport").
 					Content("width=device-width, initial-scale=1, maximum-scale=1, user-scalable=0, viewport-fit=cover"),
-				Meta().
-					Property("og:url").
-					Content(resolveOGResource(h.Domain, h.Resources.Resolve(page.URL().Path))),
-				Meta().
-					Property("og:title").
-					Content(page.Title()),
-				Meta().
-					Property("og:description").
-					Content(page.Description()),
-				Meta().
-					Property("og:type").
-					Content("website"),
-				Meta().
-					Property("og:image").
-					Content(resolveOGResource(h.Domain, page.Image())),
+				If(page.Robots() != "", func() UI {
+					return Meta().
+						Name("robots").
+						Content(page.Robots())
+				}),
+				Range(openGraph).Map(func(k string) UI {
+					v := openGraph[k]
+					switch k {
+					case "og:url", "og:image":
+						v = resolveOGResource(h.Domain, v)
+					}
+					return Meta().
+						Property(k).
+						Content(v)
+				}),
 				Range(page.twitterCardMap).Map(func(k string) UI {
 					v := page.twitterCardMap[k]
 					if v == "" {
@@ -681,6 +1013,24 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 						Content(v)
 				}),
 				Title().Text(page.Title()),
+				If(page.CanonicalURL() != "", func() UI {
+					return Link().
+						Rel("canonical").
+						Href(resolveOGResource(h.Domain, h.Resources.Resolve(page.CanonicalURL())))
+				}),
+				Range(alternates).Slice(func(i int) UI {
+					a := alternates[i]
+					return Link().
+						Rel("alternate").
+						HrefLang(a.Lang).
+						Href(resolveOGResource(h.Domain, h.Resources.Resolve(a.Href)))
+				}),
+				Range(page.structuredData).Slice(func(i int) UI {
+					if nonce != "" {
+						return Raw(`<script type="application/ld+json" nonce="` + nonce + `">` + page.structuredData[i] + `</script>`)
+					}
+					return Raw(`<script type="application/ld+json">` + page.structuredData[i] + `</script>`)
+				}),
 				Range(h.Preconnect).Slice(func(i int) UI {
 					if resource := parseHTTPResource(h.Preconnect[i]); resource.URL != "" {
 						return resource.toLink().Rel("preconnect")
@@ -688,7 +1038,7 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 					return nil
 				}),
 				Range(h.Fonts).Slice(func(i int) UI {
-					if resource := parseHTTPResource(h.Fonts[i]); resource.URL != "" {
+					if resource := h.parseHTTPResource(h.Fonts[i]); resource.URL != "" {
 						return resource.toLink().
 							Type("font/" + strings.TrimPrefix(filepath.Ext(resource.URL), ".")).
 							Rel("preload").
@@ -712,7 +1062,7 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 					return nil
 				}),
 				Range(h.Styles).Slice(func(i int) UI {
-					if resource := parseHTTPResource(h.Styles[i]); resource.URL != "" {
+					if resource := h.parseHTTPResource(h.Styles[i]); resource.URL != "" {
 						return resource.toLink().
 							Type("text/css").
 							Rel("preload").
@@ -725,32 +1075,41 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 					Href(icon),
 				Link().
 					Rel("apple-touch-icon").
-					Href(h.Icon.Maskable),
+					Href(v.Icon.Maskable),
 				Link().
 					Rel("manifest").
-					Href("/manifest.webmanifest"),
+					Href(v.PathPrefix+"/manifest.webmanifest"),
 				Range(h.Styles).Slice(func(i int) UI {
-					if resource := parseHTTPResource(h.Styles[i]); resource.URL != "" {
+					if resource := h.parseHTTPResource(h.Styles[i]); resource.URL != "" {
+						resource.Nonce = nonce
 						return resource.toLink().
 							Type("text/css").
 							Rel("stylesheet")
 					}
 					return nil
 				}),
-				Script().
-					Defer(true).
-					Src("/wasm_exec.js"),
-				Script().
-					Defer(true).
-					Src("/app.js"),
+				httpResource{
+					URL:         "/wasm_exec.js",
+					LoadingMode: "defer",
+					Nonce:       nonce,
+				}.toScript(),
+				httpResource{
+					URL:         v.PathPrefix + "/app.js",
+					LoadingMode: "defer",
+					Nonce:       nonce,
+				}.toScript(),
 				Range(h.Scripts).Slice(func(i int) UI {
-					if resource := parseHTTPResource(h.Scripts[i]); resource.URL != "" {
+					if resource := h.parseHTTPResource(h.Scripts[i]); resource.URL != "" {
+						resource.Nonce = nonce
 						return resource.toScript()
 					}
 					return nil
 
 				}),
 				Range(h.RawHeaders).Slice(func(i int) UI {
+					if nonce != "" {
+						return Raw(strings.ReplaceAll(h.RawHeaders[i], "{nonce}", nonce))
+					}
 					return Raw(h.RawHeaders[i])
 				}),
 			),
@@ -763,7 +1122,7 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 							ID("app-wasm-loader-icon").
 							Class("goapp-logo goapp-spin").
 							Alt("wasm loader icon").
-							Src(h.Icon.Default),
+							Src(v.Icon.Default),
 						P().
 							ID("app-wasm-loader-label").
 							Class("goapp-label").
@@ -772,14 +1131,9 @@ func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
 			),
 		))
 	if err != nil {
-		Log(errors.New("encoding html document failed").Wrap(err))
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+		return nil, err
 	}
-
-	w.Header().Set("Content-Length", strconv.Itoa(b.Len()))
-	w.Header().Set("Content-Type", "text/html")
-	w.Write(b.Bytes())
+	return b.Bytes(), nil
 }
 
 func (h *Handler) serveLibrary(w http.ResponseWriter, r *http.Request, library []byte) {
@@ -819,6 +1173,30 @@ type Icon struct {
 	Maskable string
 }
 
+func integrityKey(path string) string {
+	if remoteLocation(path) {
+		return path
+	}
+	return "/" + strings.TrimLeft(path, "/")
+}
+
+func fileIntegrity(filename string) (string, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	sum := sha512.Sum384(b)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:]), nil
+}
+
+func newCSPNonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(errors.New("generating content security policy nonce failed").Wrap(err))
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
 func isRemoteLocation(path string) bool {
 	return strings.HasPrefix(path, "https://") ||
 		strings.HasPrefix(path, "http://")
@@ -833,6 +1211,8 @@ type httpResource struct {
 	URL         string
 	LoadingMode string
 	CrossOrigin string
+	Integrity   string
+	Nonce       string
 }
 
 func (r httpResource) toLink() HTMLLink {
@@ -840,6 +1220,12 @@ func (r httpResource) toLink() HTMLLink {
 	if r.CrossOrigin != "" {
 		link = link.CrossOrigin(strings.Trim(r.CrossOrigin, "true"))
 	}
+	if r.Integrity != "" {
+		link = link.Attr("integrity", r.Integrity)
+	}
+	if r.Nonce != "" {
+		link = link.Attr("nonce", r.Nonce)
+	}
 	return link
 }
 
@@ -848,6 +1234,12 @@ func (r httpResource) toScript() HTMLScript {
 	if r.CrossOrigin != "" {
 		script = script.CrossOrigin(strings.Trim(r.CrossOrigin, "true"))
 	}
+	if r.Integrity != "" {
+		script = script.Attr("integrity", r.Integrity)
+	}
+	if r.Nonce != "" {
+		script = script.Attr("nonce", r.Nonce)
+	}
 
 	switch r.LoadingMode {
 	case "defer":
@@ -865,9 +1257,13 @@ func parseHTTPResource(v string) httpResource {
 		if elem = strings.TrimSpace(elem); elem == "" {
 			continue
 		}
+		original := elem
 		elem = strings.ToLower(elem)
 
 		switch {
+		case strings.HasPrefix(elem, "integrity="):
+			res.Integrity = strings.Trim(original[len("integrity="):], `"'`)
+
 		case elem == "crossorigin":
 			res.CrossOrigin = "true"
 
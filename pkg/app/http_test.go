@@ -4,10 +4,14 @@
 package app
 
 import (
+	"crypto/sha512"
+	"encoding/base64"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -15,6 +19,8 @@ import (
 
 func init() {
 	Route("/", func() Composer { return &preRenderTestCompo{} })
+	Route("/seo", func() Composer { return &seoTestCompo{} })
+	Route("/csp", func() Composer { return &cspTestCompo{} })
 }
 
 type preRenderTestCompo struct {
@@ -29,6 +35,125 @@ func (c *preRenderTestCompo) Render() UI {
 		)
 }
 
+type seoTestCompo struct {
+	Compo
+}
+
+func (c *seoTestCompo) OnPreRender(ctx Context) {
+	page := ctx.Page()
+	page.SetCanonicalURL("/seo")
+	page.SetAlternates(
+		Alternate{Lang: "fr", Href: "/fr/seo"},
+		Alternate{Lang: "x-default", Href: "https://go-app.dev/seo"},
+	)
+	page.SetRobots("noindex", "nofollow")
+	page.SetOpenGraph("og:type", "article")
+	page.SetOpenGraph("og:locale", "en_US")
+	page.SetStructuredData(map[string]any{
+		"@context": "https://schema.org",
+		"@type":    "Article",
+		"headline": "</script>",
+	})
+}
+
+func (c *seoTestCompo) Render() UI {
+	return Div()
+}
+
+func TestHandlerServePageWithSEOMetadata(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/seo", nil)
+	w := httptest.NewRecorder()
+
+	h := Handler{
+		Domain: "go-app.dev",
+	}
+	h.ServeHTTP(w, r)
+
+	body := w.Body.String()
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, body, `rel="canonical"`)
+	require.Contains(t, body, `href="https://go-app.dev/seo"`)
+	require.Contains(t, body, `hreflang="fr"`)
+	require.Contains(t, body, `href="https://go-app.dev/fr/seo"`)
+	require.Contains(t, body, `hreflang="x-default"`)
+	require.Contains(t, body, `content="noindex, nofollow"`)
+	require.Contains(t, body, `content="article"`)
+	require.NotContains(t, body, `content="website"`)
+	require.Contains(t, body, `property="og:locale"`)
+	require.Contains(t, body, `content="en_US"`)
+	require.Contains(t, body, `content="https://go-app.dev/seo"`)
+	require.Contains(t, body, `<script type="application/ld+json">{"@context":"https://schema.org","@type":"Article","headline":"\u003c/script\u003e"}</script>`)
+	t.Log(body)
+}
+
+func TestHandlerServePageWithContentSecurityPolicy(t *testing.T) {
+	h := Handler{
+		ContentSecurityPolicy: "script-src 'nonce-{nonce}' 'wasm-unsafe-eval'; style-src 'nonce-{nonce}'",
+		Scripts:               []string{"/web/hello.js"},
+		RawHeaders: []string{
+			`<script nonce="{nonce}">console.log("hello")</script>`,
+		},
+		PageCache: &PageCache{},
+	}
+
+	serve := func() (string, string) {
+		r := httptest.NewRequest(http.MethodGet, "/seo", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		policy := w.Header().Get("Content-Security-Policy")
+		require.Regexp(t, `^script-src 'nonce-[A-Za-z0-9+/=]{24}' 'wasm-unsafe-eval'; style-src 'nonce-[A-Za-z0-9+/=]{24}'$`, policy)
+		nonce := policy[len("script-src 'nonce-") : len("script-src 'nonce-")+24]
+
+		body := w.Body.String()
+		require.NotContains(t, body, h.noncePlaceholder)
+		require.NotContains(t, body, "{nonce}")
+		require.Equal(t, 6, strings.Count(body, `nonce="`+nonce+`"`))
+		require.Contains(t, body, `<script nonce="`+nonce+`">console.log("hello")</script>`)
+		return nonce, body
+	}
+
+	nonceA, _ := serve()
+	nonceB, _ := serve()
+	require.NotEqual(t, nonceA, nonceB)
+	require.Equal(t, int64(1), h.PageCache.Stats().Hits)
+}
+
+type cspTestCompo struct {
+	Compo
+}
+
+func (c *cspTestCompo) Render() UI {
+	return Div().
+		Title("{goapp-csp-nonce}").
+		Body(
+			Text("{goapp-csp-nonce} {nonce}"),
+			Raw(`<script nonce="{goapp-csp-nonce}">alert("injected")</script>`),
+		)
+}
+
+func TestHandlerServePageWithContentSecurityPolicyDoesNotReplaceContent(t *testing.T) {
+	h := Handler{
+		ContentSecurityPolicy: "script-src 'nonce-{nonce}'",
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/csp", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	policy := w.Header().Get("Content-Security-Policy")
+	nonce := strings.TrimSuffix(strings.TrimPrefix(policy, "script-src 'nonce-"), "'")
+
+	body := w.Body.String()
+	require.Contains(t, body, `title="{goapp-csp-nonce}"`)
+	require.Contains(t, body, `{goapp-csp-nonce} {nonce}`)
+	require.Contains(t, body, `<script nonce="{goapp-csp-nonce}">alert("injected")</script>`)
+	require.NotContains(t, body, `<script nonce="`+nonce+`">alert("injected")</script>`)
+	require.NotContains(t, body, h.noncePlaceholder)
+}
+
 func TestHandlerServePageWithLocalDir(t *testing.T) {
 	r := httptest.NewRequest(http.MethodGet, "/", nil)
 	w := httptest.NewRecorder()
@@ -205,6 +330,24 @@ func TestHandlerServeAppJSWithRemoteBucket(t *testing.T) {
 	require.Contains(t, body, `"GOAPP_ROOT_PREFIX":"/"`)
 }
 
+func TestHandlerServeAppWebWorkerJS(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/app-webworker.js", nil)
+	w := httptest.NewRecorder()
+
+	h := Handler{
+		Resources: RemoteBucket("https://storage.googleapis.com/go-app/"),
+	}
+	h.ServeHTTP(w, r)
+	body := w.Body.String()
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/javascript", w.Header().Get("Content-Type"))
+	require.Contains(t, body, `importScripts("/wasm_exec.js")`)
+	require.Contains(t, body, `fetch("https://storage.googleapis.com/go-app/web/app.wasm")`)
+	require.Contains(t, body, `"GOAPP_STATIC_RESOURCES_URL":"https://storage.googleapis.com/go-app/web"`)
+	require.Contains(t, body, `"GOAPP_WEBWORKER_URL":"/app-webworker.js"`)
+}
+
 func TestHandlerServeAppJSWithGitHubPages(t *testing.T) {
 	r := httptest.NewRequest(http.MethodGet, "/app.js", nil)
 	w := httptest.NewRecorder()
@@ -775,6 +918,15 @@ func TestParseHTTPResource(t *testing.T) {
 				LoadingMode: "defer",
 			},
 		},
+		{
+			scenario: "url with integrity is parsed",
+			in:       "https://hello.world integrity=sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC crossorigin=anonymous",
+			resource: httpResource{
+				URL:         "https://hello.world",
+				CrossOrigin: "anonymous",
+				Integrity:   "sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC",
+			},
+		},
 	}
 
 	for _, u := range utests {
@@ -808,6 +960,14 @@ func TestHTTPResourceToLink(t *testing.T) {
 		require.Equal(t, "http://hello.world", link.(*htmlLink).attributes["href"])
 		require.Equal(t, "anonymous", link.(*htmlLink).attributes["crossorigin"])
 	})
+
+	t.Run("resource is converted to link with integrity", func(t *testing.T) {
+		link := parseHTTPResource("http://hello.world integrity=sha384-Hello").toLink()
+
+		require.IsType(t, Link(), link)
+		require.Equal(t, "http://hello.world", link.(*htmlLink).attributes["href"])
+		require.Equal(t, "sha384-Hello", link.(*htmlLink).attributes["integrity"])
+	})
 }
 
 func TestHTTPResourceToScript(t *testing.T) {
@@ -852,4 +1012,64 @@ func TestHTTPResourceToScript(t *testing.T) {
 		require.Equal(t, "http://hello.world", script.(*htmlScript).attributes["src"])
 		require.Equal(t, "true", script.(*htmlScript).attributes["async"])
 	})
+
+	t.Run("resource is converted to script with integrity", func(t *testing.T) {
+		script := parseHTTPResource("http://hello.world integrity=sha384-Hello").toScript()
+
+		require.IsType(t, Script(), script)
+		require.Equal(t, "http://hello.world", script.(*htmlScript).attributes["src"])
+		require.Equal(t, "sha384-Hello", script.(*htmlScript).attributes["integrity"])
+	})
+}
+
+func TestHandlerSubresourceIntegrity(t *testing.T) {
+	close := testCreateDir(t, filepath.Join("sri-test", "web"))
+	defer close()
+	defer os.RemoveAll("sri-test")
+
+	testCreateFile(t, filepath.Join("sri-test", "web", "hello.css"), "body {}")
+	testCreateFile(t, filepath.Join("sri-test", "web", "hello.js"), "console.log('hello')")
+	cssIntegrity := "sha384-" + testSHA384(t, "body {}")
+	jsIntegrity := "sha384-" + testSHA384(t, "console.log('hello')")
+
+	h := Handler{
+		Resources:            LocalDir("sri-test"),
+		SubresourceIntegrity: true,
+		Styles: []string{
+			"/web/hello.css",
+			"/web/missing.css",
+			"https://boo.com/bar.css integrity=sha384-Remote crossorigin=anonymous",
+		},
+		Scripts: []string{"/web/hello.js defer"},
+	}
+
+	t.Run("page links have integrity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		body := w.Body.String()
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, body, `integrity="`+cssIntegrity+`"`)
+		require.Contains(t, body, `integrity="`+jsIntegrity+`"`)
+		require.Contains(t, body, `integrity="sha384-Remote"`)
+	})
+
+	t.Run("service worker verifies integrity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/app-worker.js", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		body := w.Body.String()
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, body, `"sri-test/web/hello.css":"`+cssIntegrity+`"`)
+		require.Contains(t, body, `"sri-test/web/hello.js":"`+jsIntegrity+`"`)
+		require.Contains(t, body, `"https://boo.com/bar.css":"sha384-Remote"`)
+		require.NotContains(t, body, `"sri-test/web/missing.css":`)
+	})
+}
+
+func testSHA384(t *testing.T, content string) string {
+	sum := sha512.Sum384([]byte(content))
+	return base64.StdEncoding.EncodeToString(sum[:])
 }
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/i18n"
+)
+
+const localeState = "/goapp/locale"
+
+var catalog *i18n.Catalog
+
+// SetCatalog sets the message catalog used to translate the messages returned
+// by Context.T. Like routes, it must be set on both the server and the client
+// side, typically in the main function before calling RunWhenOnBrowser.
+func SetCatalog(c *i18n.Catalog) {
+	catalog = c
+}
+
+func (e *engineX) localize(path string) string {
+	locales := appLocales()
+	if len(locales) == 0 {
+		return path
+	}
+
+	locale, path := splitLocalePath(path, locales)
+	if IsClient {
+		if locale == "" {
+			e.states.Get(e.baseContext(), localeState, &locale)
+		}
+		if locale != "" {
+			e.page().SetLang(locale)
+		}
+	}
+	return path
+}
+
+func (h *Handler) pageLocale(r *http.Request, path string) (string, string) {
+	if len(h.Locales) == 0 {
+		return h.Lang, path
+	}
+
+	if locale, path := splitLocalePath(path, h.Locales); locale != "" {
+		return locale, path
+	}
+
+	if locale := i18n.Negotiate(r.Header.Get("Accept-Language"), h.Locales...); locale != "" {
+		return locale, path
+	}
+	return h.Lang, path
+}
+
+func (h *Handler) localeAlternates(pathPrefix, path string) []Alternate {
+	alternates := make([]Alternate, 0, len(h.Locales)+1)
+	for _, locale := range h.Locales {
+		alternates = append(alternates, Alternate{
+			Lang: locale,
+			Href: pathPrefix + localizedPath(path, locale, h.Locales, h.Lang),
+		})
+	}
+	return append(alternates, Alternate{
+		Lang: "x-default",
+		Href: pathPrefix + path,
+	})
+}
+
+func appLocales() []string {
+	var locales []string
+	json.Unmarshal([]byte(Getenv("GOAPP_LOCALES")), &locales)
+	return locales
+}
+
+// splitLocalePath returns the locale that prefixes the given path and the path
+// without it. The returned locale is empty when the path is not prefixed by
+// one of the given locales.
+func splitLocalePath(path string, locales []string) (string, string) {
+	segment, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	for _, locale := range locales {
+		if strings.EqualFold(segment, locale) {
+			return locale, "/" + rest
+		}
+	}
+	return "", path
+}
+
+// localizedPath returns the given path prefixed with the given locale, unless
+// the locale is the default one.
+func localizedPath(path, locale string, locales []string, defaultLocale string) string {
+	_, path = splitLocalePath(path, locales)
+	if locale == "" || strings.EqualFold(locale, defaultLocale) {
+		return path
+	}
+	return "/" + locale + path
+}
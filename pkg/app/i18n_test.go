@@ -0,0 +1,164 @@
+//go:build !wasm
+// +build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whale1017/go-app/v10/pkg/i18n"
+)
+
+func init() {
+	Route("/i18n", func() Composer { return &i18nTestCompo{} })
+}
+
+type i18nTestCompo struct {
+	Compo
+
+	greeting string
+	items    string
+	price    string
+}
+
+func (c *i18nTestCompo) OnPreRender(ctx Context) {
+	c.greeting = ctx.T("hello")
+	c.items = ctx.T("items", 2)
+	c.price = ctx.Format().Currency(1234.5, "EUR")
+}
+
+func (c *i18nTestCompo) Render() UI {
+	return Div().Body(
+		P().ID("greeting").Text(c.greeting),
+		P().ID("items").Text(c.items),
+		P().ID("price").Text(c.price),
+	)
+}
+
+func TestHandlerServePageWithLocales(t *testing.T) {
+	var c i18n.Catalog
+	c.Set("en", "hello", i18n.Message{Other: "Hello"})
+	c.Set("en", "items", i18n.Message{One: "%d item", Other: "%d items"})
+	c.Set("fr", "hello", i18n.Message{Other: "Bonjour"})
+	c.Set("fr", "items", i18n.Message{One: "%d élément", Other: "%d éléments"})
+	SetCatalog(&c)
+	defer SetCatalog(nil)
+
+	h := Handler{
+		Domain:  "go-app.dev",
+		Locales: []string{"en", "fr"},
+	}
+
+	serve := func(path, acceptLanguage string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptLanguage != "" {
+			r.Header.Set("Accept-Language", acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("default locale", func(t *testing.T) {
+		w := serve("/i18n", "")
+		body := w.Body.String()
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+		require.Contains(t, body, `<html lang="en">`)
+		require.Contains(t, body, "Hello")
+		require.Contains(t, body, "2 items")
+		require.Contains(t, body, "€1,234.50")
+		require.Contains(t, body, `hreflang="fr"`)
+		require.Contains(t, body, `href="https://go-app.dev/fr/i18n"`)
+		require.Contains(t, body, `hreflang="x-default"`)
+	})
+
+	t.Run("locale prefixed path", func(t *testing.T) {
+		w := serve("/fr/i18n", "")
+		body := w.Body.String()
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, body, `<html lang="fr">`)
+		require.Contains(t, body, "Bonjour")
+		require.Contains(t, body, "2 éléments")
+		require.Contains(t, body, "1\u202f234,50\u00a0€")
+	})
+
+	t.Run("negotiated locale", func(t *testing.T) {
+		w := serve("/i18n", "fr-CA,fr;q=0.9,en;q=0.8")
+		body := w.Body.String()
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, body, `<html lang="fr">`)
+		require.Contains(t, body, "Bonjour")
+	})
+
+	t.Run("unsupported locale prefix is not routed", func(t *testing.T) {
+		w := serve("/de/i18n", "")
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("app.js contains locales", func(t *testing.T) {
+		w := serve("/app.js", "")
+		body := w.Body.String()
+		require.Contains(t, body, `"GOAPP_LOCALES":"[\"en\",\"fr\"]"`)
+		require.Contains(t, body, `"GOAPP_DEFAULT_LOCALE":"en"`)
+	})
+}
+
+func TestHandlerServeLocalizedSitemap(t *testing.T) {
+	h := Handler{
+		Domain:  "go-app.dev",
+		Locales: []string{"en", "fr"},
+		Sitemap: &Sitemap{},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	body := w.Body.String()
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, body, `xmlns:xhtml="http://www.w3.org/1999/xhtml"`)
+	require.Contains(t, body, `<loc>https://go-app.dev/i18n</loc>`)
+	require.Contains(t, body, `<loc>https://go-app.dev/fr/i18n</loc>`)
+	require.Contains(t, body, `<xhtml:link rel="alternate" hreflang="fr" href="https://go-app.dev/fr/i18n"></xhtml:link>`)
+	require.Contains(t, body, `<xhtml:link rel="alternate" hreflang="x-default" href="https://go-app.dev/i18n"></xhtml:link>`)
+}
+
+func TestSplitLocalePath(t *testing.T) {
+	locales := []string{"en", "fr", "pt-BR"}
+
+	tests := []struct {
+		path           string
+		expectedLocale string
+		expectedPath   string
+	}{
+		{path: "/", expectedLocale: "", expectedPath: "/"},
+		{path: "/about", expectedLocale: "", expectedPath: "/about"},
+		{path: "/fr", expectedLocale: "fr", expectedPath: "/"},
+		{path: "/fr/", expectedLocale: "fr", expectedPath: "/"},
+		{path: "/fr/about", expectedLocale: "fr", expectedPath: "/about"},
+		{path: "/pt-br/about", expectedLocale: "pt-BR", expectedPath: "/about"},
+		{path: "/french/about", expectedLocale: "", expectedPath: "/french/about"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			locale, path := splitLocalePath(test.path, locales)
+			require.Equal(t, test.expectedLocale, locale)
+			require.Equal(t, test.expectedPath, path)
+		})
+	}
+}
+
+func TestLocalizedPath(t *testing.T) {
+	locales := []string{"en", "fr"}
+
+	require.Equal(t, "/about", localizedPath("/about", "en", locales, "en"))
+	require.Equal(t, "/fr/about", localizedPath("/about", "fr", locales, "en"))
+	require.Equal(t, "/fr/", localizedPath("/", "fr", locales, "en"))
+	require.Equal(t, "/about", localizedPath("/fr/about", "en", locales, "en"))
+	require.Equal(t, "/about", localizedPath("/fr/about", "", locales, "en"))
+}
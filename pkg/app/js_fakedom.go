@@ -0,0 +1,54 @@
+//go:build !wasm && fakedom
+// +build !wasm,fakedom
+
+package app
+
+// With the fakedom build tag, JavaScript values on non-wasm architectures are
+// backed by the in-memory DOM rather than being no-ops, so that test engines
+// can render components and simulate events without a browser.
+
+func null() Value {
+	return fakeNull
+}
+
+func undefined() Value {
+	return fakeUndefined
+}
+
+func valueOf(x any) Value {
+	return fakeValueOf(x)
+}
+
+type function struct {
+	fakeValue
+}
+
+func (f function) Release() {
+	f.obj.fn = nil
+}
+
+func funcOf(fn func(this Value, args []Value) any) Func {
+	return function{fakeValue: fakeFunc(fn)}
+}
+
+func newBrowserWindow() BrowserWindow {
+	return newFakeWindow()
+}
+
+func newTestWindow() BrowserWindow {
+	return newFakeWindow()
+}
+
+func copyBytesToGo(dst []byte, src Value) int {
+	if b, ok := fakeBytesOf(src); ok {
+		return copy(dst, b.b)
+	}
+	return 0
+}
+
+func copyBytesToJS(dst Value, src []byte) int {
+	if b, ok := fakeBytesOf(dst); ok {
+		return copy(b.b, src)
+	}
+	return 0
+}
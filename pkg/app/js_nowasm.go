@@ -1,5 +1,5 @@
-//go:build !wasm
-// +build !wasm
+//go:build !wasm && !fakedom
+// +build !wasm,!fakedom
 
 package app
 
@@ -118,6 +118,9 @@ func (v value) appendChild(c Wrapper) {
 func (v value) replaceChild(new, old Wrapper) {
 }
 
+func (v value) insertBefore(new, ref Wrapper) {
+}
+
 func (v value) removeChild(c Wrapper) {
 }
 
@@ -163,6 +166,10 @@ func funcOf(fn func(this Value, args []Value) any) Func {
 	return function{value: value{}}
 }
 
+func newTestWindow() BrowserWindow {
+	return newBrowserWindow()
+}
+
 type browserWindow struct {
 	value
 }
@@ -0,0 +1,36 @@
+//go:build !wasm && !fakedom
+// +build !wasm,!fakedom
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPromiseOnServer(t *testing.T) {
+	ctx := context.Background()
+
+	called := false
+	promise := NewPromise(ctx, func(ctx context.Context) (any, error) {
+		called = true
+		return nil, nil
+	})
+	require.True(t, promise.IsUndefined())
+	require.False(t, called)
+
+	_, err := Await(ctx, promise)
+	require.Error(t, err)
+}
+
+func TestTestEngineEventsWithoutDOM(t *testing.T) {
+	e := NewTestEngine()
+	require.NoError(t, e.Load(&hello{}))
+	e.ConsumeAll()
+
+	err := e.Click("h1")
+	require.Error(t, err)
+	t.Log(err)
+}
@@ -106,6 +106,10 @@ func (v value) replaceChild(new, old Wrapper) {
 	v.Call("replaceChild", new, old)
 }
 
+func (v value) insertBefore(new, ref Wrapper) {
+	v.Call("insertBefore", new, ref)
+}
+
 func (v value) removeChild(c Wrapper) {
 	v.Call("removeChild", c)
 }
@@ -155,7 +159,7 @@ func valueOf(v any) Value {
 		return v.JSValue()
 
 	default:
-		return value{jsValue: js.ValueOf(v)}
+		return value{jsValue: syscalJSValueOf(v)}
 	}
 }
 
@@ -256,6 +260,10 @@ func newBrowserWindow() *browserWindow {
 	return &browserWindow{value: value{jsValue: js.Global()}}
 }
 
+func newTestWindow() BrowserWindow {
+	return newBrowserWindow()
+}
+
 func (w *browserWindow) URL() *url.URL {
 	rawurl := w.
 		Get("location").
@@ -0,0 +1,245 @@
+package app
+
+// ManifestIcon describes an image used by a web app manifest member such as a
+// shortcut.
+type ManifestIcon struct {
+	// The path or URL of the image.
+	Src string `json:"src"`
+
+	// The sizes of the image, e.g. "192x192" or "any".
+	Sizes string `json:"sizes,omitempty"`
+
+	// The MIME type of the image, e.g. "image/png".
+	Type string `json:"type,omitempty"`
+
+	// The purpose of the image: "any", "maskable" or "monochrome".
+	Purpose string `json:"purpose,omitempty"`
+}
+
+// Shortcut describes an entry of the context menu displayed when the installed
+// app icon is long-pressed or right-clicked.
+type Shortcut struct {
+	// The shortcut name displayed to the user.
+	Name string `json:"name"`
+
+	// An abbreviated name used when space is limited.
+	ShortName string `json:"short_name,omitempty"`
+
+	// A description of what the shortcut does.
+	Description string `json:"description,omitempty"`
+
+	// The path of the page opened by the shortcut.
+	URL string `json:"url"`
+
+	// The images representing the shortcut.
+	Icons []ManifestIcon `json:"icons,omitempty"`
+}
+
+// ShareTarget registers the installed app as a target of the operating system
+// share dialog. Shared data is delivered to the components that implement
+// the ShareReceiver interface.
+type ShareTarget struct {
+	// The path of the page opened when data is shared with the app. A route
+	// must be defined for it. Defaults to "/share".
+	Action string
+
+	// The files the app accepts. When set, shared data is posted as a
+	// multipart form intercepted by the service worker. Otherwise, the title,
+	// text and url are passed as query parameters.
+	Files []ShareTargetFile
+}
+
+// ShareTargetFile describes files accepted by a share target.
+type ShareTargetFile struct {
+	// The name of the form field that contains the files.
+	Name string `json:"name"`
+
+	// The accepted MIME types or file extensions, e.g. "image/*" or ".txt".
+	Accept []string `json:"accept"`
+}
+
+// FileHandler registers the installed app as a handler for files of the given
+// types. Opened files are delivered to the components that implement the
+// ShareReceiver interface.
+type FileHandler struct {
+	// The path of the page opened when files are opened with the app.
+	Action string `json:"action"`
+
+	// The handled MIME types with their associated file extensions, e.g.
+	// {"text/markdown": {".md"}}.
+	Accept map[string][]string `json:"accept"`
+}
+
+// ProtocolHandler registers the installed app as a handler for URLs with the
+// given protocol.
+type ProtocolHandler struct {
+	// The handled protocol, e.g. "mailto" or "web+goapp".
+	Protocol string `json:"protocol"`
+
+	// The URL of the page that handles the protocol. It must contain "%s",
+	// which is replaced by the escaped URL that triggered the handler.
+	URL string `json:"url"`
+}
+
+// Screenshot describes an image of the app displayed in install dialogs and
+// app stores.
+type Screenshot struct {
+	// The path or URL of the image.
+	Src string `json:"src"`
+
+	// The sizes of the image, e.g. "1280x720".
+	Sizes string `json:"sizes,omitempty"`
+
+	// The MIME type of the image, e.g. "image/png".
+	Type string `json:"type,omitempty"`
+
+	// The form factor the screenshot applies to: "narrow" or "wide".
+	FormFactor string `json:"form_factor,omitempty"`
+
+	// An accessible description of the screenshot.
+	Label string `json:"label,omitempty"`
+}
+
+type manifestShareTarget struct {
+	Action  string                    `json:"action"`
+	Method  string                    `json:"method"`
+	Enctype string                    `json:"enctype"`
+	Params  manifestShareTargetParams `json:"params"`
+}
+
+type manifestShareTargetParams struct {
+	Title string            `json:"title"`
+	Text  string            `json:"text"`
+	URL   string            `json:"url"`
+	Files []ShareTargetFile `json:"files,omitempty"`
+}
+
+func (h *Handler) shareTargetAction(v *appVariant) string {
+	if h.ShareTarget == nil {
+		return ""
+	}
+
+	action := h.ShareTarget.Action
+	if action == "" {
+		action = "/share"
+	}
+	return h.Resources.Resolve(v.PathPrefix + action)
+}
+
+func (h *Handler) manifestShortcuts(v *appVariant) string {
+	if len(h.Shortcuts) == 0 {
+		return ""
+	}
+
+	shortcuts := make([]Shortcut, len(h.Shortcuts))
+	for i, s := range h.Shortcuts {
+		s.URL = h.Resources.Resolve(v.PathPrefix + s.URL)
+		s.Icons = h.manifestIcons(s.Icons)
+		shortcuts[i] = s
+	}
+	return jsonString(shortcuts)
+}
+
+func (h *Handler) manifestIcons(icons []ManifestIcon) []ManifestIcon {
+	if len(icons) == 0 {
+		return nil
+	}
+
+	resolved := make([]ManifestIcon, len(icons))
+	for i, icon := range icons {
+		icon.Src = h.Resources.Resolve(icon.Src)
+		resolved[i] = icon
+	}
+	return resolved
+}
+
+func (h *Handler) manifestShareTarget(v *appVariant) string {
+	if h.ShareTarget == nil {
+		return ""
+	}
+
+	shareTarget := manifestShareTarget{
+		Action:  h.shareTargetAction(v),
+		Method:  "GET",
+		Enctype: "application/x-www-form-urlencoded",
+		Params: manifestShareTargetParams{
+			Title: "title",
+			Text:  "text",
+			URL:   "url",
+		},
+	}
+	if len(h.ShareTarget.Files) != 0 {
+		shareTarget.Method = "POST"
+		shareTarget.Enctype = "multipart/form-data"
+		shareTarget.Params.Files = h.ShareTarget.Files
+	}
+	return jsonString(shareTarget)
+}
+
+func (h *Handler) manifestFileHandlers(v *appVariant) string {
+	if len(h.FileHandlers) == 0 {
+		return ""
+	}
+
+	fileHandlers := make([]FileHandler, len(h.FileHandlers))
+	for i, fh := range h.FileHandlers {
+		fh.Action = h.Resources.Resolve(v.PathPrefix + fh.Action)
+		fileHandlers[i] = fh
+	}
+	return jsonString(fileHandlers)
+}
+
+func (h *Handler) manifestProtocolHandlers(v *appVariant) string {
+	if len(h.ProtocolHandlers) == 0 {
+		return ""
+	}
+
+	protocolHandlers := make([]ProtocolHandler, len(h.ProtocolHandlers))
+	for i, ph := range h.ProtocolHandlers {
+		if !remoteLocation(ph.URL) {
+			ph.URL = h.Resources.Resolve(v.PathPrefix + ph.URL)
+		}
+		protocolHandlers[i] = ph
+	}
+	return jsonString(protocolHandlers)
+}
+
+func (h *Handler) manifestScreenshots() string {
+	if len(h.Screenshots) == 0 {
+		return ""
+	}
+
+	screenshots := make([]Screenshot, len(h.Screenshots))
+	for i, s := range h.Screenshots {
+		s.Src = h.Resources.Resolve(s.Src)
+		screenshots[i] = s
+	}
+	return jsonString(screenshots)
+}
+
+func manifestUserPreferences(v *appVariant) string {
+	if v.DarkBackgroundColor == "" && v.DarkThemeColor == "" {
+		return ""
+	}
+
+	dark := map[string]string{
+		"background_color": v.BackgroundColor,
+		"theme_color":      v.ThemeColor,
+	}
+	if v.DarkBackgroundColor != "" {
+		dark["background_color"] = v.DarkBackgroundColor
+	}
+	if v.DarkThemeColor != "" {
+		dark["theme_color"] = v.DarkThemeColor
+	}
+	return jsonString(map[string]any{
+		"color_scheme_dark": dark,
+	})
+}
+
+func manifestStrings(v []string) string {
+	if len(v) == 0 {
+		return ""
+	}
+	return jsonString(v)
+}
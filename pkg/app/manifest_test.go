@@ -0,0 +1,173 @@
+//go:build !wasm
+// +build !wasm
+
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerServeManifestJSONWithExtensions(t *testing.T) {
+	h := Handler{
+		Resources: GitHubPages("go-app"),
+		Shortcuts: []Shortcut{
+			{
+				Name: "New note",
+				URL:  "/notes/new",
+				Icons: []ManifestIcon{
+					{Src: "/web/new.png", Sizes: "96x96"},
+				},
+			},
+		},
+		ShareTarget: &ShareTarget{
+			Files: []ShareTargetFile{
+				{Name: "images", Accept: []string{"image/*"}},
+			},
+		},
+		FileHandlers: []FileHandler{
+			{
+				Action: "/open",
+				Accept: map[string][]string{"text/markdown": {".md"}},
+			},
+		},
+		ProtocolHandlers: []ProtocolHandler{
+			{Protocol: "web+note", URL: "/notes?uri=%s"},
+		},
+		Screenshots: []Screenshot{
+			{Src: "/web/wide.png", Sizes: "1280x720", FormFactor: "wide"},
+		},
+		DisplayOverride: []string{"window-controls-overlay"},
+		Categories:      []string{"productivity"},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/manifest.webmanifest", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var manifest map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &manifest)
+	require.NoError(t, err)
+
+	require.Equal(t, []any{
+		map[string]any{
+			"name": "New note",
+			"url":  "/go-app/notes/new",
+			"icons": []any{
+				map[string]any{"src": "/go-app/web/new.png", "sizes": "96x96"},
+			},
+		},
+	}, manifest["shortcuts"])
+
+	require.Equal(t, map[string]any{
+		"action":  "/go-app/share",
+		"method":  "POST",
+		"enctype": "multipart/form-data",
+		"params": map[string]any{
+			"title": "title",
+			"text":  "text",
+			"url":   "url",
+			"files": []any{
+				map[string]any{"name": "images", "accept": []any{"image/*"}},
+			},
+		},
+	}, manifest["share_target"])
+
+	require.Equal(t, []any{
+		map[string]any{
+			"action": "/go-app/open",
+			"accept": map[string]any{"text/markdown": []any{".md"}},
+		},
+	}, manifest["file_handlers"])
+
+	require.Equal(t, []any{
+		map[string]any{"protocol": "web+note", "url": "/go-app/notes?uri=%s"},
+	}, manifest["protocol_handlers"])
+
+	require.Equal(t, []any{
+		map[string]any{
+			"src":         "/go-app/web/wide.png",
+			"sizes":       "1280x720",
+			"form_factor": "wide",
+		},
+	}, manifest["screenshots"])
+
+	require.Equal(t, []any{"window-controls-overlay"}, manifest["display_override"])
+	require.Equal(t, []any{"productivity"}, manifest["categories"])
+}
+
+func TestHandlerServeManifestJSONWithoutExtensions(t *testing.T) {
+	h := Handler{}
+
+	r := httptest.NewRequest(http.MethodGet, "/manifest.webmanifest", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var manifest map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &manifest)
+	require.NoError(t, err)
+	require.NotContains(t, manifest, "shortcuts")
+	require.NotContains(t, manifest, "share_target")
+	require.NotContains(t, manifest, "user_preferences")
+	require.Equal(t, "standalone", manifest["display"])
+}
+
+func TestHandlerServeManifestJSONWithDarkColors(t *testing.T) {
+	h := Handler{
+		BackgroundColor: "#ffffff",
+		ThemeColor:      "#ffffff",
+		DarkThemeColor:  "#202124",
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/manifest.webmanifest", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var manifest map[string]any
+	err := json.Unmarshal(w.Body.Bytes(), &manifest)
+	require.NoError(t, err)
+	require.Equal(t, "#ffffff", manifest["theme_color"])
+	require.Equal(t, map[string]any{
+		"color_scheme_dark": map[string]any{
+			"background_color": "#ffffff",
+			"theme_color":      "#202124",
+		},
+	}, manifest["user_preferences"])
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	require.Contains(t, body, `media="(prefers-color-scheme: light)"`)
+	require.Contains(t, body, `media="(prefers-color-scheme: dark)"`)
+	require.Contains(t, body, `content="#202124"`)
+}
+
+func TestHandlerServeShareTargetScripts(t *testing.T) {
+	h := Handler{
+		ShareTarget: &ShareTarget{Action: "/receive"},
+	}
+
+	serve := func(path string) string {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	require.Contains(t, serve("/app.js"), `const goappShareTargetAction = "/receive";`)
+	worker := serve("/app-worker.js")
+	require.Contains(t, worker, `const shareTargetAction = "/receive";`)
+	require.Contains(t, worker, `await cache.put(sharedDataKey(), new Response(form));`)
+	require.Contains(t, serve("/manifest.webmanifest"), `"method":"GET"`)
+}
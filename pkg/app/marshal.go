@@ -0,0 +1,671 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+var (
+	valueType   = reflect.TypeOf((*Value)(nil)).Elem()
+	wrapperType = reflect.TypeOf((*Wrapper)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+	bytesType   = reflect.TypeOf([]byte(nil))
+)
+
+// MarshalJS returns the JavaScript value that represents the given Go value:
+//
+//	| Go                          | JavaScript                       |
+//	| --------------------------- | -------------------------------- |
+//	| nil, nil pointer, map, etc. | null                             |
+//	| Value, Wrapper              | [its value]                      |
+//	| bool                        | boolean                          |
+//	| integers and floats         | number                           |
+//	| string                      | string                           |
+//	| time.Time                   | Date                             |
+//	| []byte                      | Uint8Array                       |
+//	| error                       | Error                            |
+//	| slices and arrays           | Array                            |
+//	| maps with string keys       | object                           |
+//	| structs                     | object                           |
+//
+// Struct fields are named after their js tag, or after the field name when
+// they do not have one. Like with encoding/json, a field tagged with "-" is
+// ignored, the "omitempty" option omits the field when it has a zero value,
+// and the fields of untagged embedded structs are promoted:
+//
+//	type User struct {
+//		Name    string    `js:"name"`
+//		Email   string    `js:"email,omitempty"`
+//		Created time.Time `js:"created"`
+//		Secret  string    `js:"-"`
+//	}
+//
+// It returns an error when the value contains channels, functions or complex
+// numbers, or when it contains a cycle, such as a pointer to a struct that
+// refers to itself.
+func MarshalJS(v any) (Value, error) {
+	x, err := marshalJS(reflect.ValueOf(v), "")
+	if err != nil {
+		return nil, err
+	}
+	return ValueOf(x), nil
+}
+
+func marshalJS(v reflect.Value, path string) (any, error) {
+	var m jsMarshaler
+	return m.marshal(v, path)
+}
+
+// jsMarshaler converts Go values to values that can be passed to ValueOf. Like
+// encoding/json, it tracks the pointers, maps and slices being marshaled in
+// order to report cycles rather than recursing endlessly.
+type jsMarshaler struct {
+	visiting map[jsVisit]struct{}
+}
+
+type jsVisit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+func (m *jsMarshaler) marshal(v reflect.Value, path string) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		visit := jsVisit{
+			ptr: v.Pointer(),
+			typ: v.Type(),
+		}
+		if v.Kind() == reflect.Slice {
+			visit.len = v.Len()
+		}
+
+		if _, ok := m.visiting[visit]; ok {
+			return nil, errors.New("marshaling go value to javascript failed").
+				WithTag("path", path).
+				WithTag("type", v.Type()).
+				Wrap(errors.New("cycle detected"))
+		}
+		if m.visiting == nil {
+			m.visiting = make(map[jsVisit]struct{})
+		}
+		m.visiting[visit] = struct{}{}
+		defer delete(m.visiting, visit)
+	}
+
+	switch t := v.Type(); {
+	case t.Implements(wrapperType):
+		return v.Interface().(Wrapper).JSValue(), nil
+
+	case t == timeType:
+		t := v.Interface().(time.Time)
+		return Window().Get("Date").New(float64(t.UnixMilli())), nil
+
+	case t == bytesType:
+		b := v.Bytes()
+		array := Window().Get("Uint8Array").New(len(b))
+		CopyBytesToJS(array, b)
+		return array, nil
+
+	case t.Implements(errorType):
+		return marshalJSError(v.Interface().(error)), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return m.marshal(v.Elem(), path)
+
+	case reflect.Bool:
+		return v.Bool(), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), nil
+
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+
+	case reflect.String:
+		return v.String(), nil
+
+	case reflect.Slice, reflect.Array:
+		array := make([]any, v.Len())
+		for i := range array {
+			item, err := m.marshal(v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			array[i] = item
+		}
+		return array, nil
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		object := make(map[string]any, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			k := it.Key().String()
+			item, err := m.marshal(it.Value(), path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			object[k] = item
+		}
+		return object, nil
+
+	case reflect.Struct:
+		object := make(map[string]any)
+		for _, f := range jsFields(v.Type()) {
+			fv := v.FieldByIndex(f.index)
+			if f.omitEmpty && fv.IsZero() {
+				continue
+			}
+
+			item, err := m.marshal(fv, path+"."+f.name)
+			if err != nil {
+				return nil, err
+			}
+			object[f.name] = item
+		}
+		return object, nil
+	}
+
+	return nil, errors.New("marshaling go value to javascript failed").
+		WithTag("path", path).
+		WithTag("type", v.Type()).
+		Wrap(errors.New("unsupported type"))
+}
+
+// marshalJSError returns a JavaScript Error that represents the given error.
+// The type, the tags and the wrapped error of errors created with the errors
+// package are set as the type, tags and cause properties of the JavaScript
+// Error.
+func marshalJSError(err error) Value {
+	props := jsErrorProperties(err)
+	jsErr := Window().Get("Error").New(props["message"])
+	for k, v := range props {
+		switch k {
+		case "message":
+		case "cause":
+			jsErr.Set(k, marshalJSError(err.(errors.Error).WrappedErr))
+		default:
+			jsErr.Set(k, v)
+		}
+	}
+	return jsErr
+}
+
+// jsErrorProperties returns the properties of the JavaScript object that
+// represents the given error, with the cause as a nested object.
+func jsErrorProperties(err error) map[string]any {
+	e, ok := err.(errors.Error)
+	if !ok {
+		return map[string]any{"message": err.Error()}
+	}
+
+	props := map[string]any{"message": e.Message}
+	if e.DefinedType != "" {
+		props["type"] = e.DefinedType
+	}
+	if len(e.Tags) != 0 {
+		tags := make(map[string]any, len(e.Tags))
+		for k, v := range e.Tags {
+			tag, err := marshalJS(reflect.ValueOf(v), "")
+			if err != nil {
+				tag = fmt.Sprint(v)
+			}
+			tags[k] = tag
+		}
+		props["tags"] = tags
+	}
+	if e.WrappedErr != nil {
+		props["cause"] = jsErrorProperties(e.WrappedErr)
+	}
+	return props
+}
+
+// UnmarshalJS stores the given JavaScript value into the Go value pointed to
+// by dst, following the reverse of the mapping described in MarshalJS:
+//
+//   - Numbers are stored in integers only when they fit without loss.
+//   - Dates, timestamps in milliseconds and RFC 3339 strings are stored in
+//     time.Time values.
+//   - Uint8Array and ArrayBuffer values are stored in []byte values.
+//   - Error objects and strings are stored in error values. The name, type,
+//     tags and cause properties of objects are set as the tags, type and
+//     wrapped error of the stored error.
+//   - Objects are stored in structs and in maps with string keys. Object
+//     properties that do not match a struct field are ignored.
+//   - Values stored in empty interfaces become bool, float64, string,
+//     time.Time, []byte, []any, map[string]any or nil.
+//   - Values are stored as is in fields of type Value.
+//
+// Like with encoding/json, null and undefined set pointers, maps, slices and
+// interfaces to nil, and leave other values unchanged.
+func UnmarshalJS(v Value, dst any) (err error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("unmarshaling javascript value failed").
+			WithTag("type", reflect.TypeOf(dst)).
+			Wrap(errors.New("destination is not a non-nil pointer"))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("unmarshaling javascript value failed").
+				WithTag("type", reflect.TypeOf(dst)).
+				WithTag("panic", fmt.Sprint(r))
+		}
+	}()
+	return unmarshalJS(v, rv.Elem(), "")
+}
+
+// ErrorOf returns the error that represents the given JavaScript value, such
+// as the reason of a rejected promise or the error of a failed browser API
+// call.
+//
+// Error objects and strings are converted like with UnmarshalJS, which
+// preserves the errors marshaled with MarshalJS. Other values are converted to
+// an error whose reason tag is the value, as stored in empty interfaces by
+// UnmarshalJS.
+func ErrorOf(v Value) error {
+	if v == nil || v.IsUndefined() || v.IsNull() {
+		return errors.New("javascript error")
+	}
+	if err, ok := jsErrorValue(v); ok {
+		return err
+	}
+	return errors.New("javascript error").WithTag("reason", jsToAny(v))
+}
+
+func unmarshalJS(v Value, dst reflect.Value, path string) error {
+	t := dst.Type()
+	if t == valueType {
+		dst.Set(reflect.ValueOf(v))
+		return nil
+	}
+
+	if v == nil || v.IsUndefined() || v.IsNull() {
+		switch dst.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+			dst.Set(reflect.Zero(t))
+		}
+		return nil
+	}
+
+	switch {
+	case t == timeType:
+		tm, ok := jsTime(v)
+		if !ok {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		dst.Set(reflect.ValueOf(tm))
+		return nil
+
+	case t == bytesType:
+		b, ok := jsBytes(v)
+		if !ok {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		dst.SetBytes(b)
+		return nil
+
+	case t == errorType:
+		err, ok := jsErrorValue(v)
+		if !ok {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		dst.Set(reflect.ValueOf(&err).Elem())
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		if dst.IsNil() {
+			dst.Set(reflect.New(t.Elem()))
+		}
+		return unmarshalJS(v, dst.Elem(), path)
+
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		if x := jsToAny(v); x != nil {
+			dst.Set(reflect.ValueOf(x))
+		} else {
+			dst.Set(reflect.Zero(t))
+		}
+		return nil
+
+	case reflect.Bool:
+		if v.Type() != TypeBoolean {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		dst.SetBool(v.Bool())
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, ok := jsNumber(v)
+		if !ok || f != math.Trunc(f) || dst.OverflowInt(int64(f)) {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		dst.SetInt(int64(f))
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f, ok := jsNumber(v)
+		if !ok || f < 0 || f != math.Trunc(f) || dst.OverflowUint(uint64(f)) {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		dst.SetUint(uint64(f))
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		f, ok := jsNumber(v)
+		if !ok || dst.OverflowFloat(f) {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		dst.SetFloat(f)
+		return nil
+
+	case reflect.String:
+		if v.Type() != TypeString {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		dst.SetString(v.String())
+		return nil
+
+	case reflect.Slice:
+		if !isJSArray(v) {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		n := v.Length()
+		s := reflect.MakeSlice(t, n, n)
+		for i := 0; i < n; i++ {
+			if err := unmarshalJS(v.Index(i), s.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(s)
+		return nil
+
+	case reflect.Array:
+		if !isJSArray(v) {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		n := v.Length()
+		for i := 0; i < dst.Len(); i++ {
+			if i >= n {
+				dst.Index(i).Set(reflect.Zero(t.Elem()))
+				continue
+			}
+			if err := unmarshalJS(v.Index(i), dst.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		if v.Type() != TypeObject || t.Key().Kind() != reflect.String {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(t))
+		}
+		keys := jsKeys(v)
+		for _, k := range keys {
+			item := reflect.New(t.Elem()).Elem()
+			if err := unmarshalJS(v.Get(k), item, path+"."+k); err != nil {
+				return err
+			}
+			dst.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), item)
+		}
+		return nil
+
+	case reflect.Struct:
+		if v.Type() != TypeObject {
+			return unmarshalJSTypeError(v, t, path)
+		}
+		for _, f := range jsFields(t) {
+			fv := v.Get(f.name)
+			if fv.IsUndefined() {
+				continue
+			}
+			if err := unmarshalJS(fv, fieldByIndexAlloc(dst, f.index), path+"."+f.name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return unmarshalJSTypeError(v, t, path)
+}
+
+func unmarshalJSTypeError(v Value, t reflect.Type, path string) error {
+	return errors.New("unmarshaling javascript value failed").
+		WithTag("path", path).
+		WithTag("type", t).
+		WithTag("javascript-type", v.Type()).
+		Wrap(errors.New("incompatible types"))
+}
+
+func jsNumber(v Value) (float64, bool) {
+	if v.Type() != TypeNumber || v.IsNaN() {
+		return 0, false
+	}
+	return v.Float(), true
+}
+
+func jsTime(v Value) (time.Time, bool) {
+	switch v.Type() {
+	case TypeNumber:
+		return time.UnixMilli(int64(v.Float())), true
+
+	case TypeString:
+		t, err := time.Parse(time.RFC3339Nano, v.String())
+		return t, err == nil
+
+	case TypeObject:
+		if v.InstanceOf(Window().Get("Date")) {
+			return time.UnixMilli(int64(v.Call("getTime").Float())), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func jsBytes(v Value) ([]byte, bool) {
+	if v.Type() != TypeObject {
+		return nil, false
+	}
+
+	if buffer := Window().Get("ArrayBuffer"); buffer.Truthy() && v.InstanceOf(buffer) {
+		v = Window().Get("Uint8Array").New(v)
+	}
+	if !v.InstanceOf(Window().Get("Uint8Array")) {
+		return nil, false
+	}
+
+	b := make([]byte, v.Get("byteLength").Int())
+	CopyBytesToGo(b, v)
+	return b, true
+}
+
+func jsErrorValue(v Value) (error, bool) {
+	switch v.Type() {
+	case TypeString:
+		return errors.New(v.String()), true
+
+	case TypeObject:
+		message := v.Get("message")
+		if message.Type() != TypeString {
+			return nil, false
+		}
+
+		err := errors.New(message.String())
+		if name := v.Get("name"); name.Type() == TypeString {
+			err = err.WithTag("name", name.String())
+		}
+		if typ := v.Get("type"); typ.Type() == TypeString {
+			err = err.WithType(typ.String())
+		}
+		if tags := v.Get("tags"); tags.Type() == TypeObject {
+			for _, k := range jsKeys(tags) {
+				err = err.WithTag(k, jsToAny(tags.Get(k)))
+			}
+		}
+		if cause, ok := jsErrorValue(v.Get("cause")); ok {
+			err = err.Wrap(cause)
+		}
+		return err, true
+	}
+	return nil, false
+}
+
+func jsToAny(v Value) any {
+	switch v.Type() {
+	case TypeBoolean:
+		return v.Bool()
+
+	case TypeNumber:
+		return v.Float()
+
+	case TypeString:
+		return v.String()
+
+	case TypeObject:
+		if t, ok := jsTime(v); ok {
+			return t
+		}
+		if b, ok := jsBytes(v); ok {
+			return b
+		}
+		if isJSArray(v) {
+			array := make([]any, v.Length())
+			for i := range array {
+				array[i] = jsToAny(v.Index(i))
+			}
+			return array
+		}
+
+		object := make(map[string]any)
+		for _, k := range jsKeys(v) {
+			object[k] = jsToAny(v.Get(k))
+		}
+		return object
+
+	case TypeFunction:
+		return v
+
+	default:
+		return nil
+	}
+}
+
+func isJSArray(v Value) bool {
+	return v.Type() == TypeObject && v.Get("length").Type() == TypeNumber
+}
+
+func jsKeys(v Value) []string {
+	keys := Window().Get("Object").Call("keys", v)
+	s := make([]string, keys.Length())
+	for i := range s {
+		s[i] = keys.Index(i).String()
+	}
+	return s
+}
+
+// fieldByIndexAlloc returns the field of the given struct at the given index,
+// allocating the embedded struct pointers it goes through.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+type jsField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// jsFields returns the fields of the given struct type that are mapped to
+// JavaScript object properties.
+func jsFields(t reflect.Type) []jsField {
+	var fields []jsField
+	names := make(map[string]int)
+
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag, hasTag := f.Tag.Lookup("js")
+			if tag == "-" {
+				continue
+			}
+
+			fieldIndex := append(append([]int{}, index...), i)
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if f.Anonymous && !hasTag && ft.Kind() == reflect.Struct && ft != timeType {
+				walk(ft, fieldIndex)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+
+			name, options, _ := strings.Cut(tag, ",")
+			if name == "" {
+				name = f.Name
+			}
+
+			field := jsField{
+				name:      name,
+				index:     fieldIndex,
+				omitEmpty: options == "omitempty",
+			}
+
+			// Like with encoding/json, shallower fields hide deeper ones.
+			if j, ok := names[name]; ok {
+				if len(fields[j].index) > len(field.index) {
+					fields[j] = field
+				}
+				continue
+			}
+			names[name] = len(fields)
+			fields = append(fields, field)
+		}
+	}
+	walk(t, nil)
+	return fields
+}
@@ -0,0 +1,305 @@
+//go:build !wasm && fakedom
+// +build !wasm,fakedom
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+type marshalTestBase struct {
+	ID string `js:"id"`
+}
+
+type marshalTestUser struct {
+	marshalTestBase
+	Name     string            `js:"name"`
+	Email    string            `js:"email,omitempty"`
+	Age      int               `js:"age"`
+	Tags     []string          `js:"tags"`
+	Settings map[string]any    `js:"settings"`
+	Created  time.Time         `js:"created"`
+	Avatar   []byte            `js:"avatar"`
+	Friend   *marshalTestUser  `js:"friend"`
+	Scores   map[string]uint16 `js:"scores"`
+	Untagged bool
+	Secret   string `js:"-"`
+	private  string
+}
+
+func TestMarshalJS(t *testing.T) {
+	NewTestEngine()
+
+	t.Run("struct", func(t *testing.T) {
+		created := time.Date(2024, 5, 17, 10, 30, 0, 0, time.UTC)
+		v, err := MarshalJS(marshalTestUser{
+			marshalTestBase: marshalTestBase{ID: "u1"},
+			Name:            "Maxence",
+			Age:             42,
+			Tags:            []string{"go", "wasm"},
+			Created:         created,
+			Avatar:          []byte("png"),
+			Friend:          &marshalTestUser{Name: "Jonhy"},
+			Untagged:        true,
+			Secret:          "hunter2",
+			private:         "private",
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "u1", v.Get("id").String())
+		require.Equal(t, "Maxence", v.Get("name").String())
+		require.True(t, v.Get("email").IsUndefined())
+		require.Equal(t, 42, v.Get("age").Int())
+		require.Equal(t, 2, v.Get("tags").Length())
+		require.Equal(t, "wasm", v.Get("tags").Index(1).String())
+		require.True(t, v.Get("settings").IsNull())
+		require.True(t, v.Get("created").InstanceOf(Window().Get("Date")))
+		require.Equal(t, float64(created.UnixMilli()), v.Get("created").Call("getTime").Float())
+		require.True(t, v.Get("avatar").InstanceOf(Window().Get("Uint8Array")))
+		require.Equal(t, 3, v.Get("avatar").Length())
+		require.Equal(t, "Jonhy", v.Get("friend").Get("name").String())
+		require.True(t, v.Get("friend").Get("friend").IsNull())
+		require.True(t, v.Get("Untagged").Bool())
+		require.True(t, v.Get("Secret").IsUndefined())
+		require.True(t, v.Get("private").IsUndefined())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		v, err := MarshalJS(nil)
+		require.NoError(t, err)
+		require.True(t, v.IsNull())
+	})
+
+	t.Run("value", func(t *testing.T) {
+		div := Window().Get("document").Call("createElement", "div")
+		v, err := MarshalJS(map[string]any{"element": div})
+		require.NoError(t, err)
+		require.True(t, v.Get("element").Equal(div))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		v, err := MarshalJS(errors.New("saving failed").
+			WithType("io").
+			WithTag("id", 42).
+			Wrap(errors.New("disk full")))
+		require.NoError(t, err)
+		require.True(t, v.InstanceOf(Window().Get("Error")))
+		require.Equal(t, "saving failed", v.Get("message").String())
+		require.Equal(t, "io", v.Get("type").String())
+		require.Equal(t, 42, v.Get("tags").Get("id").Int())
+		require.Equal(t, "disk full", v.Get("cause").Get("message").String())
+	})
+
+	t.Run("unsupported type returns an error", func(t *testing.T) {
+		_, err := MarshalJS(map[string]any{"c": make(chan int)})
+		require.Error(t, err)
+		require.Equal(t, ".c", errors.Tag(err, "path"))
+
+		_, err = MarshalJS(map[int]string{1: "one"})
+		require.Error(t, err)
+	})
+
+	t.Run("cycle returns an error", func(t *testing.T) {
+		user := &marshalTestUser{Name: "Maxence"}
+		user.Friend = user
+		_, err := MarshalJS(user)
+		require.Error(t, err)
+		require.Equal(t, ".friend", errors.Tag(err, "path"))
+
+		settings := map[string]any{}
+		settings["self"] = settings
+		_, err = MarshalJS(settings)
+		require.Error(t, err)
+		require.Equal(t, ".self", errors.Tag(err, "path"))
+
+		items := make([]any, 1)
+		items[0] = items
+		_, err = MarshalJS(items)
+		require.Error(t, err)
+		require.Equal(t, "[0]", errors.Tag(err, "path"))
+	})
+
+	t.Run("shared value is not a cycle", func(t *testing.T) {
+		friend := &marshalTestUser{Name: "Jonhy"}
+		v, err := MarshalJS([]*marshalTestUser{friend, friend})
+		require.NoError(t, err)
+		require.Equal(t, "Jonhy", v.Index(0).Get("name").String())
+		require.Equal(t, "Jonhy", v.Index(1).Get("name").String())
+	})
+}
+
+func TestUnmarshalJS(t *testing.T) {
+	NewTestEngine()
+
+	t.Run("struct round trip", func(t *testing.T) {
+		in := marshalTestUser{
+			marshalTestBase: marshalTestBase{ID: "u1"},
+			Name:            "Maxence",
+			Email:           "max@example.com",
+			Age:             42,
+			Tags:            []string{"go", "wasm"},
+			Settings:        map[string]any{"theme": "dark", "size": float64(12)},
+			Created:         time.UnixMilli(time.Now().UnixMilli()),
+			Avatar:          []byte("png"),
+			Friend:          &marshalTestUser{Name: "Jonhy"},
+			Scores:          map[string]uint16{"chess": 1200},
+			Untagged:        true,
+			Secret:          "hunter2",
+		}
+
+		v, err := MarshalJS(in)
+		require.NoError(t, err)
+
+		var out marshalTestUser
+		err = UnmarshalJS(v, &out)
+		require.NoError(t, err)
+
+		in.Secret = ""
+		require.True(t, in.Created.Equal(out.Created))
+		in.Created = out.Created
+		require.True(t, in.Friend.Created.Equal(out.Friend.Created))
+		in.Friend.Created = out.Friend.Created
+		require.Equal(t, in, out)
+	})
+
+	t.Run("empty interface", func(t *testing.T) {
+		v := ValueOf(map[string]any{
+			"bool":   true,
+			"number": 21,
+			"string": "hello",
+			"array":  []any{1, "two"},
+			"null":   nil,
+			"date":   Window().Get("Date").New(float64(1000)),
+		})
+
+		var out any
+		err := UnmarshalJS(v, &out)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"bool":   true,
+			"number": float64(21),
+			"string": "hello",
+			"array":  []any{float64(1), "two"},
+			"null":   nil,
+			"date":   time.UnixMilli(1000),
+		}, out)
+	})
+
+	t.Run("time from string and number", func(t *testing.T) {
+		var out struct {
+			String time.Time `js:"string"`
+			Number time.Time `js:"number"`
+		}
+		err := UnmarshalJS(ValueOf(map[string]any{
+			"string": "2024-05-17T10:30:00Z",
+			"number": 1000,
+		}), &out)
+		require.NoError(t, err)
+		require.True(t, out.String.Equal(time.Date(2024, 5, 17, 10, 30, 0, 0, time.UTC)))
+		require.True(t, out.Number.Equal(time.UnixMilli(1000)))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		v, err := MarshalJS(errors.New("saving failed").
+			WithType("io").
+			WithTag("id", 42).
+			Wrap(errors.New("disk full")))
+		require.NoError(t, err)
+
+		var out error
+		err = UnmarshalJS(v, &out)
+		require.NoError(t, err)
+		require.True(t, errors.HasType(out, "io"))
+		require.Equal(t, float64(42), errors.Tag(out, "id"))
+		require.Equal(t, "Error", errors.Tag(out, "name"))
+		require.Equal(t, "disk full", errors.Unwrap(out).(errors.Error).Message)
+
+		err = UnmarshalJS(ValueOf("boom"), &out)
+		require.NoError(t, err)
+		require.Equal(t, "boom", out.(errors.Error).Message)
+	})
+
+	t.Run("value field keeps the javascript value", func(t *testing.T) {
+		div := Window().Get("document").Call("createElement", "div")
+
+		var out struct {
+			Element Value `js:"element"`
+		}
+		err := UnmarshalJS(ValueOf(map[string]any{"element": div}), &out)
+		require.NoError(t, err)
+		require.True(t, out.Element.Equal(div))
+	})
+
+	t.Run("null resets pointers and keeps other values", func(t *testing.T) {
+		out := struct {
+			Name   string           `js:"name"`
+			Friend *marshalTestUser `js:"friend"`
+		}{
+			Name:   "Maxence",
+			Friend: &marshalTestUser{},
+		}
+		err := UnmarshalJS(ValueOf(map[string]any{"name": nil, "friend": nil}), &out)
+		require.NoError(t, err)
+		require.Equal(t, "Maxence", out.Name)
+		require.Nil(t, out.Friend)
+	})
+
+	t.Run("incompatible types return an error", func(t *testing.T) {
+		var out marshalTestUser
+		err := UnmarshalJS(ValueOf(map[string]any{"tags": []any{"go", 42}}), &out)
+		require.Error(t, err)
+		require.Equal(t, ".tags[1]", errors.Tag(err, "path"))
+	})
+
+	t.Run("lossy numbers return an error", func(t *testing.T) {
+		var i int8
+		require.Error(t, UnmarshalJS(ValueOf(1.5), &i))
+		require.Error(t, UnmarshalJS(ValueOf(300), &i))
+
+		var u uint
+		require.Error(t, UnmarshalJS(ValueOf(-1), &u))
+	})
+
+	t.Run("non pointer destination returns an error", func(t *testing.T) {
+		var out marshalTestUser
+		require.Error(t, UnmarshalJS(ValueOf(map[string]any{}), out))
+		require.Error(t, UnmarshalJS(ValueOf(map[string]any{}), nil))
+	})
+}
+
+func TestErrorOf(t *testing.T) {
+	NewTestEngine()
+
+	t.Run("marshaled error", func(t *testing.T) {
+		v, err := MarshalJS(errors.New("saving failed").
+			WithType("storage").
+			WithTag("id", 42))
+		require.NoError(t, err)
+
+		err = ErrorOf(v)
+		require.Equal(t, "saving failed", err.(errors.Error).Message)
+		require.True(t, errors.HasType(err, "storage"))
+		require.Equal(t, float64(42), errors.Tag(err, "id"))
+	})
+
+	t.Run("javascript error", func(t *testing.T) {
+		err := ErrorOf(Window().Get("Error").New("boom"))
+		require.Equal(t, "boom", err.(errors.Error).Message)
+		require.Equal(t, "Error", errors.Tag(err, "name"))
+	})
+
+	t.Run("string", func(t *testing.T) {
+		err := ErrorOf(ValueOf("boom"))
+		require.Equal(t, "boom", err.(errors.Error).Message)
+	})
+
+	t.Run("other values", func(t *testing.T) {
+		require.Error(t, ErrorOf(nil))
+		require.Error(t, ErrorOf(Undefined()))
+		require.Equal(t, true, errors.Tag(ErrorOf(ValueOf(true)), "reason"))
+	})
+}
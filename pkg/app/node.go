@@ -2,10 +2,14 @@ package app
 
 import (
 	"bytes"
+	"context"
 	"html"
 	"io"
+	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/whale1017/go-app/v10/pkg/errors"
@@ -77,7 +81,11 @@ func FilterUIElems(v ...UI) []UI {
 // HTMLString returns a string that represents the HTML markup for the provided
 // UI element.
 func HTMLString(ui UI) string {
-	engine := NewTestEngine().(*engineX)
+	origin, _ := url.Parse("/")
+	originPage := makeRequestPage(origin, nil)
+	routes := makeRouter()
+	engine := newEngine(context.Background(), &routes, nil, &originPage, nil)
+
 	var b bytes.Buffer
 	engine.nodes.Encode(engine.baseContext(), &b, ui)
 	return b.String()
@@ -100,6 +108,18 @@ type resize struct{}
 type nodeManager struct {
 }
 
+// leavingParent is implemented by the elements that keep track of their
+// children that are leaving the DOM, so that they are dismounted with them.
+type leavingParent interface {
+	// leavingChildren returns the functions that remove the leaving children
+	// right away.
+	leavingChildren() map[UI]func()
+
+	// setLeavingChild sets the function that removes the given leaving child
+	// right away, or forgets the child when the function is nil.
+	setLeavingChild(child UI, remove func())
+}
+
 // Mount mounts a UI element based on its type and the specified depth. It
 // returns the mounted UI element and any potential error during the process.
 func (m nodeManager) Mount(ctx Context, depth uint, v UI) (UI, error) {
@@ -107,7 +127,7 @@ func (m nodeManager) Mount(ctx Context, depth uint, v UI) (UI, error) {
 
 	switch v := v.(type) {
 	case *text:
-		return m.mountText(depth, v)
+		return m.mountText(ctx, depth, v)
 
 	case HTML:
 		return m.mountHTML(ctx, depth, v)
@@ -116,7 +136,10 @@ func (m nodeManager) Mount(ctx Context, depth uint, v UI) (UI, error) {
 		return m.mountComponent(ctx, depth, v)
 
 	case *raw:
-		return m.mountRawHTML(depth, v)
+		return m.mountRawHTML(ctx, depth, v)
+
+	case *portal:
+		return m.mountPortal(ctx, depth, v)
 
 	default:
 		return nil, errors.New("unsupported element").
@@ -125,14 +148,14 @@ func (m nodeManager) Mount(ctx Context, depth uint, v UI) (UI, error) {
 	}
 }
 
-func (m nodeManager) mountText(depth uint, v *text) (UI, error) {
+func (m nodeManager) mountText(ctx Context, depth uint, v *text) (UI, error) {
 	if v.Mounted() {
 		return nil, errors.New("text is already mounted").
 			WithTag("parent-type", reflect.TypeOf(v.parent())).
 			WithTag("preview-value", previewText(v.value))
 	}
 
-	v.jsvalue = Window().createTextNode(v.value)
+	v.jsvalue = ctx.currentWindow().createTextNode(v.value)
 	return v, nil
 }
 
@@ -145,7 +168,7 @@ func (m nodeManager) mountHTML(ctx Context, depth uint, v HTML) (UI, error) {
 			WithTag("depth", v.depth())
 	}
 
-	jsElement, _ := Window().createElement(v.Tag(), v.XMLNamespace())
+	jsElement, _ := ctx.currentWindow().createElement(v.Tag(), v.XMLNamespace())
 	v = v.setJSElement(jsElement)
 	m.mountHTMLAttributes(ctx, v)
 	m.mountHTMLEventHandlers(ctx, v)
@@ -237,6 +260,8 @@ func (m nodeManager) mountComponent(ctx Context, depth uint, v Composer) (UI, er
 		ctx.Dispatch(mounter.OnMount)
 	}
 
+	injectComponentStyles(ctx, v)
+
 	root, err := m.renderComponent(v)
 	if err != nil {
 		return nil, errors.New("rendering component failed").
@@ -261,10 +286,11 @@ func (m nodeManager) renderComponent(v Composer) (UI, error) {
 	if len(rendering) == 0 {
 		return nil, errors.New("render method does not returns a text, html element, or component")
 	}
+	scopeComponentRendering(v, rendering[0])
 	return rendering[0], nil
 }
 
-func (m nodeManager) mountRawHTML(depth uint, v *raw) (UI, error) {
+func (m nodeManager) mountRawHTML(ctx Context, depth uint, v *raw) (UI, error) {
 	if v.Mounted() {
 		return nil, errors.New("raw html is already mounted").
 			WithTag("parent-type", reflect.TypeOf(v.parent())).
@@ -273,13 +299,49 @@ func (m nodeManager) mountRawHTML(depth uint, v *raw) (UI, error) {
 			WithTag("raw-preview", previewText(v.value))
 	}
 
-	wrapper, _ := Window().createElement("div", "")
+	wrapper, _ := ctx.currentWindow().createElement("div", "")
 	wrapper.setInnerHTML(v.value)
 	v.jsElement = wrapper.firstChild()
 	wrapper.removeChild(v.jsElement)
 	return v, nil
 }
 
+func (m nodeManager) mountPortal(ctx Context, depth uint, v *portal) (UI, error) {
+	if v.Mounted() {
+		return nil, errors.New("portal is already mounted").
+			WithTag("parent-type", reflect.TypeOf(v.parent())).
+			WithTag("target-id", v.target).
+			WithTag("depth", v.depth())
+	}
+
+	target := v.targetElement(ctx.currentWindow())
+	if IsClient && !target.Truthy() {
+		return nil, errors.New("portal target not found").
+			WithTag("target-id", v.target).
+			WithTag("depth", depth)
+	}
+
+	v.jsElement = ctx.currentWindow().createTextNode("")
+	v.jsTarget = target
+	v.treeDepth = depth
+	v.ctx = ctx
+	for i, child := range v.children {
+		child, err := m.Mount(ctx, depth+1, child)
+		if err != nil {
+			return nil, errors.New("mounting portal child failed").
+				WithTag("target-id", v.target).
+				WithTag("depth", depth).
+				WithTag("index", i).
+				Wrap(err)
+		}
+		child = child.setParent(v)
+		v.children[i] = child
+		target.appendChild(child)
+	}
+
+	return v, nil
+}
+
 // Dismount removes a UI element based on its type.
 func (m nodeManager) Dismount(v UI) {
 	switch v := v.(type) {
@@ -293,10 +355,14 @@ func (m nodeManager) Dismount(v UI) {
 
 	case *raw:
 		m.dismountRawHTML(v)
+
+	case *portal:
+		m.dismountPortal(v)
 	}
 }
 
 func (m nodeManager) dismountHTML(v HTML) {
+	m.dismountLeavingChildren(v)
 	for _, child := range v.body() {
 		m.Dismount(child)
 	}
@@ -315,6 +381,7 @@ func (m nodeManager) dismountHTMLEventHandler(handler eventHandler) {
 }
 
 func (m nodeManager) dismountComponent(v Composer) {
+	m.dismountLeavingChildren(v)
 	m.Dismount(v.root())
 	v.setRef(nil)
 
@@ -327,6 +394,31 @@ func (m nodeManager) dismountRawHTML(v *raw) {
 	v.jsElement = nil
 }
 
+func (m nodeManager) dismountPortal(v *portal) {
+	for _, child := range v.children {
+		if _, ok := child.(Leaver); ok {
+			m.leave(v.ctx, v.jsTarget, child)
+			continue
+		}
+		v.jsTarget.removeChild(child)
+		m.Dismount(child)
+	}
+
+	v.jsElement = nil
+	v.jsTarget = nil
+	v.ctx = Context{}
+}
+
+// dismountLeavingChildren removes and dismounts the children of the given
+// element that are still leaving the DOM.
+func (m nodeManager) dismountLeavingChildren(v UI) {
+	if parent, ok := v.(leavingParent); ok {
+		for _, leave := range parent.leavingChildren() {
+			leave()
+		}
+	}
+}
+
 // CanUpdate determines whether a given UI element 'v' can be updated with a new
 // UI element 'new'. It returns false if the types of the two elements are
 // different.
@@ -342,6 +434,9 @@ func (m nodeManager) CanUpdate(v, new UI) bool {
 	case *htmlElem, *htmlElemSelfClosing:
 		return v.(HTML).Tag() == new.(HTML).Tag()
 
+	case *portal:
+		return v.(*portal).target == new.(*portal).target
+
 	default:
 		return true
 	}
@@ -369,6 +464,9 @@ func (m nodeManager) Update(ctx Context, v, new UI) (UI, error) {
 	case *raw:
 		return m.updateRawHTML(ctx, v, new.(*raw))
 
+	case *portal:
+		return m.updatePortal(ctx, v, new.(*portal))
+
 	default:
 		return nil, errors.New("unsupported element").WithTag("type", reflect.TypeOf(v))
 	}
@@ -419,19 +517,30 @@ func (m nodeManager) updateHTML(ctx Context, v, new HTML) (UI, error) {
 		m.updateHTMLEventHandlers(ctx, v, newEvents)
 	}
 
-	children := v.body()
-	newChildren := new.body()
+	children, err := m.updateChildren(ctx, v, v.JSValue(), v.depth(), v.body(), new.body())
+	if err != nil {
+		return nil, errors.New("updating html children failed").
+			WithTag("type", reflect.TypeOf(v)).
+			WithTag("tag", v.Tag()).
+			WithTag("depth", v.depth()).
+			Wrap(err)
+	}
+
+	v = v.setBody(children)
+	return v, nil
+}
+
+// updateChildren updates the given children of the given parent with the new
+// ones, in the given DOM node. It returns the updated children.
+func (m nodeManager) updateChildren(ctx Context, parent UI, jsParent Value, depth uint, children, newChildren []UI) ([]UI, error) {
 	sharedLen := min(len(children), len(newChildren))
-	for i := 0; i < min(len(children), len(newChildren)); i++ {
+	for i := 0; i < sharedLen; i++ {
 		child := children[i]
 		newChild := newChildren[i]
 		if m.CanUpdate(child, newChild) {
 			child, err := m.Update(ctx, child, newChild)
 			if err != nil {
 				return nil, errors.New("updating child failed").
-					WithTag("type", reflect.TypeOf(v)).
-					WithTag("tag", v.Tag()).
-					WithTag("depth", v.depth()).
 					WithTag("index", i).
 					Wrap(err)
 			}
@@ -439,46 +548,36 @@ func (m nodeManager) updateHTML(ctx Context, v, new HTML) (UI, error) {
 			continue
 		}
 
-		newChild, err := m.Mount(ctx, v.depth()+1, newChildren[i])
+		newChild, err := m.Mount(ctx, depth+1, newChild)
 		if err != nil {
 			return nil, errors.New("mounting child failed").
-				WithTag("type", reflect.TypeOf(v)).
-				WithTag("tag", v.Tag()).
-				WithTag("depth", v.depth()).
 				WithTag("index", i).
 				Wrap(err)
 		}
-		v.JSValue().replaceChild(newChild, child)
-		newChild = newChild.setParent(v)
+		m.replaceChild(ctx, jsParent, newChild, child)
+		newChild = newChild.setParent(parent)
 		children[i] = newChild
-		m.Dismount(child)
 	}
 
 	for i := sharedLen; i < len(children); i++ {
-		child := children[i]
-		v.JSValue().removeChild(child)
-		m.Dismount(child)
+		m.removeChild(ctx, jsParent, children[i])
 		children[i] = nil
 	}
 	children = children[:sharedLen]
 
 	for i := sharedLen; i < len(newChildren); i++ {
-		newChild, err := m.Mount(ctx, v.depth()+1, newChildren[i])
+		newChild, err := m.Mount(ctx, depth+1, newChildren[i])
 		if err != nil {
 			return nil, errors.New("mounting child failed").
-				WithTag("type", reflect.TypeOf(v)).
-				WithTag("tag", v.Tag()).
-				WithTag("depth", v.depth()).
 				WithTag("index", i).
 				Wrap(err)
 		}
-		v.JSValue().appendChild(newChild)
-		newChild = newChild.setParent(v)
+		jsParent.appendChild(newChild)
+		newChild = newChild.setParent(parent)
 		children = append(children, newChild)
 	}
 
-	v = v.setBody(children)
-	return v, nil
+	return children, nil
 }
 
 func (m nodeManager) updateHTMLAttributes(ctx Context, v HTML, newAttrs attributes) {
@@ -590,20 +689,87 @@ func (m nodeManager) UpdateComponentRoot(ctx Context, v Composer) (UI, error) {
 				Wrap(err)
 		}
 
-		for parent := v.parent(); parent != nil; parent = parent.parent() {
-			if parent, isHTML := parent.(HTML); isHTML {
-				parent.JSValue().replaceChild(newRoot, root)
-				break
-			}
+		if jsParent := m.jsParent(v); jsParent != nil {
+			m.replaceChild(ctx, jsParent, newRoot, root)
+		} else {
+			m.Dismount(root)
 		}
 		newRoot.setParent(v)
 		v.setRoot(newRoot)
-		m.Dismount(root)
 	}
 
 	return v, nil
 }
 
+// jsParent returns the DOM node that contains the given element, or nil when
+// the element has no parent.
+func (m nodeManager) jsParent(v UI) Value {
+	for parent := v.parent(); parent != nil; parent = parent.parent() {
+		switch parent := parent.(type) {
+		case HTML:
+			return parent.JSValue()
+
+		case *portal:
+			return parent.jsTarget
+		}
+	}
+	return nil
+}
+
+// replaceChild replaces the old child with the new one in the given DOM node,
+// and dismounts the old child. When the old child is a Leaver, the new child is
+// inserted before it and it is removed once it has left.
+func (m nodeManager) replaceChild(ctx Context, jsParent Value, new, old UI) {
+	if _, ok := old.(Leaver); !ok {
+		jsParent.replaceChild(new, old)
+		m.Dismount(old)
+		return
+	}
+
+	jsParent.insertBefore(new, old)
+	m.removeChild(ctx, jsParent, old)
+}
+
+// removeChild removes the given child from the given DOM node, and dismounts
+// it. The removal of a Leaver is delayed until it has left.
+func (m nodeManager) removeChild(ctx Context, jsParent Value, child UI) {
+	if _, ok := child.(Leaver); !ok {
+		jsParent.removeChild(child)
+		m.Dismount(child)
+		return
+	}
+
+	leave := m.leave(ctx, jsParent, child)
+	if parent, ok := child.parent().(leavingParent); ok && leave != nil {
+		parent.setLeavingChild(child, leave)
+	}
+}
+
+// leave makes the given leaver leave the given DOM node, then removes and
+// dismounts it. It returns a function that removes and dismounts the leaver
+// right away, e.g. when its parent is dismounted before it has left, or nil
+// when the leaver has already left.
+func (m nodeManager) leave(ctx Context, jsParent Value, child UI) func() {
+	removed := false
+	remove := func() {
+		if removed {
+			return
+		}
+		removed = true
+		if parent, ok := child.parent().(leavingParent); ok {
+			parent.setLeavingChild(child, nil)
+		}
+		jsParent.removeChild(child)
+		m.Dismount(child)
+	}
+
+	child.(Leaver).OnLeave(m.context(ctx, child), remove)
+	if removed {
+		return nil
+	}
+	return remove
+}
+
 func (m nodeManager) updateRawHTML(ctx Context, v, new *raw) (UI, error) {
 	if v.value == new.value {
 		return v, nil
@@ -618,16 +784,26 @@ func (m nodeManager) updateRawHTML(ctx Context, v, new *raw) (UI, error) {
 			Wrap(err)
 	}
 
-	for parent := v.parent(); parent != nil; parent = parent.parent() {
-		if parent, isHTML := parent.(HTML); isHTML {
-			parent.JSValue().replaceChild(newMount, v)
-			break
-		}
+	if jsParent := m.jsParent(v); jsParent != nil {
+		jsParent.replaceChild(newMount, v)
 	}
 	m.Dismount(v)
 	return newMount, nil
 }
 
+func (m nodeManager) updatePortal(ctx Context, v, new *portal) (UI, error) {
+	children, err := m.updateChildren(ctx, v, v.jsTarget, v.depth(), v.children, new.children)
+	if err != nil {
+		return nil, errors.New("updating portal children failed").
+			WithTag("target-id", v.target).
+			WithTag("depth", v.depth()).
+			Wrap(err)
+	}
+
+	v.children = children
+	return v, nil
+}
+
 func (m nodeManager) context(ctx Context, v UI) Context {
 	ctx.sourceElement = v
 	ctx.notifyComponentEvent = m.NotifyComponentEvent
@@ -646,8 +822,13 @@ func (m nodeManager) NotifyComponentEvent(ctx Context, root UI, event any) {
 			m.NotifyComponentEvent(ctx, child, event)
 		}
 
+	case *portal:
+		for _, child := range element.children {
+			m.NotifyComponentEvent(ctx, child, event)
+		}
+
 	case Composer:
-		switch event.(type) {
+		switch event := event.(type) {
 		case nav:
 			if navigator, ok := element.(Navigator); ok {
 				ctx.Dispatch(navigator.OnNav)
@@ -667,6 +848,13 @@ func (m nodeManager) NotifyComponentEvent(ctx Context, root UI, event any) {
 			if resizer, ok := element.(Resizer); ok {
 				ctx.Dispatch(resizer.OnResize)
 			}
+
+		case SharedData:
+			if receiver, ok := element.(ShareReceiver); ok {
+				ctx.Dispatch(func(ctx Context) {
+					receiver.OnShare(ctx, event)
+				})
+			}
 		}
 		m.NotifyComponentEvent(ctx, element.root(), event)
 	}
@@ -692,6 +880,9 @@ func (m nodeManager) encode(ctx Context, w *bytes.Buffer, depth int, v UI) {
 
 	case *raw:
 		m.encodeRawHTML(w, depth, v)
+
+	case *portal:
+		m.encodePortal(ctx, w, depth, v)
 	}
 }
 
@@ -712,8 +903,14 @@ func (m nodeManager) encodeHTML(ctx Context, w *bytes.Buffer, depth int, v HTML)
 	m.encodeIndent(w, depth)
 	w.WriteByte('<')
 	w.WriteString(v.Tag())
-	for name, value := range v.attrs() {
-		m.encodeHTMLAttribute(ctx, w, name, value)
+	attrs := v.attrs()
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m.encodeHTMLAttribute(ctx, w, name, attrs[name])
 	}
 	w.WriteByte('>')
 
@@ -758,7 +955,7 @@ func (m nodeManager) encodeHTMLAttribute(ctx Context, w *bytes.Buffer, name, val
 
 	w.WriteString(" ")
 	w.WriteString(name)
-	if value != "" && value != "true" {
+	if value != "" && (value != "true" || strings.HasPrefix(name, "aria-")) {
 		w.WriteString("=")
 		w.WriteString(strconv.Quote(resolveAttributeURLValue(name, value, ctx.ResolveStaticResource)))
 	}
@@ -774,6 +971,15 @@ func (m nodeManager) encodeComponent(ctx Context, w *bytes.Buffer, depth int, v
 	}
 }
 
+func (m nodeManager) encodePortal(ctx Context, w *bytes.Buffer, depth int, v *portal) {
+	for i, child := range v.children {
+		if i > 0 {
+			w.WriteByte('\n')
+		}
+		m.encode(ctx, w, depth, child)
+	}
+}
+
 func (m nodeManager) encodeRawHTML(w *bytes.Buffer, depth int, v *raw) {
 	if v.value != "" {
 		m.encodeIndent(w, depth)
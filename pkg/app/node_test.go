@@ -158,6 +158,12 @@ func TestPrintHTML(t *testing.T) {
 	require.Equal(t, "<div></div>", b.String())
 }
 
+func TestHTMLStringARIAAttributes(t *testing.T) {
+	require.Equal(t, `<button aria-expanded="true"></button>`, HTMLString(Button().Aria("expanded", true)))
+	require.Equal(t, `<button aria-expanded="false"></button>`, HTMLString(Button().Aria("expanded", false)))
+	require.Equal(t, `<button disabled></button>`, HTMLString(Button().Disabled(true)))
+}
+
 func TestNodeManagerMount(t *testing.T) {
 	ctx := makeTestContext()
 
@@ -954,6 +960,22 @@ func TestNodeManagerNotifyComponentEvent(t *testing.T) {
 		require.True(t, compo.appResized)
 		require.Contains(t, updates, compo)
 	})
+
+	t.Run("share event is notified", func(t *testing.T) {
+		updates := make(map[UI]struct{})
+		ctx.addComponentUpdate = func(c Composer, v int) {
+			updates[c] = struct{}{}
+		}
+
+		var m nodeManager
+		compo := &hello{}
+		div, err := m.Mount(ctx, 1, Div().Body(compo))
+		require.NoError(t, err)
+
+		m.NotifyComponentEvent(ctx, div, SharedData{Title: "hi", Opened: true})
+		require.Equal(t, SharedData{Title: "hi", Opened: true}, compo.sharedData)
+		require.Contains(t, updates, compo)
+	})
 }
 
 func TestNodeManagerEncode(t *testing.T) {
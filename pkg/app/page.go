@@ -1,8 +1,11 @@
 package app
 
 import (
+	"encoding/json"
 	"net/url"
 	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
 )
 
 // Page is the interface that describes a web page.
@@ -65,6 +68,39 @@ type Page interface {
 
 	// Set the Twitter card.
 	SetTwitterCard(v TwitterCard)
+
+	// Returns the canonical URL of the page.
+	CanonicalURL() string
+
+	// Sets the canonical URL of the page.
+	SetCanonicalURL(v string)
+
+	// Sets the versions of the page in other languages, reported as hreflang
+	// alternate links.
+	SetAlternates(v ...Alternate)
+
+	// Returns the directives given to search engine crawlers.
+	Robots() string
+
+	// Sets the directives given to search engine crawlers, such as "noindex"
+	// or "nofollow".
+	SetRobots(v ...string)
+
+	// Sets an Open Graph property, such as "og:locale" or
+	// "article:published_time".
+	SetOpenGraph(property, content string)
+
+	// Sets the structured data of the page, reported as JSON-LD scripts. Each
+	// value is encoded to JSON.
+	SetStructuredData(v ...any)
+
+	// Returns the theme color of the page.
+	ThemeColor() string
+
+	// Sets the theme color of the page, affecting OS-level UI elements like
+	// the browser toolbar. It replaces the colors set for light and dark color
+	// schemes with Handler.ThemeColor and Handler.DarkThemeColor.
+	SetThemeColor(v string)
 }
 
 type requestPage struct {
@@ -82,6 +118,12 @@ type requestPage struct {
 	width          int
 	height         int
 	twitterCardMap map[string]string
+	canonicalURL   string
+	alternates     []Alternate
+	robots         string
+	openGraph      map[string]string
+	structuredData []string
+	themeColor     string
 }
 
 func makeRequestPage(origin *url.URL, resolveURL func(string) string) requestPage {
@@ -173,6 +215,48 @@ func (p *requestPage) SetTwitterCard(v TwitterCard) {
 	p.twitterCardMap = v.toMap()
 }
 
+func (p *requestPage) CanonicalURL() string {
+	return p.canonicalURL
+}
+
+func (p *requestPage) SetCanonicalURL(v string) {
+	p.canonicalURL = v
+}
+
+func (p *requestPage) SetAlternates(v ...Alternate) {
+	p.alternates = v
+}
+
+func (p *requestPage) ThemeColor() string {
+	return p.themeColor
+}
+
+func (p *requestPage) SetThemeColor(v string) {
+	p.themeColor = v
+}
+
+func (p *requestPage) Robots() string {
+	return p.robots
+}
+
+func (p *requestPage) SetRobots(v ...string) {
+	p.robots = strings.Join(v, ", ")
+}
+
+func (p *requestPage) SetOpenGraph(property, content string) {
+	if p.openGraph == nil {
+		p.openGraph = make(map[string]string)
+	}
+	if property == "og:image" {
+		content = p.resolveURL(content)
+	}
+	p.openGraph[property] = content
+}
+
+func (p *requestPage) SetStructuredData(v ...any) {
+	p.structuredData = encodeStructuredData(v...)
+}
+
 type browserPage struct {
 	resolveURL func(string) string
 }
@@ -283,6 +367,94 @@ func (p browserPage) SetTwitterCard(v TwitterCard) {
 	}
 }
 
+func (p browserPage) CanonicalURL() string {
+	return p.linkByRel("canonical").getAttr("href")
+}
+
+func (p browserPage) SetCanonicalURL(v string) {
+	p.linkByRel("canonical").setAttr("href", v)
+}
+
+func (p browserPage) SetAlternates(v ...Alternate) {
+	p.removeAll("link[rel='alternate'][hreflang]")
+
+	head := Window().Get("document").Get("head")
+	for _, a := range v {
+		link, _ := Window().createElement("link", "")
+		link.setAttr("rel", "alternate")
+		link.setAttr("hreflang", a.Lang)
+		link.setAttr("href", a.Href)
+		head.appendChild(link)
+	}
+}
+
+func (p browserPage) ThemeColor() string {
+	return p.metaByName("theme-color").getAttr("content")
+}
+
+func (p browserPage) SetThemeColor(v string) {
+	p.removeAll("meta[name='theme-color']")
+
+	meta, _ := Window().createElement("meta", "")
+	meta.setAttr("name", "theme-color")
+	meta.setAttr("content", v)
+	Window().Get("document").Get("head").appendChild(meta)
+}
+
+func (p browserPage) Robots() string {
+	return p.metaByName("robots").getAttr("content")
+}
+
+func (p browserPage) SetRobots(v ...string) {
+	p.metaByName("robots").setAttr("content", strings.Join(v, ", "))
+}
+
+func (p browserPage) SetOpenGraph(property, content string) {
+	if property == "og:image" {
+		content = p.resolveURL(content)
+	}
+	p.metaByProperty(property).setAttr("content", content)
+}
+
+func (p browserPage) SetStructuredData(v ...any) {
+	p.removeAll("script[type='application/ld+json']")
+
+	head := Window().Get("document").Get("head")
+	for _, data := range encodeStructuredData(v...) {
+		script, _ := Window().createElement("script", "")
+		script.setAttr("type", "application/ld+json")
+		script.Set("textContent", data)
+		head.appendChild(script)
+	}
+}
+
+func (p browserPage) linkByRel(v string) Value {
+	link := Window().
+		Get("document").
+		Call("querySelector", "link[rel='"+v+"']")
+
+	if link.IsNull() {
+		link, _ = Window().createElement("link", "")
+		link.setAttr("rel", v)
+
+		Window().Get("document").
+			Get("head").
+			appendChild(link)
+	}
+
+	return link
+}
+
+func (p browserPage) removeAll(selector string) {
+	elements := Window().
+		Get("document").
+		Call("querySelectorAll", selector)
+
+	for i := elements.Length() - 1; i >= 0; i-- {
+		elements.Index(i).Call("remove")
+	}
+}
+
 func (p browserPage) metaByName(v string) Value {
 	meta := Window().
 		Get("document").
@@ -317,6 +489,28 @@ func (p browserPage) metaByProperty(v string) Value {
 	return meta
 }
 
+// Alternate describes a version of a page in another language.
+type Alternate struct {
+	// The language of the alternate page, such as "fr" or "x-default".
+	Lang string
+
+	// The URL of the alternate page.
+	Href string
+}
+
+func encodeStructuredData(v ...any) []string {
+	data := make([]string, 0, len(v))
+	for _, d := range v {
+		b, err := json.Marshal(d)
+		if err != nil {
+			Log(errors.New("encoding structured data failed").Wrap(err))
+			continue
+		}
+		data = append(data, string(b))
+	}
+	return data
+}
+
 type Preload struct {
 	Type          string
 	As            string
@@ -57,4 +57,17 @@ func testPage(t *testing.T, p Page) {
 	require.NotZero(t, h)
 
 	p.SetTwitterCard(TwitterCard{Card: "summary"})
+
+	p.SetCanonicalURL("/test")
+	require.Equal(t, "/test", p.CanonicalURL())
+
+	p.SetRobots("noindex", "nofollow")
+	require.Equal(t, "noindex, nofollow", p.Robots())
+
+	p.SetThemeColor("#202124")
+	require.Equal(t, "#202124", p.ThemeColor())
+
+	p.SetAlternates(Alternate{Lang: "fr", Href: "/fr/test"})
+	p.SetOpenGraph("og:locale", "fr_FR")
+	p.SetStructuredData(map[string]any{"@type": "Organization"})
 }
@@ -0,0 +1,261 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/whale1017/go-app/v10/pkg/cache"
+)
+
+const (
+	defaultPageCacheTTL   = time.Hour
+	minPageCacheKeysSweep = 64
+)
+
+// PageCache is an opt-in cache for prerendered pages served by a Handler.
+// Pages are keyed by their path, query and language, which allows serving
+// mostly static pages without creating a new engine for each request.
+type PageCache struct {
+	// Cache is where prerendered pages are stored. Defaults to a cache.LRU
+	// with its default maximum size. The OnEvict function of a cache.LRU is
+	// set when nil.
+	Cache cache.Cache
+
+	// TTL is the duration a prerendered page remains cached when no
+	// route-specific duration is set. Defaults to 1 hour.
+	TTL time.Duration
+
+	// RouteTTLs maps routes to the duration their prerendered pages remain
+	// cached. Routes are either URL paths or the patterns given to
+	// RouteWithRegexp, URL paths taking precedence. A zero or negative
+	// duration disables caching for the given route.
+	RouteTTLs map[string]time.Duration
+
+	once      sync.Once
+	mu        sync.Mutex
+	keys      map[string]map[string]time.Time
+	keysCount int
+	keysSweep int
+	hits      int64
+	misses    int64
+}
+
+// PageCacheStats describes the usage of a page cache.
+type PageCacheStats struct {
+	// The number of requests served from the cache.
+	Hits int64
+
+	// The number of requests that required a page to be prerendered.
+	Misses int64
+
+	// The number of cached pages.
+	Len int
+
+	// The size in bytes of the cached pages.
+	Size int
+}
+
+func (c *PageCache) init() {
+	if c.TTL <= 0 {
+		c.TTL = defaultPageCacheTTL
+	}
+
+	if c.Cache == nil {
+		ttl := c.TTL
+		for _, routeTTL := range c.RouteTTLs {
+			if routeTTL > ttl {
+				ttl = routeTTL
+			}
+		}
+		c.Cache = &cache.LRU{ItemTTL: ttl}
+	}
+	if lru, ok := c.Cache.(*cache.LRU); ok && lru.OnEvict == nil {
+		lru.OnEvict = c.evict
+	}
+
+	c.keys = make(map[string]map[string]time.Time)
+	c.keysSweep = minPageCacheKeysSweep
+}
+
+// Invalidate removes the cached pages for the given URL path, whatever their
+// query and language.
+func (c *PageCache) Invalidate(path string) {
+	c.once.Do(c.init)
+
+	c.mu.Lock()
+	keys := c.keys[path]
+	delete(c.keys, path)
+	c.keysCount -= len(keys)
+	c.mu.Unlock()
+
+	for key := range keys {
+		c.Cache.Del(context.Background(), key)
+	}
+}
+
+// InvalidateAll removes all the cached pages.
+func (c *PageCache) InvalidateAll() {
+	c.once.Do(c.init)
+
+	c.mu.Lock()
+	paths := c.keys
+	c.keys = make(map[string]map[string]time.Time)
+	c.keysCount = 0
+	c.mu.Unlock()
+
+	for _, keys := range paths {
+		for key := range keys {
+			c.Cache.Del(context.Background(), key)
+		}
+	}
+}
+
+// Stats returns the usage statistics of the cache.
+func (c *PageCache) Stats() PageCacheStats {
+	c.once.Do(c.init)
+
+	return PageCacheStats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+		Len:    c.Cache.Len(),
+		Size:   c.Cache.Size(),
+	}
+}
+
+func (c *PageCache) get(r *http.Request, variant, lang string) ([]byte, bool) {
+	c.once.Do(c.init)
+
+	if !c.cacheable(r) {
+		return nil, false
+	}
+
+	key := pageCacheKey(r.URL, variant, lang)
+	item, ok := c.Cache.Get(r.Context(), key)
+	if page, isPage := item.(cachedPage); ok && isPage && page.expiresAt.After(time.Now()) {
+		atomic.AddInt64(&c.hits, 1)
+		return page.body, true
+	}
+
+	c.mu.Lock()
+	if expiresAt, ok := c.keys[r.URL.Path][key]; ok && !expiresAt.After(time.Now()) {
+		c.untrack(r.URL.Path, key)
+	}
+	c.mu.Unlock()
+
+	atomic.AddInt64(&c.misses, 1)
+	return nil, false
+}
+
+// set caches the given page, prerendered for the given path, without variant
+// and language prefix, and for the route that matched it.
+func (c *PageCache) set(r *http.Request, path, route, variant, lang string, page []byte) {
+	c.once.Do(c.init)
+
+	ttl := c.ttl(path, route)
+	if !c.cacheable(r) || ttl <= 0 {
+		return
+	}
+
+	key := pageCacheKey(r.URL, variant, lang)
+	expiresAt := time.Now().Add(ttl)
+	c.track(r.URL.Path, key, expiresAt)
+
+	c.Cache.Set(r.Context(), key, cachedPage{
+		path:      r.URL.Path,
+		expiresAt: expiresAt,
+		body:      page,
+	})
+}
+
+// track records the key of a page cached for the given path, which allows
+// invalidating pages by path. The keys of expired pages are forgotten each
+// time the number of recorded keys doubles.
+func (c *PageCache) track(path, key string, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys, ok := c.keys[path]
+	if !ok {
+		keys = make(map[string]time.Time)
+		c.keys[path] = keys
+	}
+	if _, ok := keys[key]; !ok {
+		c.keysCount++
+	}
+	keys[key] = expiresAt
+
+	if c.keysCount <= c.keysSweep {
+		return
+	}
+
+	now := time.Now()
+	for path, keys := range c.keys {
+		for key, expiresAt := range keys {
+			if !expiresAt.After(now) {
+				c.untrack(path, key)
+			}
+		}
+	}
+	c.keysSweep = 2 * c.keysCount
+	if c.keysSweep < minPageCacheKeysSweep {
+		c.keysSweep = minPageCacheKeysSweep
+	}
+}
+
+// untrack forgets the key of a page cached for the given path. It must be
+// called with the mutex locked.
+func (c *PageCache) untrack(path, key string) {
+	keys := c.keys[path]
+	if _, ok := keys[key]; !ok {
+		return
+	}
+
+	delete(keys, key)
+	c.keysCount--
+	if len(keys) == 0 {
+		delete(c.keys, path)
+	}
+}
+
+func (c *PageCache) evict(key string, i cache.Item) {
+	page, ok := i.(cachedPage)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	c.untrack(page.path, key)
+	c.mu.Unlock()
+}
+
+func (c *PageCache) ttl(path, route string) time.Duration {
+	if ttl, ok := c.RouteTTLs[path]; ok {
+		return ttl
+	}
+	if ttl, ok := c.RouteTTLs[route]; ok {
+		return ttl
+	}
+	return c.TTL
+}
+
+func (c *PageCache) cacheable(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead
+}
+
+func pageCacheKey(u *url.URL, variant, lang string) string {
+	return variant + "|" + lang + ":" + u.Path + "?" + u.Query().Encode()
+}
+
+type cachedPage struct {
+	path      string
+	expiresAt time.Time
+	body      []byte
+}
+
+func (p cachedPage) Size() int {
+	return len(p.body)
+}
@@ -0,0 +1,158 @@
+//go:build !wasm
+// +build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whale1017/go-app/v10/pkg/cache"
+)
+
+func TestHandlerServePageWithPageCache(t *testing.T) {
+	h := Handler{
+		Title:     "Page cache testing",
+		PageCache: &PageCache{},
+	}
+
+	serve := func(path string) string {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	t.Run("first request is a miss", func(t *testing.T) {
+		body := serve("/")
+		require.Contains(t, body, `<div id="pre-render-ok">`)
+
+		stats := h.PageCache.Stats()
+		require.Equal(t, int64(0), stats.Hits)
+		require.Equal(t, int64(1), stats.Misses)
+		require.Equal(t, 1, stats.Len)
+		require.Equal(t, len(body), stats.Size)
+	})
+
+	t.Run("second request is a hit", func(t *testing.T) {
+		serve("/")
+
+		stats := h.PageCache.Stats()
+		require.Equal(t, int64(1), stats.Hits)
+		require.Equal(t, int64(1), stats.Misses)
+	})
+
+	t.Run("request with a query is cached separately", func(t *testing.T) {
+		serve("/?foo=bar")
+
+		stats := h.PageCache.Stats()
+		require.Equal(t, int64(1), stats.Hits)
+		require.Equal(t, int64(2), stats.Misses)
+		require.Equal(t, 2, stats.Len)
+	})
+
+	t.Run("invalidated path is rendered again", func(t *testing.T) {
+		h.PageCache.Invalidate("/")
+		require.Equal(t, 0, h.PageCache.Stats().Len)
+
+		serve("/")
+		require.Equal(t, int64(3), h.PageCache.Stats().Misses)
+	})
+
+	t.Run("invalidate all", func(t *testing.T) {
+		serve("/?foo=bar")
+		require.Equal(t, 2, h.PageCache.Stats().Len)
+
+		h.PageCache.InvalidateAll()
+		require.Equal(t, 0, h.PageCache.Stats().Len)
+	})
+}
+
+func TestPageCacheRouteTTLs(t *testing.T) {
+	c := PageCache{
+		TTL: time.Minute,
+		RouteTTLs: map[string]time.Duration{
+			"/expired":     time.Nanosecond,
+			"/disabled":    -1,
+			"/zero":        0,
+			"^/blog/.*":    -1,
+			"/blog/cached": time.Minute,
+		},
+	}
+
+	get := func(path string) bool {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		_, ok := c.get(r, "", "en")
+		return ok
+	}
+
+	setRoute := func(path, route string) {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		c.set(r, path, route, "", "en", []byte("<html></html>"))
+	}
+
+	set := func(path string) {
+		setRoute(path, path)
+	}
+
+	set("/")
+	require.True(t, get("/"))
+
+	set("/zero")
+	require.False(t, get("/zero"))
+
+	setRoute("/blog/hello", "^/blog/.*")
+	require.False(t, get("/blog/hello"))
+
+	setRoute("/blog/cached", "^/blog/.*")
+	require.True(t, get("/blog/cached"))
+
+	set("/expired")
+	time.Sleep(time.Millisecond)
+	require.False(t, get("/expired"))
+
+	set("/disabled")
+	require.False(t, get("/disabled"))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	_, ok := c.get(r, "", "fr")
+	require.False(t, ok)
+}
+
+func TestPageCacheKeysAreBounded(t *testing.T) {
+	c := PageCache{
+		Cache: &cache.LRU{
+			MaxSize: 10 * len("<html></html>"),
+			ItemTTL: time.Hour,
+		},
+	}
+
+	for i := 0; i < 1000; i++ {
+		r := httptest.NewRequest(http.MethodGet, "/?x="+strconv.Itoa(i), nil)
+		c.set(r, r.URL.Path, r.URL.Path, "", "en", []byte("<html></html>"))
+	}
+	require.Equal(t, 10, c.Stats().Len)
+	require.Len(t, c.keys["/"], 10)
+	require.Equal(t, 10, c.keysCount)
+
+	c.InvalidateAll()
+	require.Empty(t, c.keys)
+	require.Zero(t, c.keysCount)
+}
+
+func TestPageCacheForgetsExpiredKeys(t *testing.T) {
+	c := PageCache{TTL: time.Nanosecond}
+
+	for i := 0; i < 1000; i++ {
+		r := httptest.NewRequest(http.MethodGet, "/?x="+strconv.Itoa(i), nil)
+		c.set(r, r.URL.Path, r.URL.Path, "", "en", []byte("<html></html>"))
+		time.Sleep(time.Nanosecond)
+	}
+	require.LessOrEqual(t, c.keysCount, 2*minPageCacheKeysSweep)
+	require.LessOrEqual(t, len(c.keys["/"]), 2*minPageCacheKeysSweep)
+}
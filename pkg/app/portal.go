@@ -0,0 +1,55 @@
+package app
+
+// Portal creates an element that displays the given children in the DOM
+// element with the given id rather than in its parent, e.g. to prevent modals
+// and toasts from being clipped by ancestors with hidden overflow. The children
+// are displayed in the document body when the id is empty.
+//
+// The children remain owned by the enclosing component: they share its
+// context, and are updated and dismounted with it, except the children that
+// implement Leaver, which leave the target element before being dismounted.
+// Server-side, the children are rendered in place.
+func Portal(targetID string, children ...UI) UI {
+	return &portal{
+		target:   targetID,
+		children: FilterUIElems(children...),
+	}
+}
+
+type portal struct {
+	jsElement     Value
+	jsTarget      Value
+	parentElement UI
+	treeDepth     uint
+	target        string
+	children      []UI
+	ctx           Context
+}
+
+func (p *portal) JSValue() Value {
+	return p.jsElement
+}
+
+func (p *portal) Mounted() bool {
+	return p.jsElement != nil
+}
+
+func (p *portal) depth() uint {
+	return p.treeDepth
+}
+
+func (p *portal) parent() UI {
+	return p.parentElement
+}
+
+func (p *portal) setParent(v UI) UI {
+	p.parentElement = v
+	return p
+}
+
+func (p *portal) targetElement(w BrowserWindow) Value {
+	if p.target == "" {
+		return w.Get("document").Get("body")
+	}
+	return w.GetElementByID(p.target)
+}
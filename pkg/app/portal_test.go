@@ -0,0 +1,162 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type portalCompo struct {
+	Compo
+
+	Open bool
+}
+
+func (c *portalCompo) Render() UI {
+	return Div().Body(
+		If(c.Open, func() UI {
+			return Portal("modal", &hello{})
+		}),
+	)
+}
+
+func TestPortal(t *testing.T) {
+	ctx := makeTestContext()
+
+	t.Run("portal is mounted", func(t *testing.T) {
+		var m nodeManager
+
+		div, err := m.Mount(ctx, 1, Div().Body(
+			Portal("modal", Span(), &hello{}),
+		))
+		require.NoError(t, err)
+
+		p := div.(HTML).body()[0].(*portal)
+		require.True(t, p.Mounted())
+		require.Equal(t, uint(2), p.depth())
+		require.Len(t, p.children, 2)
+
+		span := p.children[0].(HTML)
+		require.True(t, span.Mounted())
+		require.Equal(t, p, span.parent())
+		require.Equal(t, uint(3), span.depth())
+
+		compo := p.children[1].(*hello)
+		require.True(t, compo.Mounted())
+	})
+
+	t.Run("mounting a mounted portal returns an error", func(t *testing.T) {
+		var m nodeManager
+
+		p, err := m.Mount(ctx, 1, Portal("modal"))
+		require.NoError(t, err)
+
+		_, err = m.Mount(ctx, 1, p)
+		require.Error(t, err)
+	})
+
+	t.Run("portal is dismounted", func(t *testing.T) {
+		var m nodeManager
+
+		div, err := m.Mount(ctx, 1, Div().Body(
+			Portal("modal", &hello{}),
+		))
+		require.NoError(t, err)
+		p := div.(HTML).body()[0].(*portal)
+		compo := p.children[0].(*hello)
+
+		m.Dismount(div)
+		require.False(t, p.Mounted())
+		require.False(t, compo.Mounted())
+	})
+
+	t.Run("portals with different targets cannot be updated", func(t *testing.T) {
+		var m nodeManager
+
+		require.True(t, m.CanUpdate(Portal("a"), Portal("a")))
+		require.False(t, m.CanUpdate(Portal("a"), Portal("b")))
+	})
+
+	t.Run("portal children are updated", func(t *testing.T) {
+		var m nodeManager
+
+		p, err := m.Mount(ctx, 1, Portal("modal", Text("hello")))
+		require.NoError(t, err)
+
+		p, err = m.Update(ctx, p, Portal("modal", Text("bye"), Span()))
+		require.NoError(t, err)
+		children := p.(*portal).children
+		require.Len(t, children, 2)
+		require.Equal(t, "bye", children[0].(*text).value)
+		require.True(t, children[1].Mounted())
+		require.Equal(t, p, children[1].parent())
+
+		span := children[1]
+		p, err = m.Update(ctx, p, Portal("modal", Div()))
+		require.NoError(t, err)
+		children = p.(*portal).children
+		require.Len(t, children, 1)
+		require.IsType(t, Div(), children[0])
+		require.False(t, span.Mounted())
+	})
+
+	t.Run("portal children are owned by the enclosing component", func(t *testing.T) {
+		updates := make(map[UI]struct{})
+		ctx := makeTestContext()
+		ctx.addComponentUpdate = func(c Composer, v int) {
+			updates[c] = struct{}{}
+		}
+
+		var m nodeManager
+
+		compo := &portalCompo{Open: true}
+		_, err := m.Mount(ctx, 1, compo)
+		require.NoError(t, err)
+
+		p := compo.root().(HTML).body()[0].(*portal)
+		hello := p.children[0].(*hello)
+
+		m.context(ctx, hello).Dispatch(nil)
+		require.Contains(t, updates, hello)
+		require.Contains(t, updates, compo)
+
+		m.NotifyComponentEvent(ctx, compo, nav{})
+		require.NotEmpty(t, hello.onNavURL)
+
+		_, err = m.Update(ctx, compo, &portalCompo{})
+		require.NoError(t, err)
+		require.False(t, p.Mounted())
+		require.False(t, hello.Mounted())
+	})
+
+	t.Run("portal children are rendered in place", func(t *testing.T) {
+		require.Equal(t,
+			"<div>\n  <span>hello</span>\n  <p></p>\n</div>",
+			HTMLString(Div().Body(Portal("modal", Span().Text("hello"), P()))),
+		)
+	})
+
+	t.Run("portal child is matched", func(t *testing.T) {
+		var m nodeManager
+
+		div, err := m.Mount(ctx, 1, Div().Body(Portal("modal", Span().Text("hello"))))
+		require.NoError(t, err)
+
+		require.NoError(t, TestMatch(div, TestUIDescriptor{
+			Path:     TestPath(0),
+			Expected: Portal("modal"),
+		}))
+		require.NoError(t, TestMatch(div, TestUIDescriptor{
+			Path:     TestPath(0, 0, 0),
+			Expected: Text("hello"),
+		}))
+		require.Error(t, TestMatch(div, TestUIDescriptor{
+			Path:     TestPath(0),
+			Expected: Portal("toast"),
+		}))
+		require.Error(t, TestMatch(div, TestUIDescriptor{
+			Path:     TestPath(0, 1),
+			Expected: Span(),
+		}))
+	})
+}
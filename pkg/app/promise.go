@@ -0,0 +1,147 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// Await waits for the given JavaScript promise to settle and returns the value
+// it is fulfilled with.
+//
+// It returns an error when the promise is rejected, which wraps the rejection
+// reason converted with ErrorOf. It also returns an error when the context is
+// done before the promise settles, or when the given value is not a promise,
+// such as on the server.
+//
+// The callbacks given to the promise are released once it is settled. When the
+// context is done first, they are left as no-ops until then, since releasing
+// callbacks that a pending promise still references makes the runtime report
+// calls to released functions. Await blocks the calling goroutine: it must be called
+// within Context.Async or on a goroutine other than the one of a function
+// created with FuncOf.
+func Await(ctx context.Context, promise Value) (Value, error) {
+	if promise == nil || !promise.Get("then").Truthy() {
+		return nil, errors.New("value is not a promise")
+	}
+
+	type result struct {
+		value Value
+		err   error
+	}
+	results := make(chan result, 1)
+
+	var onFulfilled, onRejected Func
+	var releaseOnce sync.Once
+	release := func() {
+		releaseOnce.Do(func() {
+			onFulfilled.Release()
+			onRejected.Release()
+		})
+	}
+	settle := func(r result) {
+		select {
+		case results <- r:
+		default:
+		}
+		release()
+	}
+	onFulfilled = FuncOf(func(this Value, args []Value) any {
+		settle(result{value: promiseArg(args)})
+		return nil
+	})
+	onRejected = FuncOf(func(this Value, args []Value) any {
+		settle(result{err: errors.New("promise rejected").
+			Wrap(ErrorOf(promiseArg(args)))})
+		return nil
+	})
+	promise.Call("then", onFulfilled, onRejected)
+
+	select {
+	case r := <-results:
+		return r.value, r.err
+
+	case <-ctx.Done():
+		return nil, errors.New("awaiting promise canceled").Wrap(ctx.Err())
+	}
+}
+
+// NewPromise returns a JavaScript promise that is settled with the result of
+// the given function, which is called on a new goroutine.
+//
+// The promise is fulfilled with the returned value, mapped to JavaScript
+// according to ValueOf, or rejected with a JavaScript Error that represents
+// the returned error, as described in MarshalJS. It is also rejected when the function panics, or
+// when the context is done before the function returns.
+//
+// On the server, where JavaScript promises are not available, the function is
+// not called and the JavaScript value "undefined" is returned.
+func NewPromise(ctx context.Context, fn func(context.Context) (any, error)) Value {
+	var resolve, reject Value
+	executor := FuncOf(func(this Value, args []Value) any {
+		if len(args) >= 2 {
+			resolve = args[0]
+			reject = args[1]
+		}
+		return nil
+	})
+	promise := Window().Get("Promise").New(executor)
+	executor.Release()
+
+	if resolve == nil || reject == nil {
+		return Undefined()
+	}
+
+	type result struct {
+		value any
+		err   error
+	}
+	results := make(chan result, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				results <- result{err: errors.New("promise function panicked").
+					WithTag("panic", fmt.Sprint(r))}
+			}
+		}()
+
+		v, err := fn(ctx)
+		results <- result{value: v, err: err}
+	}()
+
+	go func() {
+		var r result
+		select {
+		case r = <-results:
+
+		case <-ctx.Done():
+			r.err = errors.New("promise canceled").Wrap(ctx.Err())
+		}
+
+		defer func() {
+			if p := recover(); p != nil {
+				reject.Invoke(marshalJSError(errors.New("converting promise result failed").
+					WithTag("type", fmt.Sprintf("%T", r.value)).
+					WithTag("panic", fmt.Sprint(p))))
+			}
+		}()
+
+		if r.err != nil {
+			reject.Invoke(marshalJSError(r.err))
+			return
+		}
+		resolve.Invoke(r.value)
+	}()
+
+	return promise
+}
+
+func promiseArg(args []Value) Value {
+	if len(args) != 0 {
+		return args[0]
+	}
+	return Undefined()
+}
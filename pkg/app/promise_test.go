@@ -0,0 +1,158 @@
+//go:build !wasm && fakedom
+// +build !wasm,fakedom
+
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+func TestAwait(t *testing.T) {
+	NewTestEngine()
+	ctx := context.Background()
+	promise := Window().Get("Promise")
+
+	t.Run("fulfilled promise returns its value", func(t *testing.T) {
+		v, err := Await(ctx, promise.Call("resolve", 42))
+		require.NoError(t, err)
+		require.Equal(t, 42, v.Int())
+	})
+
+	t.Run("promise chain returns the last value", func(t *testing.T) {
+		double := FuncOf(func(this Value, args []Value) any {
+			return args[0].Int() * 2
+		})
+		defer double.Release()
+
+		v, err := Await(ctx, promise.Call("resolve", 21).Call("then", double))
+		require.NoError(t, err)
+		require.Equal(t, 42, v.Int())
+	})
+
+	t.Run("promise rejected with an error returns the converted error", func(t *testing.T) {
+		_, err := Await(ctx, promise.Call("reject", Window().Get("Error").New("boom")))
+		require.Error(t, err)
+		require.Equal(t, "Error", errors.Tag(err, "name"))
+		require.Equal(t, "boom", errors.Unwrap(err).(errors.Error).Message)
+		t.Log(err)
+	})
+
+	t.Run("promise rejected with a string returns the converted error", func(t *testing.T) {
+		_, err := Await(ctx, promise.Call("reject", "boom"))
+		require.Error(t, err)
+		require.Equal(t, "boom", errors.Unwrap(err).(errors.Error).Message)
+	})
+
+	t.Run("promise rejected with a value returns a tagged error", func(t *testing.T) {
+		_, err := Await(ctx, promise.Call("reject", 42))
+		require.Error(t, err)
+		require.Equal(t, float64(42), errors.Tag(err, "reason"))
+	})
+
+	t.Run("canceled context returns an error", func(t *testing.T) {
+		pending := promise.New(FuncOf(func(this Value, args []Value) any {
+			return nil
+		}))
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := Await(ctx, pending)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("canceled context keeps the callbacks until settled", func(t *testing.T) {
+		var callbacks []Value
+		then := FuncOf(func(this Value, args []Value) any {
+			callbacks = args
+			return nil
+		})
+		defer then.Release()
+
+		thenable := Window().Get("Object").New()
+		thenable.Set("then", then)
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := Await(ctx, thenable)
+		require.Error(t, err)
+		require.Len(t, callbacks, 2)
+		for _, c := range callbacks {
+			require.NotNil(t, c.(function).obj.fn)
+		}
+
+		callbacks[0].Invoke(42)
+		for _, c := range callbacks {
+			require.Nil(t, c.(function).obj.fn)
+		}
+	})
+
+	t.Run("non promise value returns an error", func(t *testing.T) {
+		_, err := Await(ctx, nil)
+		require.Error(t, err)
+
+		_, err = Await(ctx, ValueOf(42))
+		require.Error(t, err)
+	})
+}
+
+func TestNewPromise(t *testing.T) {
+	NewTestEngine()
+	ctx := context.Background()
+
+	t.Run("returned value fulfills the promise", func(t *testing.T) {
+		v, err := Await(ctx, NewPromise(ctx, func(ctx context.Context) (any, error) {
+			return "hello", nil
+		}))
+		require.NoError(t, err)
+		require.Equal(t, "hello", v.String())
+	})
+
+	t.Run("returned error rejects the promise", func(t *testing.T) {
+		_, err := Await(ctx, NewPromise(ctx, func(ctx context.Context) (any, error) {
+			return nil, errors.New("boom")
+		}))
+		require.Error(t, err)
+		require.Equal(t, "boom", errors.Unwrap(err).(errors.Error).Message)
+	})
+
+	t.Run("returned error type and tags are preserved", func(t *testing.T) {
+		_, err := Await(ctx, NewPromise(ctx, func(ctx context.Context) (any, error) {
+			return nil, errors.New("boom").
+				WithType("test").
+				WithTag("id", "42")
+		}))
+		require.Error(t, err)
+		require.True(t, errors.HasType(err, "test"))
+		require.Equal(t, "42", errors.Tag(err, "id"))
+	})
+
+	t.Run("panic rejects the promise", func(t *testing.T) {
+		_, err := Await(ctx, NewPromise(ctx, func(ctx context.Context) (any, error) {
+			panic("boom")
+		}))
+		require.Error(t, err)
+		require.Equal(t, "promise function panicked", errors.Unwrap(err).(errors.Error).Message)
+		require.Equal(t, "boom", errors.Tag(err, "panic"))
+	})
+
+	t.Run("canceled context rejects the promise", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		promise := NewPromise(ctx, func(ctx context.Context) (any, error) {
+			time.Sleep(time.Second)
+			return "hello", nil
+		})
+		cancel()
+
+		_, err := Await(context.Background(), promise)
+		require.Error(t, err)
+		require.Equal(t, "promise canceled", errors.Unwrap(err).(errors.Error).Message)
+	})
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"net/http"
+	"path/filepath"
 	"strings"
 )
 
@@ -106,6 +107,25 @@ func GitHubPages(repositoryName string) ResourceResolver {
 	return PrefixedLocation("/" + strings.Trim(repositoryName, "/"))
 }
 
+func localResourceFilename(r ResourceResolver, location string) (string, bool) {
+	var directory string
+	switch r := r.(type) {
+	case localResourceResolver:
+		directory = r.directory
+
+	case prefixedResourceResolver:
+		directory = r.directory
+
+	default:
+		return "", false
+	}
+
+	if remoteLocation(location) || !webLocation(location) {
+		return "", false
+	}
+	return filepath.Join(directory, filepath.FromSlash(strings.Trim(location, "/"))), true
+}
+
 func clientResourceResolver(resourcesLocation string) func(string) string {
 	return func(location string) string {
 		if remoteLocation(location) || !webLocation(location) {
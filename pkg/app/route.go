@@ -2,6 +2,7 @@ package app
 
 import (
 	"regexp"
+	"sort"
 	"sync"
 )
 
@@ -35,20 +36,27 @@ func (r *router) routeWithRegexp(pattern string, newComponent func() Composer) {
 }
 
 func (r *router) routed(path string) bool {
+	_, routed := r.match(path)
+	return routed
+}
+
+// match returns the path or the regular expression pattern of the route that
+// matches the given path.
+func (r *router) match(path string) (string, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	if _, routed := r.routes[path]; routed {
-		return true
+		return path, true
 	}
 
 	for _, rwr := range r.routesWithRegexp {
 		if rwr.regexp.MatchString(path) {
-			return true
+			return rwr.regexp.String(), true
 		}
 	}
 
-	return false
+	return "", false
 }
 
 func (r *router) createComponent(path string) (Composer, bool) {
@@ -68,6 +76,25 @@ func (r *router) createComponent(path string) (Composer, bool) {
 	return nil, false
 }
 
+func (r *router) forEach(fn func(path string, isPattern bool, newComponent func() Composer)) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	paths := make([]string, 0, len(r.routes))
+	for path := range r.routes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		fn(path, false, r.routes[path])
+	}
+
+	for _, rwr := range r.routesWithRegexp {
+		fn(rwr.regexp.String(), true, rwr.newComponent)
+	}
+}
+
 type regexpRoute struct {
 	regexp       *regexp.Regexp
 	newComponent func() Composer
@@ -4,7 +4,8 @@ const (
 	// The default template used to generate app-worker.js.
 	DefaultAppWorkerJS = ""
 
-	appJS        = ""
-	manifestJSON = ""
-	appCSS       = ""
+	appJS          = ""
+	appWebWorkerJS = ""
+	manifestJSON   = ""
+	appCSS         = ""
 )
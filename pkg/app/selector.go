@@ -0,0 +1,467 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// selectorNode is the interface that describes an element matched by CSS
+// selectors.
+type selectorNode interface {
+	// Returns the lowercase tag name.
+	selectorTag() string
+
+	// Returns the value of the named attribute and whether it is set.
+	selectorAttr(name string) (string, bool)
+
+	// Returns the parent element, or nil.
+	selectorParent() selectorNode
+
+	// Returns the previous sibling element, or nil.
+	selectorPrevious() selectorNode
+}
+
+// selector is a parsed CSS selector list. It supports type, universal, id,
+// class and attribute selectors, the :not(), :scope, :first-child and
+// :last-child pseudo-classes, and the descendant, child, next-sibling and
+// subsequent-sibling combinators.
+type selector []complexSelector
+
+type complexSelector struct {
+	// The compound selectors, from right to left.
+	compounds []compoundSelector
+
+	// The combinators between a compound selector and the next one on its
+	// left.
+	combinators []byte
+}
+
+type compoundSelector struct {
+	tag        string
+	ids        []string
+	classes    []string
+	attrs      []attrSelector
+	not        []selector
+	scope      bool
+	firstChild bool
+	lastChild  bool
+}
+
+type attrSelector struct {
+	name  string
+	op    string
+	value string
+}
+
+func parseSelector(s string) (selector, error) {
+	p := selectorParser{input: s}
+
+	sel, err := p.parseList()
+	if err != nil {
+		return nil, errors.New("parsing css selector failed").
+			WithTag("selector", s).
+			Wrap(err)
+	}
+
+	p.skipSpaces()
+	if !p.done() {
+		return nil, errors.New("parsing css selector failed").
+			WithTag("selector", s).
+			WithTag("position", p.pos).
+			Wrap(errors.New("unexpected character"))
+	}
+	return sel, nil
+}
+
+// match reports whether the given node matches the selector. Scope is the
+// element matched by :scope, which can be nil.
+func (s selector) match(n, scope selectorNode) bool {
+	for _, c := range s {
+		if c.match(n, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c complexSelector) match(n, scope selectorNode) bool {
+	return c.matchFrom(0, n, scope)
+}
+
+func (c complexSelector) matchFrom(i int, n, scope selectorNode) bool {
+	if !c.compounds[i].match(n, scope) {
+		return false
+	}
+	if i == len(c.compounds)-1 {
+		return true
+	}
+
+	switch c.combinators[i] {
+	case '>':
+		p := n.selectorParent()
+		return p != nil && c.matchFrom(i+1, p, scope)
+
+	case '+':
+		p := n.selectorPrevious()
+		return p != nil && c.matchFrom(i+1, p, scope)
+
+	case '~':
+		for p := n.selectorPrevious(); p != nil; p = p.selectorPrevious() {
+			if c.matchFrom(i+1, p, scope) {
+				return true
+			}
+		}
+		return false
+
+	default:
+		for p := n.selectorParent(); p != nil; p = p.selectorParent() {
+			if c.matchFrom(i+1, p, scope) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (c compoundSelector) match(n, scope selectorNode) bool {
+	if c.tag != "" && c.tag != "*" && c.tag != n.selectorTag() {
+		return false
+	}
+
+	if c.scope && (scope == nil || n != scope) {
+		return false
+	}
+
+	for _, id := range c.ids {
+		if v, _ := n.selectorAttr("id"); v != id {
+			return false
+		}
+	}
+
+	if len(c.classes) != 0 {
+		class, _ := n.selectorAttr("class")
+		classes := strings.Fields(class)
+		for _, c := range c.classes {
+			if !containsString(classes, c) {
+				return false
+			}
+		}
+	}
+
+	for _, a := range c.attrs {
+		if !a.match(n) {
+			return false
+		}
+	}
+
+	if c.firstChild && n.selectorPrevious() != nil {
+		return false
+	}
+
+	if c.lastChild {
+		p := n.selectorParent()
+		if p == nil {
+			return false
+		}
+		if last, ok := p.(interface{ selectorLastChild() selectorNode }); ok && last.selectorLastChild() != n {
+			return false
+		}
+	}
+
+	for _, s := range c.not {
+		if s.match(n, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a attrSelector) match(n selectorNode) bool {
+	v, ok := n.selectorAttr(a.name)
+	if !ok {
+		return false
+	}
+
+	switch a.op {
+	case "":
+		return true
+
+	case "=":
+		return v == a.value
+
+	case "~=":
+		return containsString(strings.Fields(v), a.value)
+
+	case "^=":
+		return a.value != "" && strings.HasPrefix(v, a.value)
+
+	case "$=":
+		return a.value != "" && strings.HasSuffix(v, a.value)
+
+	case "*=":
+		return a.value != "" && strings.Contains(v, a.value)
+
+	case "|=":
+		return v == a.value || strings.HasPrefix(v, a.value+"-")
+
+	default:
+		return false
+	}
+}
+
+func containsString(s []string, v string) bool {
+	for _, item := range s {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+type selectorParser struct {
+	input string
+	pos   int
+}
+
+func (p *selectorParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *selectorParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *selectorParser) skipSpaces() bool {
+	start := p.pos
+	for !p.done() && isSelectorSpace(p.peek()) {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *selectorParser) parseList() (selector, error) {
+	var sel selector
+	for {
+		p.skipSpaces()
+		c, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		sel = append(sel, c)
+
+		p.skipSpaces()
+		if p.peek() != ',' {
+			return sel, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *selectorParser) parseComplex() (complexSelector, error) {
+	var compounds []compoundSelector
+	var combinators []byte
+
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return complexSelector{}, err
+		}
+		compounds = append(compounds, compound)
+
+		hasSpace := p.skipSpaces()
+		switch c := p.peek(); c {
+		case '>', '+', '~':
+			p.pos++
+			p.skipSpaces()
+			combinators = append(combinators, c)
+
+		case 0, ',', ')':
+			for i, j := 0, len(compounds)-1; i < j; i, j = i+1, j-1 {
+				compounds[i], compounds[j] = compounds[j], compounds[i]
+			}
+			for i, j := 0, len(combinators)-1; i < j; i, j = i+1, j-1 {
+				combinators[i], combinators[j] = combinators[j], combinators[i]
+			}
+			return complexSelector{
+				compounds:   compounds,
+				combinators: combinators,
+			}, nil
+
+		default:
+			if !hasSpace {
+				return complexSelector{}, errors.New("unexpected character").
+					WithTag("position", p.pos)
+			}
+			combinators = append(combinators, ' ')
+		}
+	}
+}
+
+func (p *selectorParser) parseCompound() (compoundSelector, error) {
+	var c compoundSelector
+
+	switch {
+	case p.peek() == '*':
+		p.pos++
+		c.tag = "*"
+
+	case isSelectorNameChar(p.peek()):
+		c.tag = strings.ToLower(p.parseName())
+	}
+
+	for {
+		switch p.peek() {
+		case '#':
+			p.pos++
+			id := p.parseName()
+			if id == "" {
+				return c, errors.New("missing id").WithTag("position", p.pos)
+			}
+			c.ids = append(c.ids, id)
+
+		case '.':
+			p.pos++
+			class := p.parseName()
+			if class == "" {
+				return c, errors.New("missing class").WithTag("position", p.pos)
+			}
+			c.classes = append(c.classes, class)
+
+		case '[':
+			p.pos++
+			a, err := p.parseAttr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, a)
+
+		case ':':
+			p.pos++
+			if err := p.parsePseudo(&c); err != nil {
+				return c, err
+			}
+
+		default:
+			if c.tag == "" && c.ids == nil && c.classes == nil && c.attrs == nil &&
+				c.not == nil && !c.scope && !c.firstChild && !c.lastChild {
+				return c, errors.New("missing selector").WithTag("position", p.pos)
+			}
+			return c, nil
+		}
+	}
+}
+
+func (p *selectorParser) parseAttr() (attrSelector, error) {
+	p.skipSpaces()
+	a := attrSelector{name: strings.ToLower(p.parseName())}
+	if a.name == "" {
+		return a, errors.New("missing attribute name").WithTag("position", p.pos)
+	}
+	p.skipSpaces()
+
+	switch c := p.peek(); c {
+	case ']':
+		p.pos++
+		return a, nil
+
+	case '=':
+		a.op = "="
+		p.pos++
+
+	case '~', '^', '$', '*', '|':
+		p.pos++
+		if p.peek() != '=' {
+			return a, errors.New("invalid attribute operator").WithTag("position", p.pos)
+		}
+		a.op = string(c) + "="
+		p.pos++
+
+	default:
+		return a, errors.New("invalid attribute selector").WithTag("position", p.pos)
+	}
+
+	p.skipSpaces()
+	switch q := p.peek(); q {
+	case '"', '\'':
+		p.pos++
+		end := strings.IndexByte(p.input[p.pos:], q)
+		if end < 0 {
+			return a, errors.New("unterminated attribute value").WithTag("position", p.pos)
+		}
+		a.value = p.input[p.pos : p.pos+end]
+		p.pos += end + 1
+
+	default:
+		a.value = p.parseName()
+	}
+
+	p.skipSpaces()
+	if p.peek() != ']' {
+		return a, errors.New("unterminated attribute selector").WithTag("position", p.pos)
+	}
+	p.pos++
+	return a, nil
+}
+
+func (p *selectorParser) parsePseudo(c *compoundSelector) error {
+	name := strings.ToLower(p.parseName())
+	switch name {
+	case "scope":
+		c.scope = true
+
+	case "first-child":
+		c.firstChild = true
+
+	case "last-child":
+		c.lastChild = true
+
+	case "not":
+		if p.peek() != '(' {
+			return errors.New("missing :not() argument").WithTag("position", p.pos)
+		}
+		p.pos++
+
+		sel, err := p.parseList()
+		if err != nil {
+			return err
+		}
+		p.skipSpaces()
+		if p.peek() != ')' {
+			return errors.New("unterminated :not() argument").WithTag("position", p.pos)
+		}
+		p.pos++
+		c.not = append(c.not, sel)
+
+	default:
+		return errors.New("unsupported pseudo-class").
+			WithTag("pseudo-class", name).
+			WithTag("position", p.pos)
+	}
+	return nil
+}
+
+func (p *selectorParser) parseName() string {
+	start := p.pos
+	for !p.done() && isSelectorNameChar(p.peek()) {
+		if p.peek() == '\\' && p.pos+1 < len(p.input) {
+			p.pos++
+		}
+		p.pos++
+	}
+	return strings.ReplaceAll(p.input[start:p.pos], "\\", "")
+}
+
+func isSelectorSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isSelectorNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' ||
+		c >= 'A' && c <= 'Z' ||
+		c >= '0' && c <= '9' ||
+		c == '-' || c == '_' || c == '\\' || c >= 0x80
+}
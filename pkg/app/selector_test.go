@@ -0,0 +1,164 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type selectorTestNode struct {
+	tag      string
+	attrs    map[string]string
+	parent   *selectorTestNode
+	children []*selectorTestNode
+}
+
+func newSelectorTestNode(tag string, attrs map[string]string, children ...*selectorTestNode) *selectorTestNode {
+	n := &selectorTestNode{
+		tag:      tag,
+		attrs:    attrs,
+		children: children,
+	}
+	for _, c := range children {
+		c.parent = n
+	}
+	return n
+}
+
+func (n *selectorTestNode) selectorTag() string {
+	return n.tag
+}
+
+func (n *selectorTestNode) selectorAttr(name string) (string, bool) {
+	v, ok := n.attrs[name]
+	return v, ok
+}
+
+func (n *selectorTestNode) selectorParent() selectorNode {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *selectorTestNode) selectorPrevious() selectorNode {
+	if n.parent == nil {
+		return nil
+	}
+	for i, c := range n.parent.children {
+		if c == n && i > 0 {
+			return n.parent.children[i-1]
+		}
+	}
+	return nil
+}
+
+func (n *selectorTestNode) selectorLastChild() selectorNode {
+	if len(n.children) == 0 {
+		return nil
+	}
+	return n.children[len(n.children)-1]
+}
+
+func TestSelector(t *testing.T) {
+	first := newSelectorTestNode("li", map[string]string{
+		"class":    "item first",
+		"data-key": "a",
+	})
+	second := newSelectorTestNode("li", map[string]string{
+		"class":    "item",
+		"data-key": "b-2",
+		"hidden":   "",
+	})
+	third := newSelectorTestNode("li", map[string]string{
+		"id":    "last",
+		"class": "item",
+		"title": "hello world",
+	})
+	list := newSelectorTestNode("ul", map[string]string{
+		"id":   "list",
+		"role": "menu",
+	}, first, second, third)
+	root := newSelectorTestNode("div", map[string]string{
+		"class": "root",
+	}, list)
+
+	tests := []struct {
+		selector string
+		node     *selectorTestNode
+		scope    *selectorTestNode
+		matches  bool
+	}{
+		{selector: "li", node: first, matches: true},
+		{selector: "LI", node: first, matches: true},
+		{selector: "*", node: root, matches: true},
+		{selector: "ul", node: first},
+		{selector: "#last", node: third, matches: true},
+		{selector: "#last", node: second},
+		{selector: ".item.first", node: first, matches: true},
+		{selector: ".item.first", node: second},
+		{selector: "[hidden]", node: second, matches: true},
+		{selector: "[hidden]", node: first},
+		{selector: "[data-key=a]", node: first, matches: true},
+		{selector: `[data-key="b-2"]`, node: second, matches: true},
+		{selector: "[data-key|=b]", node: second, matches: true},
+		{selector: "[data-key^='b']", node: second, matches: true},
+		{selector: "[data-key$='2']", node: second, matches: true},
+		{selector: "[title*=lo]", node: third, matches: true},
+		{selector: "[title~=world]", node: third, matches: true},
+		{selector: "[title~=wor]", node: third},
+		{selector: "div li", node: third, matches: true},
+		{selector: "div > li", node: third},
+		{selector: "ul > li", node: third, matches: true},
+		{selector: ".root > [role=menu] > li.item", node: first, matches: true},
+		{selector: ".first + li", node: second, matches: true},
+		{selector: ".first + li", node: third},
+		{selector: ".first ~ li", node: third, matches: true},
+		{selector: "li:first-child", node: first, matches: true},
+		{selector: "li:first-child", node: second},
+		{selector: "li:last-child", node: third, matches: true},
+		{selector: "li:last-child", node: second},
+		{selector: "li:not([hidden])", node: first, matches: true},
+		{selector: "li:not([hidden], #last)", node: third},
+		{selector: ":scope > li", node: first, scope: list, matches: true},
+		{selector: ":scope > li", node: first, scope: root},
+		{selector: "span, .first", node: first, matches: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.selector, func(t *testing.T) {
+			sel, err := parseSelector(test.selector)
+			require.NoError(t, err)
+
+			var scope selectorNode
+			if test.scope != nil {
+				scope = test.scope
+			}
+			require.Equal(t, test.matches, sel.match(test.node, scope))
+		})
+	}
+}
+
+func TestParseSelectorError(t *testing.T) {
+	selectors := []string{
+		"",
+		"#",
+		".",
+		"div >",
+		"[",
+		"[name",
+		"[name='value",
+		"[name!=value]",
+		"li:hover",
+		"li:not(",
+		"li:not(span",
+		"div)",
+	}
+
+	for _, s := range selectors {
+		t.Run(s, func(t *testing.T) {
+			_, err := parseSelector(s)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"time"
+)
+
+// SharedData represents the data shared with the installed app, or the files
+// opened with it.
+type SharedData struct {
+	// The title of the shared data.
+	Title string
+
+	// The shared text.
+	Text string
+
+	// The shared URL.
+	URL string
+
+	// The shared or opened files.
+	Files []SharedFile
+
+	// Reports whether the files were opened with the app through a file
+	// handler rather than shared with it.
+	Opened bool
+}
+
+// SharedFile describes a file shared with the app or opened with it.
+type SharedFile struct {
+	// The file name.
+	Name string
+
+	// The file MIME type.
+	Type string
+
+	// The file size in bytes.
+	Size int
+
+	// The last modification time of the file.
+	LastModified time.Time
+
+	// The JavaScript File object, which can be used to read the file content,
+	// e.g. with Value.Call("arrayBuffer").
+	Value Value
+}
+
+func makeSharedData(v Value) SharedData {
+	data := SharedData{
+		Title:  jsString(v.Get("title")),
+		Text:   jsString(v.Get("text")),
+		URL:    jsString(v.Get("url")),
+		Opened: v.Get("opened").Truthy(),
+	}
+
+	if files := v.Get("files"); files.Truthy() {
+		data.Files = make([]SharedFile, files.Length())
+		for i := range data.Files {
+			file := files.Index(i)
+			data.Files[i] = SharedFile{
+				Name:         jsString(file.Get("name")),
+				Type:         jsString(file.Get("type")),
+				Size:         file.Get("size").Int(),
+				LastModified: time.UnixMilli(int64(file.Get("lastModified").Float())),
+				Value:        file,
+			}
+		}
+	}
+	return data
+}
+
+func jsString(v Value) string {
+	if !v.Truthy() {
+		return ""
+	}
+	return v.String()
+}
@@ -0,0 +1,317 @@
+package app
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+const (
+	maxSitemapURLs = 50000
+	sitemapXMLNS   = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	sitemapXHTMLNS = "http://www.w3.org/1999/xhtml"
+)
+
+// Sitemap configures the generation of /sitemap.xml and /robots.txt from the
+// registered routes.
+//
+// Routes defined with Route are listed as is. Components associated with a
+// route can implement SitemapEnumerator to list the URLs they serve, which is
+// required for routes defined with RouteWithRegexp to be part of the sitemap.
+//
+// When the handler has Locales, each URL path is listed for every locale, with
+// links to its alternate versions.
+//
+// Since sitemaps only contain absolute URLs, the handler Domain is required:
+// files are generated once, when the handler is initialized. Without Domain,
+// an error is logged and the files are proxied like when Sitemap is nil.
+type Sitemap struct {
+	// Exclude lists the URL paths that are not listed in the sitemap.
+	Exclude []string
+
+	// Disallow lists the URL paths that crawlers are not allowed to visit,
+	// reported in robots.txt.
+	Disallow []string
+
+	// MaxURLs is the maximum number of URLs per sitemap file. When more URLs
+	// are listed, /sitemap.xml becomes a sitemap index that references
+	// /sitemap-1.xml, /sitemap-2.xml, etc. Defaults to 50000, the maximum
+	// allowed by the sitemap protocol.
+	MaxURLs int
+}
+
+// SitemapURL describes a URL listed in a sitemap.
+type SitemapURL struct {
+	// The URL path or absolute URL of the page. Paths are resolved with the
+	// handler domain.
+	Loc string
+
+	// The date of the last modification of the page.
+	LastMod time.Time
+
+	// How frequently the page is likely to change: always, hourly, daily,
+	// weekly, monthly, yearly or never.
+	ChangeFreq string
+
+	// The priority of the page relative to other pages of the site, between
+	// 0.0 and 1.0. The priority is not reported when nil.
+	Priority *float64
+}
+
+// SitemapEnumerator is the interface that describes a routed component that
+// lists the URLs to report in the sitemap for its route.
+type SitemapEnumerator interface {
+	Composer
+
+	// Returns the URLs to list in the sitemap for the route the component is
+	// associated with.
+	SitemapURLs() []SitemapURL
+}
+
+func (h *Handler) initSitemap() {
+	if h.Sitemap == nil {
+		return
+	}
+	if h.Sitemap.MaxURLs <= 0 {
+		h.Sitemap.MaxURLs = maxSitemapURLs
+	}
+
+	if h.Domain == "" {
+		Log(errors.New("generating sitemap failed").
+			Wrap(errors.New("handler has no domain")))
+		return
+	}
+
+	files, err := h.makeSitemapFiles(&routes, h.sitemapOrigin())
+	if err != nil {
+		Log(errors.New("generating sitemap failed").Wrap(err))
+		return
+	}
+	h.sitemaps = files
+}
+
+func (h *Handler) isSitemapResource(path string) bool {
+	if h.sitemaps == nil {
+		return false
+	}
+	return path == "/robots.txt" ||
+		path == "/sitemap.xml" ||
+		strings.HasPrefix(path, "/sitemap-") && strings.HasSuffix(path, ".xml")
+}
+
+func (h *Handler) serveSitemapResource(w http.ResponseWriter, r *http.Request, path string) {
+	item, ok := h.sitemaps[path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	h.serveCachedItem(w, item)
+}
+
+// makeSitemapFiles returns the robots.txt and sitemap files for the given
+// origin, indexed by path.
+func (h *Handler) makeSitemapFiles(routes *router, origin string) (map[string]cacheItem, error) {
+	files := map[string]cacheItem{
+		"/robots.txt": {
+			Path:        "/robots.txt",
+			ContentType: "text/plain",
+			Body:        h.makeRobotsTXT(origin),
+		},
+	}
+	for _, path := range h.sitemapResources(routes) {
+		if path == "/robots.txt" {
+			continue
+		}
+
+		item, err := h.makeSitemapXML(routes, origin, path)
+		if err != nil {
+			return nil, err
+		}
+		files[path] = item
+	}
+	return files, nil
+}
+
+func (h *Handler) sitemapResources(routes *router) []string {
+	resources := []string{"/robots.txt", "/sitemap.xml"}
+
+	urls := h.sitemapURLs(routes, "")
+	if files := (len(urls) + h.Sitemap.MaxURLs - 1) / h.Sitemap.MaxURLs; files > 1 {
+		for i := 1; i <= files; i++ {
+			resources = append(resources, "/sitemap-"+strconv.Itoa(i)+".xml")
+		}
+	}
+	return resources
+}
+
+func (h *Handler) sitemapOrigin() string {
+	return "https://" + strings.TrimRight(h.Domain, "/")
+}
+
+func (h *Handler) makeRobotsTXT(origin string) []byte {
+	var b bytes.Buffer
+	b.WriteString("User-agent: *\n")
+	b.WriteString("Allow: /\n")
+	for _, path := range h.Sitemap.Disallow {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("\nSitemap: " + origin + h.Resources.Resolve("/sitemap.xml") + "\n")
+	return b.Bytes()
+}
+
+func (h *Handler) makeSitemapXML(routes *router, origin, path string) (cacheItem, error) {
+	urls := h.sitemapURLs(routes, origin)
+	files := (len(urls) + h.Sitemap.MaxURLs - 1) / h.Sitemap.MaxURLs
+
+	var xhtmlNS string
+	if len(h.Locales) != 0 {
+		xhtmlNS = sitemapXHTMLNS
+	}
+
+	var v any
+	switch {
+	case path == "/sitemap.xml" && files <= 1:
+		v = sitemapURLSet{XMLNS: sitemapXMLNS, XHTMLNS: xhtmlNS, URLs: urls}
+
+	case path == "/sitemap.xml":
+		index := sitemapIndex{XMLNS: sitemapXMLNS}
+		for i := 1; i <= files; i++ {
+			index.Sitemaps = append(index.Sitemaps, sitemapIndexEntry{
+				Loc: origin + h.Resources.Resolve("/sitemap-"+strconv.Itoa(i)+".xml"),
+			})
+		}
+		v = index
+
+	default:
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(path, "/sitemap-"), ".xml"))
+		if err != nil || n < 1 || n > files || files <= 1 {
+			return cacheItem{}, errors.New("sitemap file not found").
+				WithType("not-found").
+				WithTag("path", path).
+				WithTag("files", files)
+		}
+
+		start := (n - 1) * h.Sitemap.MaxURLs
+		end := start + h.Sitemap.MaxURLs
+		if end > len(urls) {
+			end = len(urls)
+		}
+		v = sitemapURLSet{XMLNS: sitemapXMLNS, XHTMLNS: xhtmlNS, URLs: urls[start:end]}
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	enc := xml.NewEncoder(&b)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return cacheItem{}, errors.New("encoding sitemap failed").
+			WithTag("path", path).
+			Wrap(err)
+	}
+
+	return cacheItem{
+		Path:        path,
+		ContentType: "application/xml",
+		Body:        b.Bytes(),
+	}, nil
+}
+
+func (h *Handler) sitemapURLs(routes *router, origin string) []sitemapURL {
+	excluded := make(map[string]struct{}, len(h.Sitemap.Exclude))
+	for _, path := range h.Sitemap.Exclude {
+		excluded[path] = struct{}{}
+	}
+
+	var urls []sitemapURL
+	add := func(u SitemapURL) {
+		if _, ok := excluded[u.Loc]; ok || u.Loc == "" {
+			return
+		}
+
+		url := sitemapURL{
+			Loc:        u.Loc,
+			ChangeFreq: u.ChangeFreq,
+		}
+		if !u.LastMod.IsZero() {
+			url.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+		if u.Priority != nil {
+			url.Priority = strconv.FormatFloat(*u.Priority, 'f', 1, 64)
+		}
+
+		if remoteLocation(u.Loc) {
+			urls = append(urls, url)
+			return
+		}
+
+		if len(h.Locales) == 0 {
+			url.Loc = origin + h.Resources.Resolve(u.Loc)
+			urls = append(urls, url)
+			return
+		}
+
+		alternates := make([]sitemapAlternate, 0, len(h.Locales)+1)
+		for _, a := range h.localeAlternates("", u.Loc) {
+			alternates = append(alternates, sitemapAlternate{
+				Rel:      "alternate",
+				HrefLang: a.Lang,
+				Href:     origin + h.Resources.Resolve(a.Href),
+			})
+		}
+		for _, a := range alternates[:len(h.Locales)] {
+			url.Loc = a.Href
+			url.Alternates = alternates
+			urls = append(urls, url)
+		}
+	}
+
+	routes.forEach(func(path string, isPattern bool, newComponent func() Composer) {
+		if enumerator, ok := newComponent().(SitemapEnumerator); ok {
+			for _, u := range enumerator.SitemapURLs() {
+				add(u)
+			}
+			return
+		}
+
+		if !isPattern {
+			add(SitemapURL{Loc: path})
+		}
+	})
+	return urls
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	XHTMLNS string       `xml:"xmlns:xhtml,attr,omitempty"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc        string             `xml:"loc"`
+	LastMod    string             `xml:"lastmod,omitempty"`
+	ChangeFreq string             `xml:"changefreq,omitempty"`
+	Priority   string             `xml:"priority,omitempty"`
+	Alternates []sitemapAlternate `xml:"xhtml:link"`
+}
+
+type sitemapAlternate struct {
+	Rel      string `xml:"rel,attr"`
+	HrefLang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name            `xml:"sitemapindex"`
+	XMLNS    string              `xml:"xmlns,attr"`
+	Sitemaps []sitemapIndexEntry `xml:"sitemap"`
+}
+
+type sitemapIndexEntry struct {
+	Loc string `xml:"loc"`
+}
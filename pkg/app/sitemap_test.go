@@ -0,0 +1,175 @@
+//go:build !wasm
+// +build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sitemapTestCompo struct {
+	Compo
+}
+
+func (c *sitemapTestCompo) SitemapURLs() []SitemapURL {
+	high := 0.8
+	low := 0.0
+
+	return []SitemapURL{
+		{
+			Loc:        "/users/42",
+			LastMod:    time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			ChangeFreq: "weekly",
+			Priority:   &high,
+		},
+		{
+			Loc:      "/users/43",
+			Priority: &low,
+		},
+		{
+			Loc: "https://other.domain/users/21",
+		},
+	}
+}
+
+func TestHandlerServeSitemap(t *testing.T) {
+	h := Handler{
+		Domain: "go-app.dev",
+		Sitemap: &Sitemap{
+			Disallow: []string{"/admin"},
+		},
+	}
+
+	t.Run("sitemap.xml", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		body := w.Body.String()
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+		require.Contains(t, body, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+		require.Contains(t, body, `<loc>https://go-app.dev/</loc>`)
+		t.Log(body)
+	})
+
+	t.Run("robots.txt", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/robots.txt", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		body := w.Body.String()
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+		require.Contains(t, body, "Disallow: /admin\n")
+		require.Contains(t, body, "Sitemap: https://go-app.dev/sitemap.xml\n")
+		t.Log(body)
+	})
+
+	t.Run("non existing sitemap file", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/sitemap-2.xml", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHandlerSitemapIsGeneratedOnce(t *testing.T) {
+	h := Handler{
+		Domain:  "go-app.dev",
+		Sitemap: &Sitemap{},
+	}
+	h.once.Do(h.init)
+	require.Contains(t, h.sitemaps, "/robots.txt")
+	require.Contains(t, h.sitemaps, "/sitemap.xml")
+
+	r := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, string(h.sitemaps["/sitemap.xml"].Body), w.Body.String())
+}
+
+func TestHandlerSitemapRequiresDomain(t *testing.T) {
+	h := Handler{
+		Sitemap: &Sitemap{},
+	}
+	h.once.Do(h.init)
+	require.Nil(t, h.sitemaps)
+
+	r := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	r.Host = "evil.example.com"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.NotContains(t, w.Body.String(), "evil.example.com")
+	require.False(t, h.isSitemapResource("/sitemap.xml"))
+}
+
+func TestHandlerMakeSitemapXML(t *testing.T) {
+	routes := makeRouter()
+	routes.route("/", NewZeroComponentFactory(&routeCompo{}))
+	routes.route("/about", NewZeroComponentFactory(&routeCompo{}))
+	routes.route("/private", NewZeroComponentFactory(&routeCompo{}))
+	routes.routeWithRegexp("^/users/[0-9]+$", NewZeroComponentFactory(&sitemapTestCompo{}))
+	routes.routeWithRegexp("^/posts/.*$", NewZeroComponentFactory(&routeCompo{}))
+
+	h := Handler{
+		Resources: LocalDir(""),
+		Sitemap: &Sitemap{
+			Exclude: []string{"/private"},
+		},
+	}
+	h.initSitemap()
+
+	t.Run("sitemap lists routes and enumerated urls", func(t *testing.T) {
+		item, err := h.makeSitemapXML(&routes, "https://go-app.dev", "/sitemap.xml")
+		require.NoError(t, err)
+
+		body := string(item.Body)
+		require.Contains(t, body, `<loc>https://go-app.dev/</loc>`)
+		require.Contains(t, body, `<loc>https://go-app.dev/about</loc>`)
+		require.Contains(t, body, `<loc>https://go-app.dev/users/42</loc>`)
+		require.Contains(t, body, `<lastmod>2024-06-01T00:00:00Z</lastmod>`)
+		require.Contains(t, body, `<changefreq>weekly</changefreq>`)
+		require.Contains(t, body, `<priority>0.8</priority>`)
+		require.Contains(t, body, `<priority>0.0</priority>`)
+		require.Equal(t, 2, strings.Count(body, "<priority>"))
+		require.Contains(t, body, `<loc>https://other.domain/users/21</loc>`)
+		require.NotContains(t, body, "/private")
+		require.NotContains(t, body, "/posts")
+	})
+
+	t.Run("sitemap is split into an index", func(t *testing.T) {
+		h.Sitemap.MaxURLs = 3
+		defer func() { h.Sitemap.MaxURLs = maxSitemapURLs }()
+
+		require.Equal(t, []string{
+			"/robots.txt",
+			"/sitemap.xml",
+			"/sitemap-1.xml",
+			"/sitemap-2.xml",
+		}, h.sitemapResources(&routes))
+
+		item, err := h.makeSitemapXML(&routes, "https://go-app.dev", "/sitemap.xml")
+		require.NoError(t, err)
+		body := string(item.Body)
+		require.Contains(t, body, `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+		require.Contains(t, body, `<loc>https://go-app.dev/sitemap-1.xml</loc>`)
+		require.Contains(t, body, `<loc>https://go-app.dev/sitemap-2.xml</loc>`)
+
+		item, err = h.makeSitemapXML(&routes, "https://go-app.dev", "/sitemap-2.xml")
+		require.NoError(t, err)
+		body = string(item.Body)
+		require.Contains(t, body, `<loc>https://other.domain/users/21</loc>`)
+		require.NotContains(t, body, `<loc>https://go-app.dev/about</loc>`)
+
+		_, err = h.makeSitemapXML(&routes, "https://go-app.dev", "/sitemap-3.xml")
+		require.Error(t, err)
+	})
+}
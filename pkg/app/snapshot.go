@@ -0,0 +1,225 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+const (
+	// The directory where snapshot golden files are stored, relative to the
+	// directory of the package being tested.
+	snapshotDir = "testdata"
+
+	// The extension of snapshot golden files.
+	snapshotExt = ".golden"
+
+	// The number of unchanged lines displayed around differences.
+	snapshotDiffContext = 3
+)
+
+// MatchSnapshot renders the given UI element to HTML and compares it with the
+// golden file named "testdata/<name>.golden", relative to the directory of
+// the package being tested. The rendered HTML is normalized: attributes are
+// sorted, elements are indented and trailing spaces are removed.
+//
+// It returns an error that contains a line diff when the rendered HTML does
+// not match the golden file, or when the golden file does not exist.
+//
+// Golden files are created or updated instead of being compared when the
+// GOAPP_UPDATE_SNAPSHOTS environment variable is set to a true boolean value,
+// as parsed by strconv.ParseBool:
+//
+//	GOAPP_UPDATE_SNAPSHOTS=true go test ./...
+func MatchSnapshot(name string, v UI) error {
+	return matchSnapshot(name, HTMLString(v))
+}
+
+func (e *engineX) MatchSnapshot(name string) error {
+	if e.body == nil {
+		return errors.New("no component loaded")
+	}
+
+	var b bytes.Buffer
+	e.nodes.Encode(e.baseContext(), &b, e.body.body()[0])
+	return matchSnapshot(name, b.String())
+}
+
+func matchSnapshot(name, html string) error {
+	if name == "" {
+		return errors.New("snapshot name is empty")
+	}
+
+	path := filepath.Join(snapshotDir, filepath.FromSlash(name)+snapshotExt)
+	actual := normalizeSnapshot(html)
+
+	if updateSnapshots() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return errors.New("creating snapshot directory failed").
+				WithTag("path", path).
+				Wrap(err)
+		}
+		if err := os.WriteFile(path, []byte(actual), 0644); err != nil {
+			return errors.New("writing snapshot failed").
+				WithTag("path", path).
+				Wrap(err)
+		}
+		return nil
+	}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return errors.New("snapshot golden file does not exist").
+			WithTag("path", path).
+			WithTag("hint", "set GOAPP_UPDATE_SNAPSHOTS=true to create it")
+	}
+	if err != nil {
+		return errors.New("reading snapshot failed").
+			WithTag("path", path).
+			Wrap(err)
+	}
+
+	expected := normalizeSnapshot(string(b))
+	if expected == actual {
+		return nil
+	}
+	return snapshotMismatch{
+		path: path,
+		diff: diffLines(expected, actual),
+	}
+}
+
+// normalizeSnapshot removes trailing spaces and blank lines, and makes
+// snapshot content end with a single line break.
+func normalizeSnapshot(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+
+	var b strings.Builder
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			continue
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func updateSnapshots() bool {
+	update, _ := strconv.ParseBool(os.Getenv("GOAPP_UPDATE_SNAPSHOTS"))
+	return update
+}
+
+// snapshotMismatch is the error returned when a rendered snapshot does not
+// match its golden file. Its message is a readable line diff rather than an
+// encoded error, in order to be displayed as is in test logs.
+type snapshotMismatch struct {
+	path string
+	diff string
+}
+
+func (e snapshotMismatch) Error() string {
+	return fmt.Sprintf("snapshot does not match %s (set GOAPP_UPDATE_SNAPSHOTS=true to update it):\n--- %s\n+++ rendered\n%s",
+		e.path,
+		e.path,
+		e.diff,
+	)
+}
+
+// diffLines returns the differences between the lines of the expected and
+// actual strings, in unified diff format.
+func diffLines(expected, actual string) string {
+	a := strings.Split(strings.TrimSuffix(expected, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(actual, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and
+	// b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type diffLine struct {
+		op   byte
+		text string
+		i, j int
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{op: ' ', text: a[i], i: i, j: j})
+			i++
+			j++
+
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{op: '-', text: a[i], i: i, j: j})
+			i++
+
+		default:
+			lines = append(lines, diffLine{op: '+', text: b[j], i: i, j: j})
+			j++
+		}
+	}
+
+	var w strings.Builder
+	for start := 0; start < len(lines); {
+		if lines[start].op == ' ' {
+			start++
+			continue
+		}
+
+		// Extends the hunk until changes are separated by more than twice
+		// the context.
+		end := start
+		for k := start; k < len(lines) && k <= end+2*snapshotDiffContext; k++ {
+			if lines[k].op != ' ' {
+				end = k
+			}
+		}
+
+		from := max(start-snapshotDiffContext, 0)
+		to := min(end+snapshotDiffContext+1, len(lines))
+
+		var expectedCount, actualCount int
+		for _, l := range lines[from:to] {
+			if l.op != '+' {
+				expectedCount++
+			}
+			if l.op != '-' {
+				actualCount++
+			}
+		}
+		fmt.Fprintf(&w, "@@ -%d,%d +%d,%d @@\n",
+			lines[from].i+1,
+			expectedCount,
+			lines[from].j+1,
+			actualCount,
+		)
+
+		for _, l := range lines[from:to] {
+			w.WriteByte(l.op)
+			w.WriteString(l.text)
+			w.WriteByte('\n')
+		}
+		start = to
+	}
+	return w.String()
+}
@@ -0,0 +1,141 @@
+//go:build !wasm
+// +build !wasm
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchSnapshot(t *testing.T) {
+	testSnapshotDir(t)
+
+	compo := &hello{Greeting: "Maxence"}
+
+	t.Run("missing golden file returns an error", func(t *testing.T) {
+		err := MatchSnapshot("hello", compo)
+		require.Error(t, err)
+		t.Log(err)
+	})
+
+	t.Run("update creates the golden file", func(t *testing.T) {
+		t.Setenv("GOAPP_UPDATE_SNAPSHOTS", "true")
+		require.NoError(t, MatchSnapshot("components/hello", compo))
+
+		b, err := os.ReadFile(filepath.Join("testdata", "components", "hello.golden"))
+		require.NoError(t, err)
+		require.Equal(t, "<div>\n  <h1>\n    hello,\n    Maxence\n  </h1>\n</div>\n", string(b))
+	})
+
+	t.Run("matching snapshot succeeds", func(t *testing.T) {
+		require.NoError(t, MatchSnapshot("components/hello", compo))
+	})
+
+	t.Run("golden file is normalized", func(t *testing.T) {
+		err := os.WriteFile(filepath.Join("testdata", "components", "hello.golden"),
+			[]byte("<div>  \r\n  <h1>\r\n    hello,\r\n    Maxence\r\n  </h1>\r\n\r\n</div>"), 0644)
+		require.NoError(t, err)
+		require.NoError(t, MatchSnapshot("components/hello", compo))
+	})
+
+	t.Run("attributes are sorted", func(t *testing.T) {
+		t.Setenv("GOAPP_UPDATE_SNAPSHOTS", "true")
+		require.NoError(t, MatchSnapshot("attributes", Div().
+			Title("title").
+			ID("id").
+			Class("class").
+			DataSet("key", "value"),
+		))
+
+		b, err := os.ReadFile(filepath.Join("testdata", "attributes.golden"))
+		require.NoError(t, err)
+		require.Equal(t, "<div class=\"class\" data-key=\"value\" id=\"id\" title=\"title\"></div>\n", string(b))
+	})
+
+	t.Run("update disabled compares the golden file", func(t *testing.T) {
+		t.Setenv("GOAPP_UPDATE_SNAPSHOTS", "false")
+		err := MatchSnapshot("components/hello", &hello{Greeting: "Jonhy"})
+		require.IsType(t, snapshotMismatch{}, err)
+	})
+
+	t.Run("mismatching snapshot returns a diff", func(t *testing.T) {
+		err := MatchSnapshot("components/hello", &hello{Greeting: "Jonhy"})
+		require.Error(t, err)
+		require.IsType(t, snapshotMismatch{}, err)
+		require.Contains(t, err.Error(), "-    Maxence\n+    Jonhy\n")
+		t.Log(err)
+	})
+}
+
+func TestUpdateSnapshots(t *testing.T) {
+	utests := []struct {
+		value    string
+		expected bool
+	}{
+		{value: "", expected: false},
+		{value: "true", expected: true},
+		{value: "1", expected: true},
+		{value: "false", expected: false},
+		{value: "yes", expected: false},
+	}
+
+	for _, u := range utests {
+		t.Run(u.value, func(t *testing.T) {
+			t.Setenv("GOAPP_UPDATE_SNAPSHOTS", u.value)
+			require.Equal(t, u.expected, updateSnapshots())
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	utests := []struct {
+		scenario string
+		expected string
+		actual   string
+		diff     string
+	}{
+		{
+			scenario: "changed line",
+			expected: "a\nb\nc\n",
+			actual:   "a\nB\nc\n",
+			diff:     "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			scenario: "added line",
+			expected: "a\nb\n",
+			actual:   "a\nb\nc\n",
+			diff:     "@@ -1,2 +1,3 @@\n a\n b\n+c\n",
+		},
+		{
+			scenario: "removed line",
+			expected: "a\nb\nc\n",
+			actual:   "b\nc\n",
+			diff:     "@@ -1,3 +1,2 @@\n-a\n b\n c\n",
+		},
+		{
+			scenario: "distant changes are split in hunks",
+			expected: "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			actual:   "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			diff:     "@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			require.Equal(t, u.diff, diffLines(u.expected, u.actual))
+		})
+	}
+}
+
+func testSnapshotDir(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() {
+		os.Chdir(wd)
+	})
+}
@@ -26,7 +26,7 @@ type State struct {
 // ExpiresIn sets the expiration time for the state by specifying a duration
 // from the current time.
 func (s State) ExpiresIn(v time.Duration) State {
-	return s.expire(s, time.Now().Add(v))
+	return s.expire(s, s.ctx.now().Add(v))
 }
 
 // ExpiresAt sets the exact expiration time for the state.
@@ -181,7 +181,7 @@ func (m *stateManager) Get(ctx Context, state string, receiver any) {
 		return
 	}
 
-	if expiredTime(value.expiresAt) {
+	if expiredTime(ctx.now(), value.expiresAt) {
 		delete(m.states, state)
 		ctx.LocalStorage().Del(state)
 		return
@@ -200,7 +200,7 @@ func (m *stateManager) getStoredState(ctx Context, state string, receiver any) e
 		return err
 	}
 
-	if expiredTime(value.ExpiresAt) {
+	if expiredTime(ctx.now(), value.ExpiresAt) {
 		ctx.LocalStorage().Del(state)
 		return nil
 	}
@@ -233,7 +233,7 @@ func (m *stateManager) Set(ctx Context, state string, v any) State {
 			value := m.states[state]
 			m.mutex.RUnlock()
 
-			if expiredTime(value.expiresAt) {
+			if expiredTime(ctx.now(), value.expiresAt) {
 				return
 			}
 
@@ -441,7 +441,7 @@ func (m *stateManager) CleanupExpiredPersistedStates(ctx Context) {
 		var state storableState
 		ctx.LocalStorage().Get(key, &state)
 		if (len(state.Value) != 0 || len(state.EncryptedValue) != 0) &&
-			expiredTime(state.ExpiresAt) {
+			expiredTime(ctx.now(), state.ExpiresAt) {
 			ctx.LocalStorage().Del(key)
 		}
 	})
@@ -473,6 +473,6 @@ func storeValue(recv, v any) error {
 	return nil
 }
 
-func expiredTime(v time.Time) bool {
-	return !v.IsZero() && v.Before(time.Now())
+func expiredTime(now, v time.Time) bool {
+	return !v.IsZero() && v.Before(now)
 }
@@ -20,23 +20,40 @@ import (
 //
 // Note that app.wasm must still be built separately and put into the web
 // directory.
+//
+// When the handler has a Sitemap, the generated sitemap and robots.txt files
+// are written as well. Handler.Domain must then be set for the sitemap URLs to
+// be absolute URLs of the website.
+//
+// Variants with a path prefix and no host are generated under their prefix,
+// and routes are generated for each of the handler Locales.
 func GenerateStaticWebsite(dir string, h *Handler, pages ...string) error {
 	if dir == "" {
 		dir = "."
 	}
 
+	h.once.Do(h.init)
+
 	resources := map[string]struct{}{
 		"/":                     {},
 		"/wasm_exec.js":         {},
 		"/app.js":               {},
 		"/app-worker.js":        {},
+		"/app-webworker.js":     {},
 		"/manifest.webmanifest": {},
 		"/app.css":              {},
 		"/web":                  {},
 	}
 
+	for path := range h.libraries {
+		resources[path] = struct{}{}
+	}
+
 	for path := range routes.routes {
 		resources[path] = struct{}{}
+		for _, locale := range h.Locales {
+			resources[localizedPath(path, locale, h.Locales, h.Lang)] = struct{}{}
+		}
 	}
 
 	for _, p := range pages {
@@ -49,6 +66,25 @@ func GenerateStaticWebsite(dir string, h *Handler, pages ...string) error {
 		resources[p] = struct{}{}
 	}
 
+	for _, v := range h.variants {
+		if v.Host != "" || v.PathPrefix == "" {
+			continue
+		}
+
+		resources[v.PathPrefix+"/app.js"] = struct{}{}
+		resources[v.PathPrefix+"/app-worker.js"] = struct{}{}
+		resources[v.PathPrefix+"/manifest.webmanifest"] = struct{}{}
+		for path := range routes.routes {
+			resources[v.PathPrefix+path] = struct{}{}
+		}
+	}
+
+	if h.Sitemap != nil {
+		for _, path := range h.sitemapResources(&routes) {
+			resources[path] = struct{}{}
+		}
+	}
+
 	server := httptest.NewServer(h)
 	defer server.Close()
 
@@ -61,8 +97,8 @@ func GenerateStaticWebsite(dir string, h *Handler, pages ...string) error {
 
 		default:
 			filename := path
-			if filename == "/" {
-				filename = "/index.html"
+			if strings.HasSuffix(filename, "/") {
+				filename += "index.html"
 			}
 
 			f, err := createStaticFile(dir, filename)
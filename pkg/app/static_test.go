@@ -22,6 +22,11 @@ func TestGenerateStaticWebsite(t *testing.T) {
 			Name:      "Static Go-app",
 			Title:     "Static test",
 			Resources: GitHubPages("go-app"),
+			Domain:    "go-app.dev",
+			Sitemap:   &Sitemap{},
+			Variants: []Variant{
+				{PathPrefix: "/shop"},
+			},
 		},
 		"/hello",
 		"world",
@@ -41,6 +46,12 @@ func TestGenerateStaticWebsite(t *testing.T) {
 		filepath.Join(dir, "hello.html"),
 		filepath.Join(dir, "world.html"),
 		filepath.Join(dir, "nested", "foo.html"),
+		filepath.Join(dir, "sitemap.xml"),
+		filepath.Join(dir, "robots.txt"),
+		filepath.Join(dir, "shop", "index.html"),
+		filepath.Join(dir, "shop", "app.js"),
+		filepath.Join(dir, "shop", "app-worker.js"),
+		filepath.Join(dir, "shop", "manifest.webmanifest"),
 	}
 
 	for _, f := range files {
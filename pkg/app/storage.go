@@ -116,12 +116,16 @@ func (s *memoryStorage) Contains(k string) bool {
 }
 
 type jsStorage struct {
-	name  string
-	mutex sync.RWMutex
+	window BrowserWindow
+	name   string
+	mutex  sync.RWMutex
 }
 
-func newJSStorage(name string) *jsStorage {
-	return &jsStorage{name: name}
+func newJSStorage(w BrowserWindow, name string) *jsStorage {
+	return &jsStorage{
+		window: w,
+		name:   name,
+	}
 }
 
 func (s *jsStorage) Set(k string, v any) (err error) {
@@ -143,7 +147,7 @@ func (s *jsStorage) Set(k string, v any) (err error) {
 		return err
 	}
 
-	Window().Get(s.name).Call("setItem", k, string(b))
+	s.window.Get(s.name).Call("setItem", k, string(b))
 	return nil
 }
 
@@ -151,7 +155,7 @@ func (s *jsStorage) Get(k string, v any) error {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	item := Window().Get(s.name).Call("getItem", k)
+	item := s.window.Get(s.name).Call("getItem", k)
 	if item.IsNull() {
 		return nil
 	}
@@ -162,13 +166,13 @@ func (s *jsStorage) Get(k string, v any) error {
 func (s *jsStorage) Del(k string) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	Window().Get(s.name).Call("removeItem", k)
+	s.window.Get(s.name).Call("removeItem", k)
 }
 
 func (s *jsStorage) Clear() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	Window().Get(s.name).Call("clear")
+	s.window.Get(s.name).Call("clear")
 }
 
 func (s *jsStorage) Len() int {
@@ -178,7 +182,7 @@ func (s *jsStorage) Len() int {
 }
 
 func (s *jsStorage) len() int {
-	return Window().Get(s.name).Get("length").Int()
+	return s.window.Get(s.name).Get("length").Int()
 }
 
 func (s *jsStorage) ForEach(f func(key string)) {
@@ -186,7 +190,7 @@ func (s *jsStorage) ForEach(f func(key string)) {
 	length := s.len()
 	keys := make(map[string]struct{}, length)
 	for i := 0; i < length; i++ {
-		key := Window().Get(s.name).Call("key", i)
+		key := s.window.Get(s.name).Call("key", i)
 		if key.Truthy() {
 			keys[key.String()] = struct{}{}
 		}
@@ -201,5 +205,5 @@ func (s *jsStorage) ForEach(f func(key string)) {
 func (s *jsStorage) Contains(k string) bool {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	return !Window().Get(s.name).Call("getItem", k).IsNull()
+	return !s.window.Get(s.name).Call("getItem", k).IsNull()
 }
@@ -13,12 +13,12 @@ func TestMemoryStorage(t *testing.T) {
 
 func TestJSLocalStorage(t *testing.T) {
 	testSkipNonWasm(t)
-	testBrowserStorage(t, newJSStorage("localStorage"))
+	testBrowserStorage(t, newJSStorage(Window(), "localStorage"))
 }
 
 func TestJSSessionStorage(t *testing.T) {
 	testSkipNonWasm(t)
-	testBrowserStorage(t, newJSStorage("sessionStorage"))
+	testBrowserStorage(t, newJSStorage(Window(), "sessionStorage"))
 }
 
 type obj struct {
@@ -0,0 +1,426 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const componentStylesPath = "/components.css"
+
+var (
+	injectedStylesMutex sync.Mutex
+	injectedStyles      map[string]struct{}
+)
+
+// StyleScope returns the class name that scopes the styles of the given
+// component. The class is added to the root element of components that
+// implement Styler, and is the same on the server and the client.
+func StyleScope(v Composer) string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(t.PkgPath() + "." + t.Name()))
+	return fmt.Sprintf("goapp-%08x", h.Sum32())
+}
+
+// ScopeCSS returns the given CSS code with every selector scoped by the given
+// class name. Each compound selector is restricted to the elements that have
+// the data-<class> attribute, which is added to the HTML elements rendered by
+// the components that implement Styler, but not to the ones rendered by their
+// child components. The :scope pseudo-class is replaced by the class to match
+// the root elements of the components. Rules within @media, @supports,
+// @container and @layer blocks are scoped as well, while other at-rules such
+// as @keyframes and @font-face are left as is.
+func ScopeCSS(class, css string) string {
+	var b strings.Builder
+	writeScopedCSS(&b, class, stripCSSComments(css))
+	return b.String()
+}
+
+func writeScopedCSS(b *strings.Builder, scope, css string) {
+	for {
+		css = strings.TrimSpace(css)
+		if css == "" {
+			return
+		}
+
+		end := indexCSS(css, ";{")
+		if end < 0 {
+			b.WriteString(css)
+			b.WriteByte('\n')
+			return
+		}
+		if css[end] == ';' {
+			b.WriteString(css[:end+1])
+			b.WriteByte('\n')
+			css = css[end+1:]
+			continue
+		}
+
+		prelude := strings.TrimSpace(css[:end])
+		close := matchingCSSBrace(css, end)
+		body := css[end+1 : close]
+		if close < len(css) {
+			css = css[close+1:]
+		} else {
+			css = ""
+		}
+
+		switch {
+		case isGroupingAtRule(prelude):
+			b.WriteString(prelude)
+			b.WriteString(" {\n")
+			writeScopedCSS(b, scope, body)
+			b.WriteString("}\n")
+
+		case strings.HasPrefix(prelude, "@"):
+			b.WriteString(prelude)
+			b.WriteString(" {")
+			b.WriteString(body)
+			b.WriteString("}\n")
+
+		default:
+			b.WriteString(scopeSelectors(scope, prelude))
+			b.WriteString(" {")
+			b.WriteString(body)
+			b.WriteString("}\n")
+		}
+	}
+}
+
+func isGroupingAtRule(prelude string) bool {
+	name, _, _ := strings.Cut(strings.TrimPrefix(prelude, "@"), " ")
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "media", "supports", "container", "layer", "document":
+		return strings.HasPrefix(prelude, "@")
+
+	default:
+		return false
+	}
+}
+
+func scopeSelectors(scope, selectors string) string {
+	var scoped []string
+	for _, s := range splitCSS(selectors, ',') {
+		if s = strings.TrimSpace(s); s != "" {
+			scoped = append(scoped, scopeSelector(scope, s))
+		}
+	}
+	return strings.Join(scoped, ", ")
+}
+
+// scopeSelector scopes each compound selector of the given complex selector.
+// Compound selectors that contain the :scope pseudo-class match the root
+// elements of the component, while others match the elements it renders.
+func scopeSelector(scope, selector string) string {
+	var b strings.Builder
+	writeCompound := func(compound string) {
+		if strings.Contains(compound, ":scope") {
+			b.WriteString(strings.ReplaceAll(compound, ":scope", "."+scope))
+			return
+		}
+		i := indexPseudoElement(compound)
+		b.WriteString(compound[:i])
+		b.WriteString("[data-" + scope + "]")
+		b.WriteString(compound[i:])
+	}
+
+	var quote byte
+	var depth int
+	start := 0
+	for i := 0; i < len(selector); i++ {
+		c := selector[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+
+		case c == '"' || c == '\'':
+			quote = c
+
+		case c == '(' || c == '[':
+			depth++
+
+		case c == ')' || c == ']':
+			depth--
+
+		case depth <= 0 && strings.IndexByte(" \t\n\r\f>+~", c) >= 0:
+			if start < i {
+				writeCompound(selector[start:i])
+			}
+			combinator := strings.TrimSpace(string(c))
+			for i+1 < len(selector) && strings.IndexByte(" \t\n\r\f>+~", selector[i+1]) >= 0 {
+				i++
+				if combinator == "" {
+					combinator = strings.TrimSpace(string(selector[i]))
+				}
+			}
+			if combinator == "" {
+				b.WriteByte(' ')
+			} else {
+				b.WriteString(" " + combinator + " ")
+			}
+			start = i + 1
+		}
+	}
+	if start < len(selector) {
+		writeCompound(selector[start:])
+	}
+	return b.String()
+}
+
+// indexPseudoElement returns the index of the pseudo-element of the given
+// compound selector, or its length when it has none.
+func indexPseudoElement(compound string) int {
+	for i := 0; i < len(compound); {
+		n := indexCSS(compound[i:], ":[")
+		if n < 0 {
+			break
+		}
+		i += n
+		if compound[i] == '[' {
+			if end := strings.IndexByte(compound[i:], ']'); end >= 0 {
+				i += end + 1
+				continue
+			}
+			break
+		}
+
+		rest := strings.ToLower(compound[i:])
+		if strings.HasPrefix(rest, "::") {
+			return i
+		}
+		for _, legacy := range []string{":before", ":after", ":first-line", ":first-letter"} {
+			if strings.HasPrefix(rest, legacy) {
+				return i
+			}
+		}
+		i++
+	}
+	return len(compound)
+}
+
+// indexCSS returns the index of the first of the given characters that is
+// not within a string or parentheses, or -1.
+func indexCSS(css, chars string) int {
+	var quote byte
+	var depth int
+	for i := 0; i < len(css); i++ {
+		c := css[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+
+		case c == '"' || c == '\'':
+			quote = c
+
+		case c == '(':
+			depth++
+
+		case c == ')':
+			depth--
+
+		case depth <= 0 && strings.IndexByte(chars, c) >= 0:
+			return i
+		}
+	}
+	return -1
+}
+
+// matchingCSSBrace returns the index of the brace that closes the one at the
+// given index, or the length of the code when it is not closed.
+func matchingCSSBrace(css string, open int) int {
+	depth := 0
+	for i := open; i < len(css); {
+		n := indexCSS(css[i:], "{}")
+		if n < 0 {
+			break
+		}
+		i += n
+		if css[i] == '{' {
+			depth++
+		} else if depth--; depth == 0 {
+			return i
+		}
+		i++
+	}
+	return len(css)
+}
+
+func splitCSS(css string, sep byte) []string {
+	var parts []string
+	for {
+		i := indexCSS(css, string(sep))
+		if i < 0 {
+			return append(parts, css)
+		}
+		parts = append(parts, css[:i])
+		css = css[i+1:]
+	}
+}
+
+func stripCSSComments(css string) string {
+	for {
+		start := strings.Index(css, "/*")
+		if start < 0 {
+			return css
+		}
+		end := strings.Index(css[start+2:], "*/")
+		if end < 0 {
+			return css[:start]
+		}
+		css = css[:start] + css[start+2+end+2:]
+	}
+}
+
+// scopeComponentRendering adds the style scopes of the given component, and
+// of the parent components it is the root of, to the given root. When the
+// root is a component, the scopes are added to its own root. The HTML elements
+// rendered by a component that implements Styler are also given the attribute
+// its styles are scoped to, up to its child components.
+func scopeComponentRendering(v Composer, root UI) {
+	scopes := v.styleScopes()
+	if _, ok := v.(Styler); ok {
+		scope := StyleScope(v)
+		scopes = append(scopes[:len(scopes):len(scopes)], scope)
+		setScopeAttribute(root, "data-"+scope)
+	}
+	if len(scopes) == 0 {
+		return
+	}
+
+	switch root := root.(type) {
+	case Composer:
+		root.setStyleScopes(scopes)
+
+	case HTML:
+		attrs := copyAttributes(root.attrs())
+		for _, scope := range scopes {
+			attrs.Set("class", scope)
+		}
+		root.setAttrs(attrs)
+	}
+}
+
+// setScopeAttribute adds the given attribute to the given element and its
+// descendants, up to the components.
+func setScopeAttribute(v UI, name string) {
+	switch v := v.(type) {
+	case HTML:
+		attrs := copyAttributes(v.attrs())
+		attrs.Set(name, "")
+		v.setAttrs(attrs)
+		for _, child := range v.body() {
+			setScopeAttribute(child, name)
+		}
+
+	case *portal:
+		for _, child := range v.children {
+			setScopeAttribute(child, name)
+		}
+	}
+}
+
+func copyAttributes(v attributes) attributes {
+	attrs := make(attributes, len(v)+1)
+	for name, value := range v {
+		attrs[name] = value
+	}
+	return attrs
+}
+
+func (h *Handler) componentStyles() ([]byte, []string) {
+	var stylers []Styler
+	routes.forEach(func(path string, isPattern bool, newComponent func() Composer) {
+		if styler, ok := newComponent().(Styler); ok {
+			stylers = append(stylers, styler)
+		}
+	})
+	stylers = append(stylers, h.Stylers...)
+
+	var b strings.Builder
+	var scopes []string
+	scoped := make(map[string]struct{}, len(stylers))
+	for _, styler := range stylers {
+		compo, ok := styler.(Composer)
+		if !ok {
+			continue
+		}
+
+		scope := StyleScope(compo)
+		if _, ok := scoped[scope]; ok {
+			continue
+		}
+		scoped[scope] = struct{}{}
+
+		if styles := ScopeCSS(scope, styler.Styles()); styles != "" {
+			b.WriteString(styles)
+			scopes = append(scopes, scope)
+		}
+	}
+	return []byte(b.String()), scopes
+}
+
+// injectComponentStyles adds the scoped styles of the given component into
+// the page head, unless they are served in the component stylesheet or were
+// already injected.
+func injectComponentStyles(ctx Context, v Composer) {
+	styler, ok := v.(Styler)
+	if !ok || IsServer {
+		return
+	}
+
+	injectedStylesMutex.Lock()
+	defer injectedStylesMutex.Unlock()
+
+	if injectedStyles == nil {
+		var scopes []string
+		json.Unmarshal([]byte(Getenv("GOAPP_COMPONENT_STYLES")), &scopes)
+
+		injectedStyles = make(map[string]struct{}, len(scopes))
+		for _, scope := range scopes {
+			injectedStyles[scope] = struct{}{}
+		}
+	}
+
+	scope := StyleScope(v)
+	if _, ok := injectedStyles[scope]; ok {
+		return
+	}
+	injectedStyles[scope] = struct{}{}
+
+	styles := ScopeCSS(scope, styler.Styles())
+	if styles == "" {
+		return
+	}
+
+	style, err := ctx.currentWindow().createElement("style", "")
+	if err != nil {
+		Log(err)
+		return
+	}
+	style.setAttr("data-goapp-scope", scope)
+	if nonced := ctx.currentWindow().Get("document").Call("querySelector", "[nonce]"); nonced.Truthy() {
+		// Browsers hide the nonce attribute value, which remains available
+		// from the nonce property.
+		if nonce := nonced.Get("nonce"); nonce.Truthy() {
+			style.Set("nonce", nonce.String())
+		}
+	}
+	style.Set("textContent", styles)
+	ctx.currentWindow().Get("document").Get("head").appendChild(style)
+}
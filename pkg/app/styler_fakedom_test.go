@@ -0,0 +1,43 @@
+//go:build !wasm && fakedom
+// +build !wasm,fakedom
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStylerNestedComponentIsNotStyled(t *testing.T) {
+	scope := StyleScope(&stylerTestCompo{})
+
+	e := NewTestEngine()
+	require.NoError(t, e.Load(&stylerTestCompo{}))
+	e.ConsumeAll()
+
+	title, err := e.Query(".title")
+	require.NoError(t, err)
+	require.True(t, title.JSValue().Call("hasAttribute", "data-"+scope).Bool())
+
+	nested, err := e.Query("p")
+	require.NoError(t, err)
+	require.False(t, nested.JSValue().Call("hasAttribute", "data-"+scope).Bool())
+	require.NotContains(t, nested.JSValue().Get("className").String(), scope)
+
+	// Selectors without :scope only match the elements that have the
+	// attribute, which excludes the ones rendered by nested components.
+	require.Equal(t, "p[data-"+scope+"] { margin: 0 }\n", ScopeCSS(scope, "p { margin: 0 }"))
+}
+
+func TestStylerMountedComponentRootClass(t *testing.T) {
+	class := `class="card ` + StyleScope(&wrapperStylerTestCompo{}) + ` ` + StyleScope(&stylerTestCompo{}) + `"`
+
+	e := NewTestEngine()
+	require.NoError(t, e.Load(&outerWrapperStylerTestCompo{}))
+	e.ConsumeAll()
+
+	card, err := e.Query(".card")
+	require.NoError(t, err)
+	require.Equal(t, class, `class="`+card.JSValue().Get("className").String()+`"`)
+}
@@ -0,0 +1,199 @@
+//go:build !wasm
+// +build !wasm
+
+package app
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stylerTestCompo struct {
+	Compo
+}
+
+func (c *stylerTestCompo) Styles() string {
+	return `
+		:scope { display: flex; }
+		.title { color: red; }
+	`
+}
+
+func (c *stylerTestCompo) Render() UI {
+	return Div().
+		Class("card").
+		Body(
+			H1().Class("title").Text("hello"),
+			&nestedStylerTestCompo{},
+		)
+}
+
+type nestedStylerTestCompo struct {
+	Compo
+}
+
+func (c *nestedStylerTestCompo) Styles() string {
+	return `p { margin: 0; }`
+}
+
+func (c *nestedStylerTestCompo) Render() UI {
+	return P().Text("nested")
+}
+
+func TestScopeCSS(t *testing.T) {
+	utests := []struct {
+		scenario string
+		css      string
+		expected string
+	}{
+		{
+			scenario: "empty",
+			css:      " ",
+			expected: "",
+		},
+		{
+			scenario: "selector",
+			css:      ".title { color: red; }",
+			expected: ".title[data-s] { color: red; }\n",
+		},
+		{
+			scenario: "selector list",
+			css:      "h1, h2:is(.a, .b) { margin: 0 }",
+			expected: "h1[data-s], h2:is(.a, .b)[data-s] { margin: 0 }\n",
+		},
+		{
+			scenario: "scope pseudo-class",
+			css:      ":scope { display: flex } :scope > p { margin: 0 }",
+			expected: ".s { display: flex }\n.s > p[data-s] { margin: 0 }\n",
+		},
+		{
+			scenario: "comments",
+			css:      "/* title */ .title { color: red }",
+			expected: ".title[data-s] { color: red }\n",
+		},
+		{
+			scenario: "media",
+			css:      "@media (max-width: 600px) { .title { display: none } }",
+			expected: "@media (max-width: 600px) {\n.title[data-s] { display: none }\n}\n",
+		},
+		{
+			scenario: "keyframes",
+			css:      "@keyframes spin { from { opacity: 0 } to { opacity: 1 } }",
+			expected: "@keyframes spin { from { opacity: 0 } to { opacity: 1 } }\n",
+		},
+		{
+			scenario: "statement at-rule",
+			css:      `@import url("a;b.css"); a { color: blue }`,
+			expected: "@import url(\"a;b.css\");\na[data-s] { color: blue }\n",
+		},
+		{
+			scenario: "quoted brace",
+			css:      `a::after { content: "}" }`,
+			expected: "a[data-s]::after { content: \"}\" }\n",
+		},
+		{
+			scenario: "combinators",
+			css:      "ul >li+li ~ li a:hover { color: blue }",
+			expected: "ul[data-s] > li[data-s] + li[data-s] ~ li[data-s] a:hover[data-s] { color: blue }\n",
+		},
+		{
+			scenario: "attribute selector",
+			css:      `a[title="a > b"], input[type = text] { color: blue }`,
+			expected: "a[title=\"a > b\"][data-s], input[type = text][data-s] { color: blue }\n",
+		},
+		{
+			scenario: "legacy pseudo-element",
+			css:      "p:first-child:before { content: '-' }",
+			expected: "p:first-child[data-s]:before { content: '-' }\n",
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			require.Equal(t, u.expected, ScopeCSS("s", u.css))
+		})
+	}
+}
+
+func TestStyleScope(t *testing.T) {
+	scope := StyleScope(&stylerTestCompo{})
+	require.Regexp(t, `^goapp-[0-9a-f]{8}$`, scope)
+	require.Equal(t, scope, StyleScope(&stylerTestCompo{}))
+	require.NotEqual(t, scope, StyleScope(&nestedStylerTestCompo{}))
+}
+
+func TestStylerRootClass(t *testing.T) {
+	scope := StyleScope(&stylerTestCompo{})
+	nestedScope := StyleScope(&nestedStylerTestCompo{})
+
+	html := HTMLString(&stylerTestCompo{})
+	require.Contains(t, html, `<div class="card `+scope+`" data-`+scope+`>`)
+	require.Contains(t, html, `<h1 class="title" data-`+scope+`>`)
+	require.Contains(t, html, `<p class="`+nestedScope+`" data-`+nestedScope+`>`)
+}
+
+func TestHandlerServeComponentStyles(t *testing.T) {
+	h := Handler{
+		Stylers: []Styler{
+			&stylerTestCompo{},
+			&nestedStylerTestCompo{},
+			&stylerTestCompo{},
+		},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `href="/components.css"`)
+
+	r = httptest.NewRequest(http.MethodGet, "/components.css", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/css", w.Header().Get("Content-Type"))
+
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+
+	scope := StyleScope(&stylerTestCompo{})
+	nestedScope := StyleScope(&nestedStylerTestCompo{})
+	require.Equal(t, ""+
+		"."+scope+" { display: flex; }\n"+
+		".title[data-"+scope+"] { color: red; }\n"+
+		"p[data-"+nestedScope+"] { margin: 0; }\n",
+		string(body),
+	)
+	require.Equal(t, []string{scope, nestedScope}, h.styleScopes)
+	require.Contains(t, h.Env["GOAPP_COMPONENT_STYLES"], nestedScope)
+}
+
+type wrapperStylerTestCompo struct {
+	Compo
+}
+
+func (c *wrapperStylerTestCompo) Styles() string {
+	return `:scope { margin: 0; }`
+}
+
+func (c *wrapperStylerTestCompo) Render() UI {
+	return &stylerTestCompo{}
+}
+
+type outerWrapperStylerTestCompo struct {
+	Compo
+}
+
+func (c *outerWrapperStylerTestCompo) Render() UI {
+	return &wrapperStylerTestCompo{}
+}
+
+func TestStylerComponentRootClass(t *testing.T) {
+	class := `class="card ` + StyleScope(&wrapperStylerTestCompo{}) + ` ` + StyleScope(&stylerTestCompo{}) + `"`
+	require.Contains(t, HTMLString(&wrapperStylerTestCompo{}), class)
+	require.Contains(t, HTMLString(&outerWrapperStylerTestCompo{}), class)
+}
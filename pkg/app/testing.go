@@ -4,6 +4,9 @@ import (
 	"context"
 	"net/url"
 	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/whale1017/go-app/v10/pkg/errors"
 )
@@ -27,23 +30,627 @@ type TestEngine interface {
 	// component's state is fully updated, allowing for accurate assertions and
 	// verifications in test scenarios.
 	ConsumeAll()
+
+	// Query returns the first HTML element of the loaded component tree that
+	// matches the given CSS selector. Supported selectors are type, universal,
+	// id, class and attribute selectors, the :not(), :first-child and
+	// :last-child pseudo-classes, and the descendant, child and sibling
+	// combinators. Components are transparent: their root elements are
+	// matched as children of the elements that contain the components.
+	Query(selector string) (HTML, error)
+
+	// QueryAll returns the HTML elements of the loaded component tree that
+	// match the given CSS selector, in document order.
+	QueryAll(selector string) ([]HTML, error)
+
+	// QueryText returns the innermost HTML element of the loaded component
+	// tree whose text content is the given text, ignoring leading and
+	// trailing spaces.
+	QueryText(text string) (HTML, error)
+
+	// QueryComponent returns the first component of the loaded component tree
+	// that has the same type as the given component.
+	QueryComponent(v Composer) (Composer, error)
+
+	// Click simulates a click on the HTML element that matches the given CSS
+	// selector: mousedown, focus, mouseup and click events are dispatched,
+	// then the resulting operations are consumed, like with ConsumeAll.
+	Click(selector string) error
+
+	// Input simulates the typing of the given value in the HTML element that
+	// matches the given CSS selector: its value is set, input and change
+	// events are dispatched, then the resulting operations are consumed.
+	Input(selector, value string) error
+
+	// Submit simulates the submission of the form that matches the given CSS
+	// selector, or that contains the matching element: a submit event is
+	// dispatched, then the resulting operations are consumed.
+	Submit(selector string) error
+
+	// KeyDown simulates the press of the given key, such as "Enter" or
+	// "ArrowDown", on the HTML element that matches the given CSS selector: a
+	// keydown event is dispatched, then the resulting operations are
+	// consumed.
+	KeyDown(selector, key string) error
+
+	// Route associates the type of the given component to the specified
+	// path, like the Route function does for an app.
+	Route(path string, newComponent func() Composer)
+
+	// RouteWithRegexp associates the type of the given component to the
+	// paths that match the given regular expression, like the RouteWithRegexp
+	// function does for an app.
+	RouteWithRegexp(pattern string, newComponent func() Composer)
+
+	// NavigateTo loads the component routed to the given URL, which can be
+	// relative, adds it to the history, then consumes the resulting
+	// operations.
+	NavigateTo(rawURL string) error
+
+	// URL returns the URL of the current page.
+	URL() *url.URL
+
+	// SetState sets the value of the given state, notifies its observers, then
+	// consumes the resulting operations.
+	SetState(state string, v any) State
+
+	// GetState stores the value of the given state into the given receiver.
+	// Expired states are not retrieved.
+	GetState(state string, recv any)
+
+	// DelState deletes the given state.
+	DelState(state string)
+
+	// Actions returns the actions that have been posted since the engine has
+	// been created, in posting order.
+	Actions() []Action
+
+	// UseFakeClock makes the engine use a fake time that starts on
+	// 2024-01-01 at 00:00:00 UTC and that only moves forward with Advance.
+	// Functions scheduled with Context.After are then only called by Advance,
+	// and states expire according to the fake time. It must be called before
+	// loading components.
+	UseFakeClock()
+
+	// Now returns the current time of the engine, which is the fake time when
+	// UseFakeClock has been called.
+	Now() time.Time
+
+	// Advance moves the fake time of the engine forward by the given
+	// duration. Functions scheduled with Context.After that are due are called
+	// in chronological order, each followed by the consumption of the
+	// resulting operations. It panics when UseFakeClock has not been called.
+	Advance(d time.Duration)
+
+	// MatchSnapshot compares the HTML markup of the loaded component tree with
+	// the golden file named "testdata/<name>.golden". See the MatchSnapshot
+	// function for more details.
+	MatchSnapshot(name string) error
 }
 
 // NewTestEngine creates and returns a new instance of test engine configured
 // for unit testing.
+//
+// On non-wasm architectures, tests built with the fakedom build tag, e.g. with
+// "go test -tags fakedom", render components into an in-memory DOM. It tracks
+// nodes, attributes, texts, event listeners, history and storages, which allows
+// to assert component behavior without a browser. Each engine has its own DOM,
+// which is distinct from the window returned by Window(). Without the build
+// tag, the DOM operations are no-ops and simulating events returns an error.
 func NewTestEngine() TestEngine {
 	origin, _ := url.Parse("/")
 	originPage := makeRequestPage(origin, nil)
 
 	routes := makeRouter()
-	return newEngine(context.Background(),
+	engine := newEngine(context.Background(),
 		&routes,
 		nil,
 		&originPage,
-		map[string]ActionHandler{
-			"/test": func(ctx Context, a Action) {},
-		},
+		actionHandlers,
 	)
+	engine.window = newTestWindow()
+	engine.localStorage = newJSStorage(engine.window, "localStorage")
+	engine.sessionStorage = newJSStorage(engine.window, "sessionStorage")
+	engine.test = &testHarness{}
+	return engine
+}
+
+func (e *engineX) Route(path string, newComponent func() Composer) {
+	e.routes.route(path, newComponent)
+}
+
+func (e *engineX) RouteWithRegexp(pattern string, newComponent func() Composer) {
+	e.routes.routeWithRegexp(pattern, newComponent)
+}
+
+func (e *engineX) NavigateTo(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.New("parsing url failed").
+			WithTag("url", rawURL).
+			Wrap(err)
+	}
+
+	e.Navigate(e.originPage.URL().ResolveReference(u), true)
+	e.ConsumeAll()
+	return nil
+}
+
+func (e *engineX) URL() *url.URL {
+	u := *e.lastVisitedURL
+	return &u
+}
+
+func (e *engineX) SetState(state string, v any) State {
+	s := e.baseContext().SetState(state, v)
+	e.ConsumeAll()
+	return s
+}
+
+func (e *engineX) GetState(state string, recv any) {
+	e.baseContext().GetState(state, recv)
+}
+
+func (e *engineX) DelState(state string) {
+	e.baseContext().DelState(state)
+	e.ConsumeAll()
+}
+
+func (e *engineX) Actions() []Action {
+	return e.test.postedActions()
+}
+
+func (e *engineX) UseFakeClock() {
+	e.test.useFakeClock(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func (e *engineX) Now() time.Time {
+	return e.now()
+}
+
+func (e *engineX) Advance(d time.Duration) {
+	if !e.test.usesFakeClock() {
+		panic(errors.New("advancing time requires a fake clock").
+			WithTag("duration", d))
+	}
+
+	end := e.test.now().Add(d)
+	for {
+		f, ok := e.test.nextTimer(end)
+		if !ok {
+			break
+		}
+		f()
+		e.ConsumeAll()
+	}
+	e.test.setTime(end)
+	e.ConsumeAll()
+}
+
+// testHarness holds the posted actions and the optional fake time of a test
+// engine.
+type testHarness struct {
+	mutex     sync.Mutex
+	fakeClock bool
+	time      time.Time
+	timers    []*testTimer
+	actions   []Action
+}
+
+type testTimer struct {
+	at time.Time
+	f  func()
+}
+
+func (h *testHarness) useFakeClock(start time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.fakeClock = true
+	h.time = start
+}
+
+func (h *testHarness) usesFakeClock() bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.fakeClock
+}
+
+func (h *testHarness) now() time.Time {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.time
+}
+
+func (h *testHarness) setTime(v time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.time = v
+}
+
+func (h *testHarness) after(d time.Duration, f func()) func() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	timer := &testTimer{
+		at: h.time.Add(d),
+		f:  f,
+	}
+	h.timers = append(h.timers, timer)
+
+	return func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		for i, t := range h.timers {
+			if t == timer {
+				h.timers = append(h.timers[:i], h.timers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// nextTimer removes the earliest timer that is due before or at the given
+// time, moves the time to its due time and returns its function.
+func (h *testHarness) nextTimer(end time.Time) (func(), bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	next := -1
+	for i, t := range h.timers {
+		if !t.at.After(end) && (next < 0 || t.at.Before(h.timers[next].at)) {
+			next = i
+		}
+	}
+	if next < 0 {
+		return nil, false
+	}
+
+	t := h.timers[next]
+	h.timers = append(h.timers[:next], h.timers[next+1:]...)
+	if t.at.After(h.time) {
+		h.time = t.at
+	}
+	return t.f, true
+}
+
+func (h *testHarness) recordAction(a Action) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.actions = append(h.actions, a)
+}
+
+func (h *testHarness) postedActions() []Action {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return append([]Action(nil), h.actions...)
+}
+
+func (e *engineX) Query(selector string) (HTML, error) {
+	elems, err := e.query(selector, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(elems) == 0 {
+		return nil, errors.New("no html element matches the selector").
+			WithTag("selector", selector)
+	}
+	return elems[0], nil
+}
+
+func (e *engineX) QueryAll(selector string) ([]HTML, error) {
+	return e.query(selector, false)
+}
+
+func (e *engineX) query(selector string, first bool) ([]HTML, error) {
+	if e.body == nil {
+		return nil, errors.New("no component loaded")
+	}
+
+	sel, err := parseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var elems []HTML
+	walkUI(e.body, func(v UI) bool {
+		if v, ok := v.(HTML); ok && v != e.body && sel.match(uiSelectorNode{v}, nil) {
+			elems = append(elems, v)
+			return !first
+		}
+		return true
+	})
+	return elems, nil
+}
+
+func (e *engineX) QueryText(text string) (HTML, error) {
+	if e.body == nil {
+		return nil, errors.New("no component loaded")
+	}
+
+	text = strings.TrimSpace(text)
+	var find func(UI) HTML
+	find = func(v UI) HTML {
+		for _, c := range uiChildren(v) {
+			if elem := find(c); elem != nil {
+				return elem
+			}
+		}
+		if elem, ok := v.(HTML); ok && elem != e.body && strings.TrimSpace(uiText(elem)) == text {
+			return elem
+		}
+		return nil
+	}
+
+	if elem := find(e.body); elem != nil {
+		return elem, nil
+	}
+	return nil, errors.New("no html element has the text").
+		WithTag("text", text)
+}
+
+func (e *engineX) QueryComponent(v Composer) (Composer, error) {
+	if e.body == nil {
+		return nil, errors.New("no component loaded")
+	}
+
+	var compo Composer
+	walkUI(e.body, func(c UI) bool {
+		if c, ok := c.(Composer); ok && reflect.TypeOf(c) == reflect.TypeOf(v) {
+			compo = c
+			return false
+		}
+		return true
+	})
+	if compo == nil {
+		return nil, errors.New("no component has the type").
+			WithTag("type", reflect.TypeOf(v))
+	}
+	return compo, nil
+}
+
+func (e *engineX) Click(selector string) error {
+	elem, err := e.queryEnabled(selector)
+	if err != nil {
+		return err
+	}
+
+	doc := e.window.Get("document")
+	active := doc.Get("activeElement")
+
+	mouseInit := map[string]any{
+		"bubbles":    true,
+		"cancelable": true,
+		"button":     0,
+		"detail":     1,
+	}
+	if e.dispatchTestEvent(elem, "MouseEvent", "mousedown", mouseInit) {
+		elem.Call("focus")
+		if focused := doc.Get("activeElement"); !focused.Equal(elem) &&
+			active.Truthy() &&
+			active.Equal(focused) &&
+			!active.Call("contains", elem).Bool() {
+			active.Call("blur")
+		}
+	}
+	e.dispatchTestEvent(elem, "MouseEvent", "mouseup", mouseInit)
+	elem.Call("click")
+
+	e.ConsumeAll()
+	return nil
+}
+
+func (e *engineX) Input(selector, value string) error {
+	elem, err := e.queryEnabled(selector)
+	if err != nil {
+		return err
+	}
+
+	elem.Set("value", value)
+	e.dispatchTestEvent(elem, "InputEvent", "input", map[string]any{
+		"bubbles":   true,
+		"inputType": "insertText",
+		"data":      value,
+	})
+	e.dispatchTestEvent(elem, "Event", "change", map[string]any{
+		"bubbles": true,
+	})
+
+	e.ConsumeAll()
+	return nil
+}
+
+func (e *engineX) Submit(selector string) error {
+	elem, err := e.queryEnabled(selector)
+	if err != nil {
+		return err
+	}
+
+	init := map[string]any{
+		"bubbles":    true,
+		"cancelable": true,
+	}
+
+	form := elem
+	if !elem.Call("matches", "form").Bool() {
+		form = elem.Call("closest", "form")
+		init["submitter"] = elem
+	}
+	if !form.Truthy() {
+		return errors.New("html element is not in a form").
+			WithTag("selector", selector)
+	}
+	e.dispatchTestEvent(form, "SubmitEvent", "submit", init)
+
+	e.ConsumeAll()
+	return nil
+}
+
+func (e *engineX) KeyDown(selector, key string) error {
+	elem, err := e.queryEnabled(selector)
+	if err != nil {
+		return err
+	}
+
+	e.dispatchTestEvent(elem, "KeyboardEvent", "keydown", map[string]any{
+		"bubbles":    true,
+		"cancelable": true,
+		"key":        key,
+	})
+
+	e.ConsumeAll()
+	return nil
+}
+
+// queryEnabled returns the JavaScript value of the first mounted HTML element
+// that matches the given selector and that is not disabled.
+func (e *engineX) queryEnabled(selector string) (Value, error) {
+	if !e.window.Get("Event").Truthy() {
+		return nil, errors.New("simulating events requires a dom").
+			WithTag("selector", selector).
+			WithTag("build-tag", "fakedom")
+	}
+
+	elem, err := e.Query(selector)
+	if err != nil {
+		return nil, err
+	}
+	if !elem.Mounted() {
+		return nil, errors.New("html element is not mounted").
+			WithTag("selector", selector).
+			WithTag("tag", elem.Tag())
+	}
+
+	jsElem := elem.JSValue()
+	if jsElem.Get("disabled").Truthy() {
+		return nil, errors.New("html element is disabled").
+			WithTag("selector", selector).
+			WithTag("tag", elem.Tag())
+	}
+	return jsElem, nil
+}
+
+// dispatchTestEvent dispatches an event created with the given constructor
+// name, type and options to the given element. It returns false when the
+// default action of the event has been prevented.
+func (e *engineX) dispatchTestEvent(elem Value, class, typ string, init map[string]any) bool {
+	event := e.window.Get(class).New(typ, init)
+	return elem.Call("dispatchEvent", event).Bool()
+}
+
+// uiSelectorNode wraps an HTML element of a UI tree to be matched by CSS
+// selectors.
+type uiSelectorNode struct {
+	HTML
+}
+
+func (n uiSelectorNode) selectorTag() string {
+	return strings.ToLower(n.Tag())
+}
+
+func (n uiSelectorNode) selectorAttr(name string) (string, bool) {
+	v, ok := n.attrs()[name]
+	return v, ok
+}
+
+func (n uiSelectorNode) selectorParent() selectorNode {
+	if p := parentHTML(n.HTML); p != nil {
+		return uiSelectorNode{p}
+	}
+	return nil
+}
+
+func (n uiSelectorNode) selectorPrevious() selectorNode {
+	p := parentHTML(n.HTML)
+	if p == nil {
+		return nil
+	}
+
+	var previous HTML
+	for _, c := range childrenHTML(p) {
+		if c == n.HTML {
+			break
+		}
+		previous = c
+	}
+	if previous == nil {
+		return nil
+	}
+	return uiSelectorNode{previous}
+}
+
+func (n uiSelectorNode) selectorLastChild() selectorNode {
+	children := childrenHTML(n.HTML)
+	if len(children) == 0 {
+		return nil
+	}
+	return uiSelectorNode{children[len(children)-1]}
+}
+
+// uiChildren returns the direct children of the given UI element.
+func uiChildren(v UI) []UI {
+	switch v := v.(type) {
+	case HTML:
+		return v.body()
+
+	case *portal:
+		return v.children
+
+	case Composer:
+		if root := v.root(); root != nil {
+			return []UI{root}
+		}
+	}
+	return nil
+}
+
+// walkUI calls the given function on the descendants of the given UI element,
+// in document order, until it returns false.
+func walkUI(v UI, f func(UI) bool) bool {
+	for _, c := range uiChildren(v) {
+		if !f(c) || !walkUI(c, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// parentHTML returns the closest HTML element that contains the given UI
+// element.
+func parentHTML(v UI) HTML {
+	for p := v.parent(); p != nil; p = p.parent() {
+		if p, ok := p.(HTML); ok {
+			return p
+		}
+	}
+	return nil
+}
+
+// childrenHTML returns the closest HTML elements contained in the given UI
+// element.
+func childrenHTML(v UI) []HTML {
+	var children []HTML
+	for _, c := range uiChildren(v) {
+		if c, ok := c.(HTML); ok {
+			children = append(children, c)
+			continue
+		}
+		children = append(children, childrenHTML(c)...)
+	}
+	return children
+}
+
+// uiText returns the text content of the given UI element.
+func uiText(v UI) string {
+	if t, ok := v.(*text); ok {
+		return t.value
+	}
+
+	var b strings.Builder
+	for _, c := range uiChildren(v) {
+		b.WriteString(uiText(c))
+	}
+	return b.String()
 }
 
 // Match compares the expected UI element with another UI element at a specified
@@ -114,6 +721,17 @@ func TestMatch(root UI, d TestUIDescriptor) error {
 			d.Path = d.Path[1:]
 			return TestMatch(children[index], d)
 
+		case *portal:
+			if index < 0 || index >= len(root.children) {
+				return errors.New("element to match is out of range").
+					WithTag("type", reflect.TypeOf(d.Expected)).
+					WithTag("parent-type", reflect.TypeOf(root)).
+					WithTag("parent-children-count", len(root.children)).
+					WithTag("index", index)
+			}
+			d.Path = d.Path[1:]
+			return TestMatch(root.children[index], d)
+
 		case Composer:
 			if index != 0 {
 				return errors.New("element to match is out of range").
@@ -150,6 +768,9 @@ func match(n UI, d TestUIDescriptor) error {
 	case *raw:
 		return matchRaw(n.(*raw), d)
 
+	case *portal:
+		return matchPortal(n.(*portal), d)
+
 	default:
 		return errors.New("unsupported element").
 			WithTag("type", reflect.TypeOf(n))
@@ -281,3 +902,18 @@ func matchRaw(n *raw, d TestUIDescriptor) error {
 
 	return nil
 }
+
+func matchPortal(n *portal, d TestUIDescriptor) error {
+	a := n
+	b := d.Expected.(*portal)
+
+	if a.target != b.target {
+		return errors.New("the portal is not matching with the descriptor").
+			WithTag("type", reflect.TypeOf(n)).
+			WithTag("reason", "unexpected target id").
+			WithTag("expected-target-id", b.target).
+			WithTag("current-target-id", a.target)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+//go:build wasm || fakedom
+// +build wasm fakedom
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestEngineEvents(t *testing.T) {
+	e := NewTestEngine()
+	compo := &testEngineForm{}
+	require.NoError(t, e.Load(compo))
+	e.ConsumeAll()
+
+	t.Run("click", func(t *testing.T) {
+		require.NoError(t, e.Click("button.count"))
+		require.NoError(t, e.Click("button.count"))
+		require.Equal(t, 2, compo.clicks)
+	})
+
+	t.Run("click on disabled element returns an error", func(t *testing.T) {
+		require.Error(t, e.Click("button.disabled"))
+	})
+
+	t.Run("click without match returns an error", func(t *testing.T) {
+		require.Error(t, e.Click("button.missing"))
+	})
+
+	t.Run("click focuses the element", func(t *testing.T) {
+		require.NoError(t, e.Click("input"))
+		require.True(t, compo.focused)
+	})
+
+	t.Run("input", func(t *testing.T) {
+		require.NoError(t, e.Input("input[name=name]", "Maxence"))
+		require.Equal(t, "Maxence", compo.name)
+		require.True(t, compo.changed)
+
+		p, err := e.QueryText("Hello Maxence")
+		require.NoError(t, err)
+		require.Equal(t, "greeting", p.attrs()["class"])
+	})
+
+	t.Run("key down", func(t *testing.T) {
+		require.NoError(t, e.KeyDown("input", "Enter"))
+		require.Equal(t, "Enter", compo.key)
+	})
+
+	t.Run("submit form", func(t *testing.T) {
+		require.NoError(t, e.Submit("#form"))
+		require.Equal(t, 1, compo.submitted)
+	})
+
+	t.Run("submit from form element", func(t *testing.T) {
+		require.NoError(t, e.Submit("button.submit"))
+		require.Equal(t, 2, compo.submitted)
+	})
+
+	t.Run("submit outside of a form returns an error", func(t *testing.T) {
+		require.NoError(t, e.Load(&hello{}))
+		e.ConsumeAll()
+		require.Error(t, e.Submit("h1"))
+	})
+}
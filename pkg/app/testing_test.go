@@ -4,6 +4,7 @@ import (
 	"os"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/whale1017/go-app/v10/pkg/logs"
@@ -181,3 +182,273 @@ func testCreateFile(t *testing.T, path, content string) {
 	err := os.WriteFile(path, []byte(content), 0666)
 	require.NoError(t, err)
 }
+
+type testEngineForm struct {
+	Compo
+
+	name      string
+	changed   bool
+	focused   bool
+	key       string
+	submitted int
+	clicks    int
+}
+
+func (f *testEngineForm) Render() UI {
+	return Form().
+		ID("form").
+		OnSubmit(func(ctx Context, e Event) {
+			e.PreventDefault()
+			f.submitted++
+		}).
+		Body(
+			Label().Body(
+				Span().Text("Name"),
+			),
+			Input().
+				Name("name").
+				Value(f.name).
+				OnFocus(func(ctx Context, e Event) {
+					f.focused = true
+				}).
+				OnInput(func(ctx Context, e Event) {
+					f.name = ctx.JSSrc().Get("value").String()
+				}).
+				OnChange(func(ctx Context, e Event) {
+					f.changed = true
+				}).
+				OnKeyDown(func(ctx Context, e Event) {
+					f.key = e.Get("key").String()
+				}),
+			P().Class("greeting").Text("Hello "+f.name),
+			&hello{},
+			Button().
+				Class("count").
+				Type("button").
+				OnClick(func(ctx Context, e Event) {
+					f.clicks++
+				}).
+				Text("Count"),
+			Button().
+				Class("disabled").
+				Type("button").
+				Disabled(true).
+				Text("Disabled"),
+			Button().
+				Class("submit").
+				Type("submit").
+				Text("Submit"),
+		)
+}
+
+func TestTestEngineQuery(t *testing.T) {
+	e := NewTestEngine()
+
+	_, err := e.Query("form")
+	require.Error(t, err)
+
+	compo := &testEngineForm{}
+	require.NoError(t, e.Load(compo))
+	e.ConsumeAll()
+
+	t.Run("query returns the first match", func(t *testing.T) {
+		button, err := e.Query("form > button")
+		require.NoError(t, err)
+		require.Equal(t, "count", button.attrs()["class"])
+	})
+
+	t.Run("query through components", func(t *testing.T) {
+		h1, err := e.Query("form > div > h1")
+		require.NoError(t, err)
+		require.Equal(t, "h1", h1.Tag())
+
+		next, err := e.Query("div + button")
+		require.NoError(t, err)
+		require.Equal(t, "count", next.attrs()["class"])
+	})
+
+	t.Run("query all returns matches in order", func(t *testing.T) {
+		buttons, err := e.QueryAll("#form button:not(.disabled)")
+		require.NoError(t, err)
+		require.Len(t, buttons, 2)
+		require.Equal(t, "count", buttons[0].attrs()["class"])
+		require.Equal(t, "submit", buttons[1].attrs()["class"])
+
+		last, err := e.Query("button:last-child")
+		require.NoError(t, err)
+		require.Equal(t, "submit", last.attrs()["class"])
+	})
+
+	t.Run("query without match returns an error", func(t *testing.T) {
+		_, err := e.Query("table")
+		require.Error(t, err)
+	})
+
+	t.Run("query with invalid selector returns an error", func(t *testing.T) {
+		_, err := e.QueryAll("div >")
+		require.Error(t, err)
+	})
+
+	t.Run("query text returns the innermost element", func(t *testing.T) {
+		span, err := e.QueryText(" Name ")
+		require.NoError(t, err)
+		require.Equal(t, "span", span.Tag())
+
+		_, err = e.QueryText("Goodbye")
+		require.Error(t, err)
+	})
+
+	t.Run("query component returns the component", func(t *testing.T) {
+		c, err := e.QueryComponent(&hello{})
+		require.NoError(t, err)
+		require.IsType(t, &hello{}, c)
+
+		c, err = e.QueryComponent(&testEngineForm{})
+		require.NoError(t, err)
+		require.Equal(t, compo, c)
+
+		_, err = e.QueryComponent(&foo{})
+		require.Error(t, err)
+	})
+}
+
+type testEngineTimer struct {
+	Compo
+
+	ticks int
+}
+
+func (c *testEngineTimer) OnNav(ctx Context) {
+	c.tick(ctx)
+}
+
+func (c *testEngineTimer) tick(ctx Context) {
+	ctx.After(time.Second, func(ctx Context) {
+		c.ticks++
+		if c.ticks < 3 {
+			c.tick(ctx)
+		}
+	})
+}
+
+func (c *testEngineTimer) Render() UI {
+	return Span().Text(c.ticks)
+}
+
+func TestTestEngineNavigate(t *testing.T) {
+	e := NewTestEngine()
+	e.Route("/hello", func() Composer { return &hello{} })
+	e.RouteWithRegexp("^/foo/.*", func() Composer { return &foo{} })
+
+	require.NoError(t, e.NavigateTo("/hello?name=Maxence"))
+	require.Equal(t, "/hello", e.URL().Path)
+	require.Equal(t, "Maxence", e.URL().Query().Get("name"))
+	_, err := e.QueryComponent(&hello{})
+	require.NoError(t, err)
+
+	require.NoError(t, e.NavigateTo("/foo/bar"))
+	require.Equal(t, "/foo/bar", e.URL().Path)
+	_, err = e.QueryComponent(&foo{})
+	require.NoError(t, err)
+
+	require.NoError(t, e.NavigateTo("/unknown"))
+	_, err = e.QueryComponent(&notFound{})
+	require.NoError(t, err)
+
+	require.Error(t, e.NavigateTo(":bad"))
+}
+
+type testEngineObserver struct {
+	Compo
+
+	Number int
+}
+
+func (o *testEngineObserver) OnNav(ctx Context) {
+	ctx.ObserveState("observed-number", &o.Number)
+}
+
+func (o *testEngineObserver) Render() UI {
+	return Span().Text(o.Number)
+}
+
+func TestTestEngineState(t *testing.T) {
+	e := NewTestEngine()
+	e.UseFakeClock()
+
+	compo := &testEngineObserver{}
+	e.Route("/", func() Composer { return compo })
+	require.NoError(t, e.NavigateTo("/"))
+
+	e.SetState("observed-number", 42).ExpiresIn(time.Minute)
+	require.Equal(t, 42, compo.Number)
+
+	var v int
+	e.GetState("observed-number", &v)
+	require.Equal(t, 42, v)
+
+	e.Advance(time.Minute + time.Second)
+	v = 0
+	e.GetState("observed-number", &v)
+	require.Zero(t, v)
+
+	e.SetState("observed-number", 21)
+	e.DelState("observed-number")
+	v = 0
+	e.GetState("observed-number", &v)
+	require.Zero(t, v)
+}
+
+func TestTestEngineActions(t *testing.T) {
+	e := NewTestEngine()
+
+	compo := &hello{}
+	require.NoError(t, e.Load(compo))
+	e.ConsumeAll()
+	require.Empty(t, e.Actions())
+
+	ctx := e.(*engineX).nodes.context(e.(*engineX).baseContext(), compo)
+	ctx.NewAction("greet", T("name", "Maxence"))
+	ctx.NewActionWithValue("count", 42)
+	e.ConsumeAll()
+
+	actions := e.Actions()
+	require.Len(t, actions, 2)
+	require.Equal(t, "greet", actions[0].Name)
+	require.Equal(t, "Maxence", actions[0].Tags.Get("name"))
+	require.Equal(t, "count", actions[1].Name)
+	require.Equal(t, 42, actions[1].Value)
+}
+
+func TestTestEngineAdvance(t *testing.T) {
+	e := NewTestEngine()
+	e.UseFakeClock()
+	start := e.Now()
+	require.Equal(t, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), start)
+
+	compo := &testEngineTimer{}
+	e.Route("/", func() Composer { return compo })
+	require.NoError(t, e.NavigateTo("/"))
+	require.Zero(t, compo.ticks)
+
+	e.Advance(time.Millisecond * 999)
+	require.Zero(t, compo.ticks)
+
+	e.Advance(time.Millisecond)
+	require.Equal(t, 1, compo.ticks)
+	require.Equal(t, start.Add(time.Second), e.Now())
+
+	e.Advance(time.Minute)
+	require.Equal(t, 3, compo.ticks)
+	require.Equal(t, start.Add(time.Minute+time.Second), e.Now())
+
+	span, err := e.Query("span")
+	require.NoError(t, err)
+	require.Equal(t, "3", uiText(span))
+}
+
+func TestTestEngineRealClock(t *testing.T) {
+	e := NewTestEngine()
+	require.WithinDuration(t, time.Now(), e.Now(), time.Minute)
+	require.Panics(t, func() { e.Advance(time.Second) })
+}
@@ -0,0 +1,237 @@
+package app
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// The maximum time a transition waits for its animations to finish.
+	transitionTimeout = 5 * time.Second
+)
+
+// TransitionWrapper is the interface that describes an element animated with
+// CSS transitions or animations when it is mounted and removed.
+type TransitionWrapper interface {
+	Composer
+
+	// Move animates the children of the element to their new position when the
+	// element is updated by its parent, for the given duration. Children are
+	// matched across updates by their data-key attribute, set with
+	// DataSet("key", v).
+	Move(d time.Duration) TransitionWrapper
+}
+
+// Transition returns a component that animates the given element with CSS
+// classes prefixed by the given name:
+//
+//   - When the element is mounted, the name-enter and name-enter-active classes
+//     are added. The name-enter class is removed once its style is applied, and
+//     the name-enter-active class is removed once the animations are finished.
+//   - When the element is removed, the name-leave-active class is added, then
+//     the name-leave class once its style is applied. The element stays in the
+//     DOM until the animations are finished.
+//
+// Animations include CSS transitions, CSS animations and Web Animations
+// started on the element or its children. Example of a fade transition:
+//
+//	.fade-enter-active, .fade-leave-active {
+//	    transition: opacity 0.3s;
+//	}
+//
+//	.fade-enter, .fade-leave {
+//	    opacity: 0;
+//	}
+func Transition(name string, elem UI) TransitionWrapper {
+	return &transition{
+		Iname:    name,
+		Ielement: elem,
+	}
+}
+
+type transition struct {
+	Compo
+
+	Iname         string
+	Ielement      UI
+	ImoveDuration time.Duration
+
+	positions        map[string]transitionPosition
+	cancelAnimations func()
+}
+
+type transitionPosition struct {
+	elem Value
+	x    float64
+	y    float64
+}
+
+func (t *transition) Move(d time.Duration) TransitionWrapper {
+	t.ImoveDuration = d
+	return t
+}
+
+func (t *transition) OnMount(ctx Context) {
+	elem := t.JSValue()
+	if t.Iname == "" || !elem.Get("classList").Truthy() {
+		return
+	}
+
+	classes := elem.Get("classList")
+	classes.Call("add", t.Iname+"-enter", t.Iname+"-enter-active")
+	elem.Get("offsetWidth")
+	classes.Call("remove", t.Iname+"-enter")
+
+	t.cancelAnimations = waitAnimations(ctx, elem, func() {
+		classes.Call("remove", t.Iname+"-enter-active")
+	})
+}
+
+func (t *transition) OnUpdate(ctx Context) {
+	if t.ImoveDuration <= 0 {
+		return
+	}
+
+	t.positions = childPositions(t.JSValue())
+	ctx.Defer(func(ctx Context) {
+		t.move()
+	})
+}
+
+func (t *transition) OnLeave(ctx Context, done func()) {
+	elem := t.JSValue()
+	if t.Iname == "" || !elem.Get("classList").Truthy() {
+		done()
+		return
+	}
+
+	classes := elem.Get("classList")
+	classes.Call("remove", t.Iname+"-enter", t.Iname+"-enter-active")
+	classes.Call("add", t.Iname+"-leave-active")
+	elem.Get("offsetWidth")
+	classes.Call("add", t.Iname+"-leave")
+
+	t.stopWaiting()
+	t.cancelAnimations = waitAnimations(ctx, elem, done)
+}
+
+func (t *transition) OnDismount() {
+	t.stopWaiting()
+}
+
+func (t *transition) Render() UI {
+	return t.Ielement
+}
+
+// stopWaiting stops waiting for the animations of the element, if any.
+func (t *transition) stopWaiting() {
+	if t.cancelAnimations != nil {
+		t.cancelAnimations()
+		t.cancelAnimations = nil
+	}
+}
+
+// move plays the FLIP animations of the children that have moved since their
+// positions were recorded.
+func (t *transition) move() {
+	positions := t.positions
+	t.positions = nil
+
+	for key, position := range childPositions(t.JSValue()) {
+		previous, ok := positions[key]
+		if !ok {
+			continue
+		}
+
+		dx := previous.x - position.x
+		dy := previous.y - position.y
+		if dx == 0 && dy == 0 {
+			continue
+		}
+
+		if !position.elem.Get("animate").Truthy() {
+			continue
+		}
+		position.elem.Call("animate",
+			[]any{
+				map[string]any{"transform": fmt.Sprintf("translate(%gpx, %gpx)", dx, dy)},
+				map[string]any{"transform": "none"},
+			},
+			map[string]any{
+				"duration": t.ImoveDuration.Milliseconds(),
+				"easing":   "ease",
+			},
+		)
+	}
+}
+
+// childPositions returns the positions of the keyed children of the given
+// element.
+func childPositions(elem Value) map[string]transitionPosition {
+	if !elem.Get("children").Truthy() {
+		return nil
+	}
+
+	children := elem.Get("children")
+	positions := make(map[string]transitionPosition, children.Length())
+	for i := 0; i < children.Length(); i++ {
+		child := children.Index(i)
+
+		key := child.getAttr("data-key")
+		if key == "" {
+			continue
+		}
+
+		rect := child.Call("getBoundingClientRect")
+		positions[key] = transitionPosition{
+			elem: child,
+			x:    rect.Get("left").Float(),
+			y:    rect.Get("top").Float(),
+		}
+	}
+	return positions
+}
+
+// waitAnimations calls the given function on the UI goroutine once the
+// animations of the given element and its children are finished, or after
+// transitionTimeout when they take too long. The returned function stops
+// waiting without calling the given one.
+func waitAnimations(ctx Context, elem Value, done func()) func() {
+	finished := false
+	stopTimer := func() {}
+	finish := func() {
+		if !finished {
+			finished = true
+			stopTimer()
+			done()
+		}
+	}
+	cancel := func() {
+		finished = true
+		stopTimer()
+	}
+
+	if !elem.Get("getAnimations").Truthy() {
+		finish()
+		return cancel
+	}
+
+	animations := elem.Call("getAnimations", map[string]any{"subtree": true})
+	if animations.Length() == 0 {
+		finish()
+		return cancel
+	}
+
+	stopTimer = ctx.after(transitionTimeout, func() {
+		ctx.dispatch(finish)
+	})
+
+	promises := make([]any, animations.Length())
+	for i := range promises {
+		promises[i] = animations.Index(i).Get("finished")
+	}
+	ctx.currentWindow().Get("Promise").Call("allSettled", promises).Then(func(Value) {
+		ctx.dispatch(finish)
+	})
+	return cancel
+}
@@ -0,0 +1,229 @@
+//go:build !wasm && fakedom
+// +build !wasm,fakedom
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type leaver struct {
+	Compo
+
+	done func()
+}
+
+func (l *leaver) OnLeave(ctx Context, done func()) {
+	l.done = done
+}
+
+func (l *leaver) Render() UI {
+	return Span()
+}
+
+func TestNodeManagerRemoveLeaver(t *testing.T) {
+	ctx := makeTestContext()
+
+	t.Run("removed leaver is dismounted once it has left", func(t *testing.T) {
+		var m nodeManager
+
+		div, err := m.Mount(ctx, 1, Div().Body(&leaver{}))
+		require.NoError(t, err)
+		l := div.(HTML).body()[0].(*leaver)
+
+		div, err = m.Update(ctx, div, Div())
+		require.NoError(t, err)
+		require.Empty(t, div.(HTML).body())
+		require.True(t, l.Mounted())
+		require.NotNil(t, l.done)
+
+		l.done()
+		require.False(t, l.Mounted())
+		l.done()
+	})
+
+	t.Run("replaced leaver is dismounted once it has left", func(t *testing.T) {
+		var m nodeManager
+
+		div, err := m.Mount(ctx, 1, Div().Body(&leaver{}))
+		require.NoError(t, err)
+		l := div.(HTML).body()[0].(*leaver)
+
+		div, err = m.Update(ctx, div, Div().Body(Text("hello")))
+		require.NoError(t, err)
+		require.IsType(t, &text{}, div.(HTML).body()[0])
+		require.True(t, l.Mounted())
+
+		l.done()
+		require.False(t, l.Mounted())
+	})
+
+	t.Run("leaving leaver is dismounted with its parent", func(t *testing.T) {
+		var m nodeManager
+
+		div, err := m.Mount(ctx, 1, Div().Body(&leaver{}))
+		require.NoError(t, err)
+		l := div.(HTML).body()[0].(*leaver)
+
+		div, err = m.Update(ctx, div, Div())
+		require.NoError(t, err)
+		require.True(t, l.Mounted())
+
+		m.Dismount(div)
+		require.False(t, l.Mounted())
+		require.Empty(t, div.(*htmlDiv).leavingChildren())
+		l.done()
+	})
+
+	t.Run("leaving component root is dismounted with the component", func(t *testing.T) {
+		var m nodeManager
+
+		compo := &leaverRootCompo{}
+		_, err := m.Mount(ctx, 1, Div().Body(compo))
+		require.NoError(t, err)
+		l := compo.root().(*leaver)
+
+		compo.Left = true
+		_, err = m.UpdateComponentRoot(ctx, compo)
+		require.NoError(t, err)
+		require.True(t, l.Mounted())
+
+		m.Dismount(compo)
+		require.False(t, l.Mounted())
+	})
+
+	t.Run("portal leaver leaves when the portal is dismounted", func(t *testing.T) {
+		var m nodeManager
+
+		div, err := m.Mount(ctx, 1, Div().Body(Portal("", &leaver{})))
+		require.NoError(t, err)
+		l := div.(HTML).body()[0].(*portal).children[0].(*leaver)
+
+		_, err = m.Update(ctx, div, Div())
+		require.NoError(t, err)
+		require.True(t, l.Mounted())
+		require.NotNil(t, l.done)
+
+		l.done()
+		require.False(t, l.Mounted())
+	})
+}
+
+type leaverRootCompo struct {
+	Compo
+
+	Left bool
+}
+
+func (c *leaverRootCompo) Render() UI {
+	if c.Left {
+		return Text("left")
+	}
+	return &leaver{}
+}
+
+func TestWaitAnimations(t *testing.T) {
+	newAnimatedElem := func() (Value, *fakePromise) {
+		finished, p := newFakePromise()
+		animation := fakeValueOf(map[string]any{"finished": finished})
+		elem := fakeValueOf(map[string]any{
+			"getAnimations": fakeMethod(func(args []Value) any {
+				return fakeArray(animation)
+			}),
+		})
+		return elem, p
+	}
+
+	newContext := func() (*engineX, Context) {
+		e := NewTestEngine().(*engineX)
+		e.UseFakeClock()
+		return e, e.baseContext()
+	}
+
+	t.Run("finished animations stop the timeout", func(t *testing.T) {
+		e, ctx := newContext()
+		elem, p := newAnimatedElem()
+
+		calls := 0
+		waitAnimations(ctx, elem, func() { calls++ })
+		require.Len(t, e.test.timers, 1)
+
+		p.resolve(fakeValueOf(nil))
+		e.ConsumeAll()
+		require.Equal(t, 1, calls)
+		require.Empty(t, e.test.timers)
+
+		e.Advance(transitionTimeout)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("long animations time out", func(t *testing.T) {
+		e, ctx := newContext()
+		elem, p := newAnimatedElem()
+
+		calls := 0
+		waitAnimations(ctx, elem, func() { calls++ })
+
+		e.Advance(transitionTimeout)
+		require.Equal(t, 1, calls)
+
+		p.resolve(fakeValueOf(nil))
+		e.ConsumeAll()
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("canceled wait stops the timeout", func(t *testing.T) {
+		e, ctx := newContext()
+		elem, p := newAnimatedElem()
+
+		calls := 0
+		cancel := waitAnimations(ctx, elem, func() { calls++ })
+		cancel()
+		require.Empty(t, e.test.timers)
+
+		p.resolve(fakeValueOf(nil))
+		e.Advance(transitionTimeout)
+		require.Zero(t, calls)
+	})
+}
+
+func TestTransition(t *testing.T) {
+	ctx := makeTestContext()
+
+	t.Run("transition renders its element", func(t *testing.T) {
+		require.Equal(t,
+			"<div>\n  <span>hello</span>\n</div>",
+			HTMLString(Div().Body(Transition("fade", Span().Text("hello")))),
+		)
+	})
+
+	t.Run("transition is mounted, moved and removed", func(t *testing.T) {
+		var m nodeManager
+
+		div, err := m.Mount(ctx, 1, Div().Body(
+			Transition("fade", Ul().Body(
+				Li().DataSet("key", "a"),
+				Li().DataSet("key", "b"),
+			)).Move(time.Millisecond*200),
+		))
+		require.NoError(t, err)
+		transition := div.(HTML).body()[0].(*transition)
+		require.True(t, transition.Mounted())
+
+		div, err = m.Update(ctx, div, Div().Body(
+			Transition("fade", Ul().Body(
+				Li().DataSet("key", "b"),
+				Li().DataSet("key", "a"),
+			)).Move(time.Millisecond*200),
+		))
+		require.NoError(t, err)
+		require.Len(t, transition.root().(HTML).body(), 2)
+
+		_, err = m.Update(ctx, div, Div())
+		require.NoError(t, err)
+		require.False(t, transition.Mounted())
+	})
+}
@@ -0,0 +1,149 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Variant describes a version of the app served by a Handler for a given host
+// or URL path prefix, such as a tenant or a white-label build. Each variant
+// has its own manifest and service worker. Empty fields fall back to the
+// Handler ones.
+type Variant struct {
+	// Host is the host the variant is served on, e.g. "acme.example.com".
+	// Matches any host when empty.
+	Host string
+
+	// PathPrefix is the URL path prefix the variant is served under, e.g.
+	// "/acme". The variant manifest and service worker are scoped to this
+	// prefix, and routes are matched against the path that follows it.
+	// Matches any path when empty.
+	PathPrefix string
+
+	// Name specifies the display name of the variant.
+	Name string
+
+	// ShortName is an abbreviated variant name for limited display areas.
+	ShortName string
+
+	// Description provides a summary of the variant.
+	Description string
+
+	// Icon represents the variant icon used for PWA, favicon and loading
+	// screen. The handler icon is used when empty. Otherwise, the large and
+	// maskable icons default to the variant default icon, and the handler SVG
+	// icon is not used.
+	Icon Icon
+
+	// BackgroundColor sets a placeholder background color for the variant
+	// pages before stylesheets load.
+	BackgroundColor string
+
+	// ThemeColor specifies the variant theme color.
+	ThemeColor string
+
+	// DarkBackgroundColor and DarkThemeColor are the variant background and
+	// theme colors used when the user prefers a dark color scheme.
+	DarkBackgroundColor string
+	DarkThemeColor      string
+}
+
+type appVariant struct {
+	Variant
+
+	id                 string
+	cachedPWAResources *memoryCache
+}
+
+func (h *Handler) initVariants() {
+	h.defaultVariant = &appVariant{
+		Variant: Variant{
+			Name:            h.Name,
+			ShortName:       h.ShortName,
+			Description:     h.Description,
+			Icon:            h.Icon,
+			BackgroundColor: h.BackgroundColor,
+			ThemeColor:      h.ThemeColor,
+
+			DarkBackgroundColor: h.DarkBackgroundColor,
+			DarkThemeColor:      h.DarkThemeColor,
+		},
+	}
+
+	h.variants = make([]*appVariant, 0, len(h.Variants))
+	for _, v := range h.Variants {
+		if v.PathPrefix = strings.Trim(v.PathPrefix, "/"); v.PathPrefix != "" {
+			v.PathPrefix = "/" + v.PathPrefix
+		}
+
+		if v.Name == "" && v.ShortName == "" {
+			v.Name = h.Name
+			v.ShortName = h.ShortName
+		}
+		if v.ShortName == "" {
+			v.ShortName = v.Name
+		}
+		if v.Name == "" {
+			v.Name = v.ShortName
+		}
+		if v.Description == "" {
+			v.Description = h.Description
+		}
+		if v.Icon == (Icon{}) {
+			v.Icon = h.Icon
+		}
+		if v.Icon.Default == "" {
+			v.Icon.Default = h.Icon.Default
+		}
+		if v.Icon.Large == "" {
+			v.Icon.Large = v.Icon.Default
+		}
+		if v.Icon.Maskable == "" {
+			v.Icon.Maskable = v.Icon.Default
+		}
+		if v.BackgroundColor == "" {
+			v.BackgroundColor = h.BackgroundColor
+		}
+		if v.ThemeColor == "" {
+			v.ThemeColor = h.ThemeColor
+		}
+		if v.DarkBackgroundColor == "" {
+			v.DarkBackgroundColor = h.DarkBackgroundColor
+		}
+		if v.DarkThemeColor == "" {
+			v.DarkThemeColor = h.DarkThemeColor
+		}
+
+		h.variants = append(h.variants, &appVariant{
+			Variant: v,
+			id:      strings.Trim(v.Host+v.PathPrefix, "/"),
+		})
+	}
+}
+
+func (h *Handler) variant(r *http.Request, path string) *appVariant {
+	for _, v := range h.variants {
+		if v.Host != "" && v.Host != r.Host && v.Host != hostname(r.Host) {
+			continue
+		}
+		if v.PathPrefix != "" && path != v.PathPrefix && !strings.HasPrefix(path, v.PathPrefix+"/") {
+			continue
+		}
+		return v
+	}
+	return h.defaultVariant
+}
+
+func (v *appVariant) trimPathPrefix(path string) string {
+	if path = strings.TrimPrefix(path, v.PathPrefix); !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
+func hostname(host string) string {
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.HasSuffix(host, "]") {
+		return host[:i]
+	}
+	return host
+}
@@ -0,0 +1,120 @@
+//go:build !wasm
+// +build !wasm
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerServeVariants(t *testing.T) {
+	h := Handler{
+		Name:       "Default",
+		ThemeColor: "#000000",
+		Icon: Icon{
+			Default:  "/web/icon.png",
+			Large:    "/web/large.png",
+			Maskable: "/web/maskable.png",
+		},
+		Variants: []Variant{
+			{
+				Host:       "acme.go-app.dev",
+				Name:       "Acme",
+				ThemeColor: "#ff0000",
+			},
+			{
+				PathPrefix: "shop/",
+				Name:       "Shop",
+				ThemeColor: "#00ff00",
+				Icon: Icon{
+					Default: "/web/shop.png",
+				},
+			},
+		},
+	}
+
+	serve := func(host, path string) string {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if host != "" {
+			r.Host = host
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	t.Run("default manifest", func(t *testing.T) {
+		body := serve("", "/manifest.webmanifest")
+		require.Contains(t, body, `"name": "Default"`)
+		require.Contains(t, body, `"theme_color": "#000000"`)
+		require.Contains(t, body, `"scope": "/"`)
+	})
+
+	t.Run("host variant manifest", func(t *testing.T) {
+		body := serve("acme.go-app.dev:8080", "/manifest.webmanifest")
+		require.Contains(t, body, `"name": "Acme"`)
+		require.Contains(t, body, `"short_name": "Acme"`)
+		require.Contains(t, body, `"src": "/web/large.png"`)
+		require.Contains(t, body, `"src": "/web/maskable.png"`)
+		require.Contains(t, body, `"theme_color": "#ff0000"`)
+		require.Contains(t, body, `"scope": "/"`)
+	})
+
+	t.Run("path prefix variant manifest", func(t *testing.T) {
+		body := serve("", "/shop/manifest.webmanifest")
+		require.Contains(t, body, `"name": "Shop"`)
+		require.Contains(t, body, `"src": "/web/shop.png"`)
+		require.NotContains(t, body, `"src": "/web/large.png"`)
+		require.NotContains(t, body, `"src": "/web/maskable.png"`)
+		require.NotContains(t, body, `image/svg+xml`)
+		require.Equal(t, 3, strings.Count(body, `"src": "/web/shop.png"`))
+		require.Contains(t, body, `"theme_color": "#00ff00"`)
+		require.Contains(t, body, `"scope": "/shop/"`)
+		require.Contains(t, body, `"start_url": "/shop/"`)
+	})
+
+	t.Run("path prefix variant app.js", func(t *testing.T) {
+		body := serve("", "/shop/app.js")
+		require.Contains(t, body, `"/shop/app-worker.js"`)
+		require.Contains(t, body, `"GOAPP_ROOT_PREFIX":"/shop"`)
+	})
+
+	t.Run("path prefix variant app-worker.js", func(t *testing.T) {
+		body := serve("", "/shop/app-worker.js")
+		require.Contains(t, body, `const cacheScope = "shop";`)
+		require.Contains(t, body, `"/shop/app.js"`)
+		require.Contains(t, body, `"/shop/manifest.webmanifest"`)
+		require.Contains(t, body, `"/web/shop.png"`)
+
+		body = serve("", "/app-worker.js")
+		require.Contains(t, body, `const cacheScope = "";`)
+	})
+
+	t.Run("path prefix variant page", func(t *testing.T) {
+		body := serve("", "/shop/")
+		require.Contains(t, body, `<div id="pre-render-ok">`)
+		require.Contains(t, body, `href="/shop/manifest.webmanifest"`)
+		require.Contains(t, body, `src="/shop/app.js"`)
+		require.Contains(t, body, `content="#00ff00"`)
+	})
+
+	t.Run("path prefix variant unknown route", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/shop/unknown", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHostname(t *testing.T) {
+	require.Equal(t, "go-app.dev", hostname("go-app.dev"))
+	require.Equal(t, "go-app.dev", hostname("go-app.dev:8080"))
+	require.Equal(t, "[::1]", hostname("[::1]"))
+	require.Equal(t, "[::1]", hostname("[::1]:8080"))
+}
@@ -0,0 +1,497 @@
+package app
+
+import (
+	"context"
+	"sync"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// WorkerMessage represents a message exchanged between a page and a Web
+// Worker.
+type WorkerMessage struct {
+	// The name of the message.
+	Name string
+
+	// The JavaScript value carried by the message.
+	Value Value
+}
+
+// Decode stores the value carried by the message into the Go value pointed to
+// by v. See UnmarshalJS for the conversion rules.
+func (m WorkerMessage) Decode(v any) error {
+	return UnmarshalJS(m.Value, v)
+}
+
+// WorkerHandler defines a callback executed in a Web Worker when a message is
+// posted to it with Worker.Post or Worker.Call. The returned value, or error,
+// is sent back as the result of Worker.Call.
+type WorkerHandler func(WorkerContext, WorkerMessage) (any, error)
+
+// HandleWorker registers the handler for messages with the given name posted
+// to Web Workers. Handlers are registered in the main function, before
+// RunWhenOnBrowser is called, and are executed on their own goroutine.
+//
+// The app WebAssembly program is loaded in a Web Worker started with
+// Context.NewWorker. RunWhenOnBrowser then serves messages posted to the
+// worker instead of displaying components.
+func HandleWorker(name string, h WorkerHandler) {
+	workerHandlersMutex.Lock()
+	defer workerHandlersMutex.Unlock()
+	workerHandlers[name] = h
+}
+
+var (
+	workerHandlersMutex sync.RWMutex
+	workerHandlers      = make(map[string]WorkerHandler)
+)
+
+func workerHandler(name string) (WorkerHandler, bool) {
+	workerHandlersMutex.RLock()
+	defer workerHandlersMutex.RUnlock()
+	h, ok := workerHandlers[name]
+	return h, ok
+}
+
+// WorkerContext represents the environment of a message handled in a Web
+// Worker. For messages sent with Worker.Call, it is canceled when the context
+// of the call is done.
+type WorkerContext struct {
+	context.Context
+
+	name string
+	post func(Value)
+}
+
+// Name returns the name the worker has been started with.
+func (ctx WorkerContext) Name() string {
+	return ctx.name
+}
+
+// Post sends a message with the given name and value to the page that started
+// the worker, where it is posted as an action. The value is converted with
+// MarshalJS.
+func (ctx WorkerContext) Post(name string, v any) error {
+	value, err := MarshalJS(v)
+	if err != nil {
+		return errors.New("posting message from web worker failed").
+			WithTag("worker", ctx.name).
+			WithTag("message", name).
+			Wrap(err)
+	}
+
+	ctx.post(workerMessage("message", 0, name, value, nil))
+	return nil
+}
+
+// Worker is a Web Worker that runs the app WebAssembly program off the main
+// thread, where heavy computations do not freeze the UI.
+//
+// Messages the worker posts with WorkerContext.Post are posted as actions on
+// the page, with a WorkerMessage as value and the worker name as "worker" tag.
+type Worker struct {
+	name      string
+	ctx       Context
+	post      func(Value)
+	terminate func()
+
+	mutex  sync.Mutex
+	err    error
+	lastID int
+	calls  map[int]chan workerReply
+}
+
+type workerReply struct {
+	value Value
+	err   error
+}
+
+// NewWorker starts a Web Worker with the given name and returns it. The worker
+// loads the app WebAssembly program, whose messages are handled by the
+// handlers registered with HandleWorker.
+//
+// Posting messages to the worker returns an error when Web Workers are not
+// supported, such as on the server.
+func (ctx Context) NewWorker(name string) *Worker {
+	w := &Worker{
+		name: name,
+		ctx:  ctx,
+	}
+
+	constructor := ctx.currentWindow().Get("Worker")
+	if IsServer || !constructor.Truthy() {
+		w.err = errors.New("web workers are not supported").WithTag("worker", name)
+		return w
+	}
+
+	jsWorker := constructor.New(Getenv("GOAPP_WEBWORKER_URL"), map[string]any{
+		"name": name,
+	})
+
+	onMessage := FuncOf(func(this Value, args []Value) any {
+		w.receive(args[0].Get("data"))
+		return nil
+	})
+	jsWorker.Call("addEventListener", "message", onMessage)
+
+	onError := FuncOf(func(this Value, args []Value) any {
+		err := errors.New("web worker failed").WithTag("worker", name)
+		if jsErr := args[0].Get("error"); jsErr.Truthy() {
+			err = err.Wrap(ErrorOf(jsErr))
+		} else if message := args[0].Get("message"); message.Type() == TypeString {
+			err = err.WithTag("reason", message.String())
+		}
+		w.fail(err)
+		return nil
+	})
+	jsWorker.Call("addEventListener", "error", onError)
+
+	w.post = func(msg Value) {
+		jsWorker.Call("postMessage", msg)
+	}
+	w.terminate = func() {
+		jsWorker.Call("terminate")
+		onMessage.Release()
+		onError.Release()
+	}
+	return w
+}
+
+// Name returns the name of the worker.
+func (w *Worker) Name() string {
+	return w.name
+}
+
+// Post sends a message with the given name and value to the worker, without
+// waiting for it to be handled. The value is converted with MarshalJS.
+func (w *Worker) Post(name string, v any) error {
+	return w.send(0, name, v)
+}
+
+// Call sends a message with the given name and value to the worker, waits for
+// its handler to return, and stores the returned value into the Go value
+// pointed to by res, which can be nil. The error returned by the handler is
+// returned as the cause of the returned error.
+//
+// Call blocks until the handler returns or the context is done, and is meant
+// to be called within Context.Async. When the context is done, the context of
+// the handler is canceled.
+func (w *Worker) Call(ctx context.Context, name string, v any, res any) error {
+	if err := ctx.Err(); err != nil {
+		return errors.New("calling web worker canceled").
+			WithTag("worker", w.name).
+			WithTag("message", name).
+			Wrap(err)
+	}
+
+	reply := make(chan workerReply, 1)
+
+	w.mutex.Lock()
+	w.lastID++
+	id := w.lastID
+	if w.calls == nil {
+		w.calls = make(map[int]chan workerReply)
+	}
+	w.calls[id] = reply
+	w.mutex.Unlock()
+
+	defer func() {
+		w.mutex.Lock()
+		delete(w.calls, id)
+		w.mutex.Unlock()
+	}()
+
+	if err := w.send(id, name, v); err != nil {
+		return err
+	}
+
+	select {
+	case r := <-reply:
+		if r.err != nil {
+			return errors.New("calling web worker failed").
+				WithTag("worker", w.name).
+				WithTag("message", name).
+				Wrap(r.err)
+		}
+		if res == nil {
+			return nil
+		}
+		return UnmarshalJS(r.value, res)
+
+	case <-ctx.Done():
+		w.cancel(id, name)
+		return errors.New("calling web worker canceled").
+			WithTag("worker", w.name).
+			WithTag("message", name).
+			Wrap(ctx.Err())
+	}
+}
+
+// Terminate immediately stops the worker. Pending calls return an error.
+func (w *Worker) Terminate() {
+	w.fail(errors.New("web worker terminated").WithTag("worker", w.name))
+
+	w.mutex.Lock()
+	terminate := w.terminate
+	w.terminate = nil
+	w.mutex.Unlock()
+
+	if terminate != nil {
+		terminate()
+	}
+}
+
+func (w *Worker) send(id int, name string, v any) error {
+	w.mutex.Lock()
+	err := w.err
+	w.mutex.Unlock()
+	if err != nil {
+		return errors.New("posting message to web worker failed").
+			WithTag("message", name).
+			Wrap(err)
+	}
+
+	value, err := MarshalJS(v)
+	if err != nil {
+		return errors.New("posting message to web worker failed").
+			WithTag("worker", w.name).
+			WithTag("message", name).
+			Wrap(err)
+	}
+
+	w.post(workerMessage("message", id, name, value, nil))
+	return nil
+}
+
+// cancel notifies the worker that the call with the given id is canceled.
+func (w *Worker) cancel(id int, name string) {
+	w.mutex.Lock()
+	err := w.err
+	w.mutex.Unlock()
+	if err == nil {
+		w.post(workerMessage("cancel", id, name, nil, nil))
+	}
+}
+
+func (w *Worker) receive(msg Value) {
+	msg = msg.Get("goapp")
+	if msg.Type() != TypeObject {
+		return
+	}
+
+	switch msg.Get("type").String() {
+	case "message":
+		w.ctx.NewActionWithValue(msg.Get("name").String(), WorkerMessage{
+			Name:  msg.Get("name").String(),
+			Value: msg.Get("value"),
+		}, T("worker", w.name))
+
+	case "reply":
+		var r workerReply
+		if jsErr := msg.Get("error"); jsErr.Truthy() {
+			r.err = ErrorOf(jsErr)
+		} else {
+			r.value = msg.Get("value")
+		}
+
+		w.mutex.Lock()
+		reply, ok := w.calls[msg.Get("id").Int()]
+		w.mutex.Unlock()
+		if ok {
+			select {
+			case reply <- r:
+			default:
+			}
+		}
+	}
+}
+
+// fail makes pending and future calls return the given error.
+func (w *Worker) fail(err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.err == nil {
+		w.err = err
+	}
+	for id, reply := range w.calls {
+		select {
+		case reply <- workerReply{err: err}:
+		default:
+		}
+		delete(w.calls, id)
+	}
+}
+
+func isWebWorker() bool {
+	return Window().Get("goappOnWorkerMessage").Truthy() &&
+		!Window().Get("document").Truthy()
+}
+
+// runWebWorker serves the messages posted to the Web Worker the program is
+// running in. It never returns.
+func runWebWorker() {
+	s := newWorkerServer(Window().Get("name").String(), func(msg Value) {
+		Window().Call("postMessage", msg)
+	})
+
+	onMessage := FuncOf(func(this Value, args []Value) any {
+		if len(args) != 0 {
+			s.receive(args[0])
+		}
+		return nil
+	})
+	Window().Set("goappOnWorkerMessage", onMessage)
+
+	if messages := Window().Get("goappWorkerMessages"); messages.Truthy() {
+		for i := 0; i < messages.Length(); i++ {
+			s.receive(messages.Index(i))
+		}
+		messages.Call("splice", 0)
+	}
+
+	select {}
+}
+
+// workerServer executes the handlers of the messages posted to a Web Worker.
+type workerServer struct {
+	name string
+	post func(Value)
+
+	mutex    sync.Mutex
+	closed   bool
+	cancels  map[int]func()
+	handlers sync.WaitGroup
+}
+
+func newWorkerServer(name string, post func(Value)) *workerServer {
+	return &workerServer{
+		name:    name,
+		post:    post,
+		cancels: make(map[int]func()),
+	}
+}
+
+// receive executes the handler of the given message on a new goroutine, or
+// cancels the context of the handler of the call it cancels.
+func (s *workerServer) receive(msg Value) {
+	msg = msg.Get("goapp")
+	if msg.Type() != TypeObject {
+		return
+	}
+
+	id := msg.Get("id").Int()
+	switch msg.Get("type").String() {
+	case "message":
+		ctx, cancel := context.WithCancel(context.Background())
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			cancel()
+			return
+		}
+		if id != 0 {
+			s.cancels[id] = cancel
+		}
+		s.handlers.Add(1)
+		s.mutex.Unlock()
+
+		go func() {
+			defer s.handlers.Done()
+			defer s.done(id, cancel)
+			s.serve(ctx, id, msg)
+		}()
+
+	case "cancel":
+		s.mutex.Lock()
+		cancel, ok := s.cancels[id]
+		s.mutex.Unlock()
+		if ok {
+			cancel()
+		}
+	}
+}
+
+func (s *workerServer) done(id int, cancel func()) {
+	cancel()
+	if id != 0 {
+		s.mutex.Lock()
+		delete(s.cancels, id)
+		s.mutex.Unlock()
+	}
+}
+
+// close cancels the contexts of the running handlers and waits for them to
+// return. Messages received afterward are ignored.
+func (s *workerServer) close() {
+	s.mutex.Lock()
+	s.closed = true
+	for _, cancel := range s.cancels {
+		cancel()
+	}
+	s.mutex.Unlock()
+
+	s.handlers.Wait()
+}
+
+// serve executes the handler of the given message and posts its result when a
+// reply is expected.
+func (s *workerServer) serve(ctx context.Context, id int, msg Value) {
+	messageName := msg.Get("name").String()
+	reply := func(v any, err error) {
+		if id == 0 {
+			if err != nil {
+				Log(err)
+			}
+			return
+		}
+
+		var value Value
+		if err == nil {
+			value, err = MarshalJS(v)
+		}
+		s.post(workerMessage("reply", id, messageName, value, err))
+	}
+
+	h, ok := workerHandler(messageName)
+	if !ok {
+		reply(nil, errors.New("web worker message is not handled").
+			WithTag("worker", s.name).
+			WithTag("message", messageName))
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			reply(nil, errors.New("web worker handler panicked").
+				WithTag("worker", s.name).
+				WithTag("message", messageName).
+				WithTag("panic", r))
+		}
+	}()
+
+	reply(h(WorkerContext{
+		Context: ctx,
+		name:    s.name,
+		post:    s.post,
+	}, WorkerMessage{
+		Name:  messageName,
+		Value: msg.Get("value"),
+	}))
+}
+
+// workerMessage returns a message that can be posted between a page and a Web
+// Worker. Errors are converted to plain objects since the properties of Error
+// objects other than name and message are not cloned.
+func workerMessage(typ string, id int, name string, v Value, err error) Value {
+	msg := map[string]any{
+		"type":  typ,
+		"id":    id,
+		"name":  name,
+		"value": v,
+	}
+	if err != nil {
+		msg["error"] = jsErrorProperties(err)
+	}
+	return ValueOf(map[string]any{"goapp": msg})
+}
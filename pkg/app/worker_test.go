@@ -0,0 +1,181 @@
+//go:build !wasm && fakedom
+// +build !wasm,fakedom
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// newTestWorker returns a worker that serves its messages in the current
+// program instead of a Web Worker. Its handlers are waited for at the end of
+// the test.
+func newTestWorker(t *testing.T, ctx Context, name string) *Worker {
+	w := &Worker{
+		name: name,
+		ctx:  ctx,
+	}
+	s := newWorkerServer(name, w.receive)
+	w.post = s.receive
+	w.terminate = s.close
+	t.Cleanup(w.Terminate)
+	return w
+}
+
+func TestWorker(t *testing.T) {
+	type square struct {
+		N int `js:"n"`
+	}
+
+	started := make(chan struct{}, 1)
+	canceled := make(chan error, 1)
+	handlers := map[string]WorkerHandler{
+		"/worker-test/square": func(ctx WorkerContext, m WorkerMessage) (any, error) {
+			var in square
+			if err := m.Decode(&in); err != nil {
+				return nil, err
+			}
+			if err := ctx.Post("/worker-test/progress", map[string]any{"worker": ctx.Name()}); err != nil {
+				return nil, err
+			}
+			return square{N: in.N * in.N}, nil
+		},
+		"/worker-test/error": func(ctx WorkerContext, m WorkerMessage) (any, error) {
+			return nil, errors.New("computing failed").WithType("compute").WithTag("n", 42)
+		},
+		"/worker-test/panic": func(ctx WorkerContext, m WorkerMessage) (any, error) {
+			panic("boom")
+		},
+		"/worker-test/block": func(ctx WorkerContext, m WorkerMessage) (any, error) {
+			started <- struct{}{}
+			<-ctx.Done()
+			canceled <- ctx.Err()
+			return nil, ctx.Err()
+		},
+	}
+	for name, h := range handlers {
+		HandleWorker(name, h)
+	}
+	defer func() {
+		workerHandlersMutex.Lock()
+		defer workerHandlersMutex.Unlock()
+		for name := range handlers {
+			delete(workerHandlers, name)
+		}
+	}()
+
+	e := newTestEngine()
+	ctx := context.Background()
+
+	t.Run("call returns the handler result", func(t *testing.T) {
+		w := newTestWorker(t, e.baseContext(), "compute")
+
+		var res square
+		err := w.Call(ctx, "/worker-test/square", square{N: 3}, &res)
+		require.NoError(t, err)
+		require.Equal(t, 9, res.N)
+	})
+
+	t.Run("message posted by the worker is posted as an action", func(t *testing.T) {
+		w := newTestWorker(t, e.baseContext(), "compute")
+
+		err := w.Call(ctx, "/worker-test/square", square{N: 2}, nil)
+		require.NoError(t, err)
+
+		var action Action
+		for _, a := range e.Actions() {
+			if a.Name == "/worker-test/progress" {
+				action = a
+			}
+		}
+		require.Equal(t, "compute", action.Tags.Get("worker"))
+
+		var progress map[string]string
+		err = action.Value.(WorkerMessage).Decode(&progress)
+		require.NoError(t, err)
+		require.Equal(t, "compute", progress["worker"])
+	})
+
+	t.Run("call returns the handler error", func(t *testing.T) {
+		w := newTestWorker(t, e.baseContext(), "compute")
+
+		err := w.Call(ctx, "/worker-test/error", nil, nil)
+		require.Error(t, err)
+		require.True(t, errors.HasType(err, "compute"))
+		require.Equal(t, float64(42), errors.Tag(err, "n"))
+		t.Log(err)
+	})
+
+	t.Run("call returns an error when the handler panics", func(t *testing.T) {
+		w := newTestWorker(t, e.baseContext(), "compute")
+
+		err := w.Call(ctx, "/worker-test/panic", nil, nil)
+		require.Error(t, err)
+		require.Equal(t, "boom", errors.Tag(err, "panic"))
+	})
+
+	t.Run("call returns an error when the message is not handled", func(t *testing.T) {
+		w := newTestWorker(t, e.baseContext(), "compute")
+
+		err := w.Call(ctx, "/worker-test/unknown", nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("canceled call returns an error", func(t *testing.T) {
+		w := newTestWorker(t, e.baseContext(), "compute")
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := w.Call(ctx, "/worker-test/block", nil, nil)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.Canceled))
+
+		w.mutex.Lock()
+		lastID := w.lastID
+		w.mutex.Unlock()
+		require.Zero(t, lastID)
+	})
+
+	t.Run("canceling a call cancels the handler context", func(t *testing.T) {
+		w := newTestWorker(t, e.baseContext(), "compute")
+
+		ctx, cancel := context.WithCancel(ctx)
+		errc := make(chan error, 1)
+		go func() {
+			errc <- w.Call(ctx, "/worker-test/block", nil, nil)
+		}()
+		<-started
+
+		cancel()
+		require.True(t, errors.Is(<-errc, context.Canceled))
+		require.Equal(t, context.Canceled, <-canceled)
+	})
+
+	t.Run("terminate stops pending and future calls", func(t *testing.T) {
+		w := newTestWorker(t, e.baseContext(), "compute")
+
+		errc := make(chan error, 1)
+		go func() {
+			errc <- w.Call(ctx, "/worker-test/block", nil, nil)
+		}()
+		<-started
+
+		w.Terminate()
+		require.Error(t, <-errc)
+		require.Equal(t, context.Canceled, <-canceled)
+		require.Error(t, w.Post("/worker-test/square", square{N: 2}))
+	})
+
+	t.Run("post returns an error when web workers are not supported", func(t *testing.T) {
+		w := e.baseContext().NewWorker("compute")
+		require.Equal(t, "compute", w.Name())
+		require.Error(t, w.Post("/worker-test/square", square{N: 2}))
+		require.Error(t, w.Call(ctx, "/worker-test/square", square{N: 2}, nil))
+		w.Terminate()
+	})
+}
@@ -0,0 +1,210 @@
+package form
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+// OnInput returns an event handler that sets the named field from the value of
+// the input that triggered the event. Checkbox inputs set boolean fields from
+// their checked property.
+func (f *Form) OnInput(name string) app.EventHandler {
+	return func(ctx app.Context, e app.Event) {
+		field := f.field(name)
+
+		src := ctx.JSSrc()
+		value := src.Get("value").String()
+		if f.value.Field(field.index).Kind() == reflect.Bool {
+			value = strconv.FormatBool(src.Get("checked").Bool())
+		}
+
+		f.Set(name, value)
+		if field.err == "" && !field.invalid {
+			f.validateAsync(ctx, field)
+		}
+	}
+}
+
+// OnBlur returns an event handler that marks the named field as touched.
+func (f *Form) OnBlur(name string) app.EventHandler {
+	return func(ctx app.Context, e app.Event) {
+		f.Touch(name)
+	}
+}
+
+// OnSubmit returns an event handler for the submit event of a form element. It
+// prevents the browser from submitting the form, validates the form, and calls
+// the given function when the form is valid, once the asynchronous validators
+// are done. Fields edited while the submission waits for asynchronous
+// validators are validated again before the function is called, and the
+// submission is dropped when the form is no longer valid.
+func (f *Form) OnSubmit(fn func(ctx app.Context)) app.EventHandler {
+	return func(ctx app.Context, e app.Event) {
+		e.PreventDefault()
+		f.submit(ctx, fn)
+	}
+}
+
+func (f *Form) submit(ctx app.Context, fn func(ctx app.Context)) {
+	f.submitted = true
+	f.submission = fn
+	for _, field := range f.fields {
+		if !field.pending {
+			field.validated = false
+		}
+	}
+	f.settleSubmission(ctx)
+}
+
+// settleSubmission calls the pending submission function once the
+// asynchronous validators of all the fields are done for their current values.
+// Fields that are not validated yet, e.g. because they were edited during the
+// submission, are validated first.
+func (f *Form) settleSubmission(ctx app.Context) {
+	if f.submission == nil || f.Pending() {
+		return
+	}
+	if !f.Valid() {
+		f.submission = nil
+		return
+	}
+
+	for _, field := range f.fields {
+		if len(field.asyncValidators) != 0 && !field.validated {
+			f.validateAsync(ctx, field)
+		}
+	}
+	if f.Pending() {
+		return
+	}
+
+	fn := f.submission
+	f.submission = nil
+	fn(ctx)
+}
+
+// Input returns an input element bound to the named field. Its name, value and
+// type are set from the field, as well as the constraint attributes of its
+// validation rules, so that the form is validated by the browser when it is
+// submitted before the app is loaded.
+func (f *Form) Input(name string) app.HTMLInput {
+	field := f.field(name)
+	kind := f.value.Field(field.index).Kind()
+
+	input := app.Input().
+		Name(name).
+		OnInput(f.OnInput(name)).
+		OnBlur(f.OnBlur(name))
+
+	switch {
+	case kind == reflect.Bool:
+		input = input.
+			Type("checkbox").
+			Checked(f.value.Field(field.index).Bool())
+
+	case kind != reflect.String:
+		input = input.
+			Type("number").
+			Value(field.value)
+
+	case field.hasRule("email"):
+		input = input.
+			Type("email").
+			Value(field.value)
+
+	default:
+		input = input.
+			Type("text").
+			Value(field.value)
+	}
+
+	for _, r := range field.rules {
+		switch {
+		case r.name == "required":
+			input = input.Required(true)
+
+		case r.name == "min" && kind == reflect.String:
+			input = input.Attr("minlength", r.value)
+
+		case r.name == "max" && kind == reflect.String:
+			input = input.Attr("maxlength", r.value)
+
+		case r.name == "min":
+			input = input.Min(r.value)
+
+		case r.name == "max":
+			input = input.Max(r.value)
+
+		case r.name == "pattern":
+			input = input.Attr("pattern", r.value)
+		}
+	}
+
+	if f.Error(name) != "" {
+		input = input.Aria("invalid", true)
+	}
+	return input
+}
+
+// Textarea returns a textarea element bound to the named field, with the
+// constraint attributes of its validation rules.
+func (f *Form) Textarea(name string) app.HTMLTextarea {
+	field := f.field(name)
+
+	textarea := app.Textarea().
+		Name(name).
+		Text(field.value).
+		OnInput(f.OnInput(name)).
+		OnBlur(f.OnBlur(name))
+
+	for _, r := range field.rules {
+		switch r.name {
+		case "required":
+			textarea = textarea.Required(true)
+
+		case "min":
+			textarea = textarea.Attr("minlength", r.value)
+
+		case "max":
+			textarea = textarea.Attr("maxlength", r.value)
+		}
+	}
+
+	if f.Error(name) != "" {
+		textarea = textarea.Aria("invalid", true)
+	}
+	return textarea
+}
+
+// ErrorText returns an element that displays the error of the named field, or
+// nothing when the field has no error to report. The element has the
+// "goapp-form-error" class and is announced by screen readers.
+func (f *Form) ErrorText(name string) app.UI {
+	return errorText(f.Error(name))
+}
+
+// FormErrorText returns an element that displays the error reported by form
+// validators, or nothing when there is no error to report.
+func (f *Form) FormErrorText() app.UI {
+	return errorText(f.FormError())
+}
+
+func errorText(err string) app.UI {
+	return app.If(err != "", func() app.UI {
+		return app.Span().
+			Class("goapp-form-error").
+			Aria("live", "polite").
+			Text(err)
+	})
+}
+
+func (f *field) hasRule(name string) bool {
+	for _, r := range f.rules {
+		if r.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package form
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+func TestFormInput(t *testing.T) {
+	s := signup{Username: "max"}
+	f := newSignupForm(&s)
+
+	html := app.HTMLString(f.Input("username"))
+	require.Contains(t, html, `name="username"`)
+	require.Contains(t, html, `type="text"`)
+	require.Contains(t, html, `value="max"`)
+	require.Contains(t, html, `required`)
+	require.Contains(t, html, `minlength="3"`)
+	require.Contains(t, html, `maxlength="12"`)
+	require.Contains(t, html, `pattern="^[a-z0-9_]+$"`)
+	require.NotContains(t, html, `aria-invalid`)
+
+	html = app.HTMLString(f.Input("email"))
+	require.Contains(t, html, `type="email"`)
+
+	html = app.HTMLString(f.Input("age"))
+	require.Contains(t, html, `type="number"`)
+	require.Contains(t, html, `min="18"`)
+	require.Contains(t, html, `max="130"`)
+
+	html = app.HTMLString(f.Input("terms"))
+	require.Contains(t, html, `type="checkbox"`)
+
+	f.Set("username", "")
+	f.Touch("username")
+	html = app.HTMLString(f.Input("username"))
+	require.Contains(t, html, `aria-invalid="true"`)
+}
+
+func TestFormTextarea(t *testing.T) {
+	var v struct {
+		Bio string `form:"bio" validate:"required,max=140"`
+	}
+	v.Bio = "hello"
+	f := New(&v)
+
+	html := app.HTMLString(f.Textarea("bio"))
+	require.Contains(t, html, `name="bio"`)
+	require.Contains(t, html, `maxlength="140"`)
+	require.Contains(t, html, `hello`)
+}
+
+func TestFormErrorText(t *testing.T) {
+	var s signup
+	f := newSignupForm(&s)
+	require.Equal(t, "<div></div>", app.HTMLString(app.Div().Body(f.ErrorText("email"))))
+
+	f.Touch("email")
+	f.Valid()
+	html := app.HTMLString(app.Div().Body(f.ErrorText("email")))
+	require.Contains(t, html, `class="goapp-form-error"`)
+	require.Contains(t, html, Messages.Required)
+
+	f.Set("email", "max@go-app.dev")
+	f.Set("username", "max")
+	f.Set("password", "a")
+	f.Set("confirm", "b")
+	f.Set("terms", "true")
+	f.Valid()
+	require.Equal(t, "<div></div>", app.HTMLString(app.Div().Body(f.FormErrorText())))
+
+	f.submitted = true
+	require.Contains(t, app.HTMLString(app.Div().Body(f.FormErrorText())), "The passwords do not match.")
+}
+
+type submitTestCompo struct {
+	app.Compo
+
+	ctx app.Context
+}
+
+func (c *submitTestCompo) OnPreRender(ctx app.Context) {
+	c.ctx = ctx
+}
+
+func (c *submitTestCompo) OnMount(ctx app.Context) {
+	c.ctx = ctx
+}
+
+func (c *submitTestCompo) Render() app.UI {
+	return app.Div()
+}
+
+func newSubmitTestContext(t *testing.T) (app.TestEngine, app.Context) {
+	compo := &submitTestCompo{}
+	e := app.NewTestEngine()
+	require.NoError(t, e.Load(compo))
+	e.ConsumeAll()
+	return e, compo.ctx
+}
+
+func TestFormSubmit(t *testing.T) {
+	t.Run("pending before any edit", func(t *testing.T) {
+		e, ctx := newSubmitTestContext(t)
+
+		var v struct {
+			Name string `form:"name"`
+		}
+		f := New(&v).ValidateAsync("name", func(any) error { return nil })
+
+		f.validateAsync(ctx, f.field("name"))
+		require.True(t, f.Pending())
+
+		e.ConsumeAll()
+		require.False(t, f.Pending())
+	})
+
+	t.Run("field edited during validation is validated again", func(t *testing.T) {
+		e, ctx := newSubmitTestContext(t)
+
+		var v struct {
+			Name string `form:"name" validate:"required"`
+		}
+		v.Name = "max"
+
+		var mu sync.Mutex
+		var validated []any
+		f := New(&v).ValidateAsync("name", func(name any) error {
+			mu.Lock()
+			defer mu.Unlock()
+			validated = append(validated, name)
+			return nil
+		})
+
+		var submitted []string
+		f.submit(ctx, func(ctx app.Context) {
+			submitted = append(submitted, v.Name)
+		})
+		require.True(t, f.Pending())
+
+		f.Set("name", "maxence")
+		e.ConsumeAll()
+		require.False(t, f.Pending())
+		require.Equal(t, []string{"maxence"}, submitted)
+		require.Equal(t, []any{"max", "maxence"}, validated)
+	})
+
+	t.Run("field made invalid during validation drops the submission", func(t *testing.T) {
+		e, ctx := newSubmitTestContext(t)
+
+		var v struct {
+			Name string `form:"name" validate:"required"`
+		}
+		v.Name = "max"
+		f := New(&v).ValidateAsync("name", func(any) error { return nil })
+
+		submitted := false
+		f.submit(ctx, func(ctx app.Context) {
+			submitted = true
+		})
+
+		f.Set("name", "")
+		e.ConsumeAll()
+		require.False(t, submitted)
+		require.Equal(t, Messages.Required, f.Error("name"))
+	})
+
+	t.Run("asynchronous error", func(t *testing.T) {
+		e, ctx := newSubmitTestContext(t)
+
+		var v struct {
+			Name string `form:"name"`
+		}
+		f := New(&v).ValidateAsync("name", func(any) error {
+			return errors.New("This name is taken.")
+		})
+
+		submitted := false
+		f.submit(ctx, func(ctx app.Context) {
+			submitted = true
+		})
+		e.ConsumeAll()
+		require.False(t, submitted)
+		require.Equal(t, "This name is taken.", f.Error("name"))
+	})
+}
@@ -0,0 +1,53 @@
+package form
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// Decode sets the fields of the form from the values of the given request, and
+// validates them with all the validators, including the asynchronous ones that
+// are called on the current goroutine. The form is marked as submitted.
+//
+// It is used on the server to handle forms submitted by the browser before the
+// app is loaded, since the inputs returned by Input and Textarea are named after
+// their field. The validation result is then reported by Valid and Errors. An
+// error is returned when the request values cannot be parsed.
+func (f *Form) Decode(r *http.Request) error {
+	if err := r.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+		return errors.New("parsing form values failed").Wrap(err)
+	}
+
+	f.submitted = true
+	for _, field := range f.fields {
+		value, ok := r.Form[field.name]
+		switch {
+		case ok && len(value) != 0:
+			f.Set(field.name, value[0])
+
+		case f.value.Field(field.index).Kind() == reflect.Bool:
+			f.Set(field.name, strconv.FormatBool(false))
+
+		default:
+			f.Set(field.name, "")
+		}
+	}
+
+	if !f.Valid() {
+		return nil
+	}
+
+	for _, field := range f.fields {
+		value := f.value.Field(field.index).Interface()
+		for _, validate := range field.asyncValidators {
+			if err := validate(value); err != nil {
+				field.asyncErr = err.Error()
+				break
+			}
+		}
+	}
+	return nil
+}
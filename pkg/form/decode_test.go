@@ -0,0 +1,86 @@
+//go:build !wasm
+// +build !wasm
+
+package form
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormDecode(t *testing.T) {
+	newRequest := func(values url.Values) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(values.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return r
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		var s signup
+		f := newSignupForm(&s)
+
+		err := f.Decode(newRequest(url.Values{
+			"email":    {"max@go-app.dev"},
+			"username": {"max"},
+			"age":      {"42"},
+			"password": {"secret"},
+			"confirm":  {"secret"},
+			"terms":    {"on"},
+		}))
+		require.NoError(t, err)
+		require.True(t, f.Valid())
+		require.True(t, f.Submitted())
+		require.Equal(t, signup{
+			Email:    "max@go-app.dev",
+			Username: "max",
+			Age:      42,
+			Password: "secret",
+			Confirm:  "secret",
+			Terms:    true,
+		}, s)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		s := signup{Terms: true}
+		f := newSignupForm(&s)
+
+		err := f.Decode(newRequest(url.Values{
+			"email": {"max"},
+			"age":   {"12"},
+		}))
+		require.NoError(t, err)
+		require.False(t, f.Valid())
+		require.False(t, s.Terms)
+		require.Equal(t, Messages.Email, f.Error("email"))
+		require.Equal(t, Messages.Required, f.Error("username"))
+		require.Equal(t, Messages.Required, f.Error("terms"))
+		require.NotEmpty(t, f.Error("age"))
+	})
+
+	t.Run("async validators", func(t *testing.T) {
+		var s signup
+		f := newSignupForm(&s).ValidateAsync("username", func(v any) error {
+			if v == "taken" {
+				return errors.New("This username is taken.")
+			}
+			return nil
+		})
+
+		err := f.Decode(newRequest(url.Values{
+			"email":    {"max@go-app.dev"},
+			"username": {"taken"},
+			"password": {"secret"},
+			"confirm":  {"secret"},
+			"terms":    {"on"},
+		}))
+		require.NoError(t, err)
+		require.False(t, f.Valid())
+		require.Equal(t, Errors{"username": "This username is taken."}, f.Errors())
+	})
+}
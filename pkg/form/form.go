@@ -0,0 +1,368 @@
+// Package form binds Go structs to HTML forms and validates them.
+//
+// Struct fields are bound to inputs by name. The name is given by the form tag
+// or defaults to the field name, and fields tagged with form:"-" or not
+// exported are ignored. Validation rules are given by the validate tag, e.g.:
+//
+//	type signup struct {
+//	    Email    string `form:"email" validate:"required,email"`
+//	    Username string `form:"username" validate:"required,min=3,pattern=^[a-z0-9_]+$"`
+//	    Age      int    `form:"age" validate:"min=18"`
+//	}
+//
+// Supported field types are strings, booleans, integers, unsigned integers and
+// floats.
+package form
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// Errors maps field names to validation error messages. The error reported by
+// form validators is stored under the empty name.
+type Errors map[string]string
+
+// Form binds a struct to form inputs and tracks its validation state. A form is
+// typically created once per component, e.g. in OnInit, and bound to inputs in
+// the Render method.
+type Form struct {
+	value          reflect.Value
+	fields         []*field
+	formValidators []func() error
+	formError      string
+	submitted      bool
+	submission     func(ctx app.Context)
+}
+
+type field struct {
+	name            string
+	index           int
+	rules           []rule
+	validators      []func(any) error
+	asyncValidators []func(any) error
+
+	value      string
+	initial    string
+	err        string
+	asyncErr   string
+	invalid    bool
+	touched    bool
+	validation int
+	pending    bool
+	validated  bool
+}
+
+func (f *field) error() string {
+	if f.invalid {
+		return Messages.Type
+	}
+	if f.err != "" {
+		return f.err
+	}
+	return f.asyncErr
+}
+
+// New creates a form bound to the given struct. It panics if v is not a pointer
+// to a struct.
+func New(v any) *Form {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		panic(errors.New("form value is not a pointer to a struct").
+			WithTag("type", reflect.TypeOf(v)))
+	}
+	value = value.Elem()
+
+	f := &Form{value: value}
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		name, ok := fieldName(structField)
+		if !ok {
+			continue
+		}
+
+		rules, err := parseRules(structField.Tag.Get("validate"))
+		if err != nil {
+			panic(errors.New("parsing validation rules failed").
+				WithTag("field", structField.Name).
+				Wrap(err))
+		}
+
+		s := formatValue(value.Field(i))
+		f.fields = append(f.fields, &field{
+			name:    name,
+			index:   i,
+			rules:   rules,
+			value:   s,
+			initial: s,
+		})
+	}
+	return f
+}
+
+func fieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(f.Tag.Get("form"), ",")
+	switch name {
+	case "-":
+		return "", false
+
+	case "":
+		return f.Name, true
+
+	default:
+		return name, true
+	}
+}
+
+// Validate adds a validator to the named field. The validator is called with
+// the field value after the rules of the validate tag pass, and the message of
+// the returned error is reported as the field error.
+func (f *Form) Validate(name string, fn func(v any) error) *Form {
+	field := f.field(name)
+	field.validators = append(field.validators, fn)
+	return f
+}
+
+// ValidateAsync adds a validator to the named field that is called on another
+// goroutine, e.g. to check a value with a server. Asynchronous validators are
+// called after the synchronous ones pass, when the field is changed from an
+// input and when the form is submitted.
+func (f *Form) ValidateAsync(name string, fn func(v any) error) *Form {
+	field := f.field(name)
+	field.asyncValidators = append(field.asyncValidators, fn)
+	return f
+}
+
+// ValidateForm adds a validator that checks the form as a whole, e.g. that two
+// fields match. It is called after all the fields are valid.
+func (f *Form) ValidateForm(fn func() error) *Form {
+	f.formValidators = append(f.formValidators, fn)
+	return f
+}
+
+// Get returns the value of the named field as it is displayed in inputs.
+func (f *Form) Get(name string) string {
+	return f.field(name).value
+}
+
+// Set sets the value of the named field from its string representation and
+// validates it.
+func (f *Form) Set(name, value string) {
+	field := f.field(name)
+	field.value = value
+	field.pending = false
+	field.validated = false
+	field.asyncErr = ""
+
+	if field.invalid = setValue(f.value.Field(field.index), value) != nil; field.invalid {
+		return
+	}
+	f.validateField(field)
+}
+
+// Touch marks the named field as touched, so that its error is reported.
+// Fields are touched when their input loses focus.
+func (f *Form) Touch(name string) {
+	f.field(name).touched = true
+}
+
+// Touched reports whether the named field is touched.
+func (f *Form) Touched(name string) bool {
+	return f.field(name).touched
+}
+
+// Dirty reports whether the value of the named field differs from its initial
+// value.
+func (f *Form) Dirty(name string) bool {
+	field := f.field(name)
+	return field.value != field.initial
+}
+
+// IsDirty reports whether the value of any field differs from its initial
+// value.
+func (f *Form) IsDirty() bool {
+	for _, field := range f.fields {
+		if field.value != field.initial {
+			return true
+		}
+	}
+	return false
+}
+
+// Pending reports whether asynchronous validators are running.
+func (f *Form) Pending() bool {
+	for _, field := range f.fields {
+		if field.pending {
+			return true
+		}
+	}
+	return false
+}
+
+// Submitted reports whether the form has been submitted.
+func (f *Form) Submitted() bool {
+	return f.submitted
+}
+
+// Error returns the error of the named field once the field is touched or the
+// form is submitted.
+func (f *Form) Error(name string) string {
+	field := f.field(name)
+	if !field.touched && !f.submitted {
+		return ""
+	}
+	return field.error()
+}
+
+// FormError returns the error reported by form validators once the form is
+// submitted.
+func (f *Form) FormError() string {
+	if !f.submitted {
+		return ""
+	}
+	return f.formError
+}
+
+// Errors returns the current errors of the form, whether the fields are touched
+// or not.
+func (f *Form) Errors() Errors {
+	errs := make(Errors)
+	for _, field := range f.fields {
+		if err := field.error(); err != "" {
+			errs[field.name] = err
+		}
+	}
+	if f.formError != "" {
+		errs[""] = f.formError
+	}
+	return errs
+}
+
+// Valid validates all the fields and the form with the synchronous validators,
+// and reports whether there are no errors and no pending asynchronous
+// validators.
+func (f *Form) Valid() bool {
+	valid := true
+	for _, field := range f.fields {
+		if field.invalid {
+			valid = false
+			continue
+		}
+		if !f.validateField(field) || field.asyncErr != "" {
+			valid = false
+		}
+	}
+
+	f.formError = ""
+	if valid {
+		for _, validate := range f.formValidators {
+			if err := validate(); err != nil {
+				f.formError = err.Error()
+				valid = false
+				break
+			}
+		}
+	}
+	return valid && !f.Pending()
+}
+
+// Reset restores the initial values of the fields and clears the validation
+// state.
+func (f *Form) Reset() {
+	for _, field := range f.fields {
+		setValue(f.value.Field(field.index), field.initial)
+		field.value = field.initial
+		field.err = ""
+		field.asyncErr = ""
+		field.invalid = false
+		field.touched = false
+		field.pending = false
+		field.validated = false
+	}
+	f.formError = ""
+	f.submitted = false
+	f.submission = nil
+}
+
+// Commit marks the current values as the initial ones, typically once they are
+// saved.
+func (f *Form) Commit() {
+	for _, field := range f.fields {
+		field.initial = field.value
+	}
+}
+
+func (f *Form) field(name string) *field {
+	for _, field := range f.fields {
+		if field.name == name {
+			return field
+		}
+	}
+	panic(errors.New("form field not found").
+		WithTag("name", name).
+		WithTag("type", f.value.Type()))
+}
+
+func (f *Form) validateField(field *field) bool {
+	field.err = ""
+	value := f.value.Field(field.index)
+
+	for _, r := range field.rules {
+		if msg := r.check(value, field.value); msg != "" {
+			field.err = msg
+			return false
+		}
+	}
+
+	for _, validate := range field.validators {
+		if err := validate(value.Interface()); err != nil {
+			field.err = err.Error()
+			return false
+		}
+	}
+	return true
+}
+
+// validateAsync calls the asynchronous validators of the given field on
+// another goroutine and reports the result on the UI goroutine. Results for
+// outdated values are discarded. Pending submissions are settled once the
+// result is received.
+func (f *Form) validateAsync(ctx app.Context, field *field) {
+	if len(field.asyncValidators) == 0 {
+		return
+	}
+
+	value := f.value.Field(field.index).Interface()
+	field.validation++
+	validation := field.validation
+	field.pending = true
+	field.validated = false
+	field.asyncErr = ""
+
+	ctx.Async(func() {
+		var err error
+		for _, validate := range field.asyncValidators {
+			if err = validate(value); err != nil {
+				break
+			}
+		}
+
+		ctx.Dispatch(func(ctx app.Context) {
+			if field.pending && field.validation == validation {
+				field.pending = false
+				field.validated = true
+				if err != nil {
+					field.asyncErr = err.Error()
+				}
+			}
+			f.settleSubmission(ctx)
+		})
+	})
+}
@@ -0,0 +1,154 @@
+package form
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type signup struct {
+	Email    string `form:"email" validate:"required,email"`
+	Username string `form:"username" validate:"required,min=3,max=12,pattern=^[a-z0-9_]+$"`
+	Age      int    `form:"age" validate:"min=18,max=130"`
+	Password string `form:"password" validate:"required"`
+	Confirm  string `form:"confirm"`
+	Terms    bool   `form:"terms" validate:"required"`
+	Nickname string
+	Ignored  string `form:"-"`
+	internal string
+}
+
+func newSignupForm(s *signup) *Form {
+	return New(s).ValidateForm(func() error {
+		if s.Password != s.Confirm {
+			return errors.New("The passwords do not match.")
+		}
+		return nil
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("fields", func(t *testing.T) {
+		f := New(&signup{Email: "max@go-app.dev"})
+
+		names := make([]string, len(f.fields))
+		for i, field := range f.fields {
+			names[i] = field.name
+		}
+		require.Equal(t, []string{"email", "username", "age", "password", "confirm", "terms", "Nickname"}, names)
+		require.Equal(t, "max@go-app.dev", f.Get("email"))
+		require.Equal(t, "", f.Get("age"))
+		require.Equal(t, "false", f.Get("terms"))
+	})
+
+	t.Run("non struct pointer panics", func(t *testing.T) {
+		require.Panics(t, func() { New(signup{}) })
+	})
+
+	t.Run("invalid rule panics", func(t *testing.T) {
+		require.Panics(t, func() {
+			New(&struct {
+				Name string `validate:"unknown"`
+			}{})
+		})
+	})
+
+	t.Run("unknown field panics", func(t *testing.T) {
+		require.Panics(t, func() { New(&signup{}).Get("Ignored") })
+	})
+}
+
+func TestFormSet(t *testing.T) {
+	var s signup
+	f := newSignupForm(&s)
+
+	f.Set("username", "max")
+	require.Equal(t, "max", s.Username)
+	require.Equal(t, "max", f.Get("username"))
+	require.Empty(t, f.Errors()["username"])
+
+	f.Set("username", "Max!")
+	require.Equal(t, Messages.Pattern, f.Errors()["username"])
+
+	f.Set("age", "42")
+	require.Equal(t, 42, s.Age)
+
+	f.Set("age", "forty-two")
+	require.Equal(t, Messages.Type, f.Errors()["age"])
+	require.Equal(t, "forty-two", f.Get("age"))
+
+	f.Set("terms", "true")
+	require.True(t, s.Terms)
+}
+
+func TestFormTouchedAndDirty(t *testing.T) {
+	s := signup{Username: "max"}
+	f := newSignupForm(&s)
+	require.False(t, f.IsDirty())
+
+	f.Set("username", "")
+	require.True(t, f.Dirty("username"))
+	require.False(t, f.Dirty("email"))
+	require.True(t, f.IsDirty())
+
+	require.False(t, f.Touched("username"))
+	require.Empty(t, f.Error("username"))
+	require.Equal(t, Messages.Required, f.Errors()["username"])
+
+	f.Touch("username")
+	require.True(t, f.Touched("username"))
+	require.Equal(t, Messages.Required, f.Error("username"))
+
+	f.Set("username", "max")
+	require.False(t, f.Dirty("username"))
+	require.Empty(t, f.Error("username"))
+
+	f.Set("username", "maxence")
+	f.Commit()
+	require.False(t, f.IsDirty())
+
+	f.Set("username", "other")
+	f.Reset()
+	require.Equal(t, "maxence", s.Username)
+	require.False(t, f.Touched("username"))
+	require.False(t, f.IsDirty())
+}
+
+func TestFormValid(t *testing.T) {
+	var s signup
+	f := newSignupForm(&s).Validate("username", func(v any) error {
+		if v == "admin" {
+			return errors.New("This username is reserved.")
+		}
+		return nil
+	})
+
+	require.False(t, f.Valid())
+	require.Equal(t, Errors{
+		"email":    Messages.Required,
+		"username": Messages.Required,
+		"password": Messages.Required,
+		"terms":    Messages.Required,
+	}, f.Errors())
+
+	f.Set("email", "max@go-app.dev")
+	f.Set("username", "admin")
+	f.Set("password", "secret")
+	f.Set("confirm", "secrets")
+	f.Set("terms", "true")
+	require.False(t, f.Valid())
+	require.Equal(t, Errors{"username": "This username is reserved."}, f.Errors())
+
+	f.Set("username", "max")
+	require.False(t, f.Valid())
+	require.Equal(t, Errors{"": "The passwords do not match."}, f.Errors())
+	require.Empty(t, f.FormError())
+
+	f.submitted = true
+	require.Equal(t, "The passwords do not match.", f.FormError())
+
+	f.Set("confirm", "secret")
+	require.True(t, f.Valid())
+	require.Empty(t, f.Errors())
+}
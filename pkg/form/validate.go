@@ -0,0 +1,249 @@
+package form
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// Messages contains the error messages reported by the validation rules. The
+// messages of the min and max rules are formatted with the rule value. They can
+// be replaced, e.g. to translate them.
+var Messages = struct {
+	// Reported when a required field is empty.
+	Required string
+
+	// Reported when a number is lower than the min rule.
+	Min string
+
+	// Reported when a number is greater than the max rule.
+	Max string
+
+	// Reported when a string is shorter than the min rule.
+	MinLength string
+
+	// Reported when a string is longer than the max rule.
+	MaxLength string
+
+	// Reported when a string does not match the pattern rule.
+	Pattern string
+
+	// Reported when a string is not an email address.
+	Email string
+
+	// Reported when a value cannot be converted to the field type.
+	Type string
+}{
+	Required:  "This field is required.",
+	Min:       "The value must be greater than or equal to %v.",
+	Max:       "The value must be less than or equal to %v.",
+	MinLength: "The value must be at least %v characters long.",
+	MaxLength: "The value must be at most %v characters long.",
+	Pattern:   "The value does not match the expected format.",
+	Email:     "The value must be an email address.",
+	Type:      "The value is not valid.",
+}
+
+// emailRegexp is the regular expression used by browsers to validate email
+// inputs.
+var emailRegexp = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+
+type rule struct {
+	name    string
+	value   string
+	number  float64
+	pattern *regexp.Regexp
+}
+
+// parseRules parses the rules of a validate tag. Rules are separated by commas,
+// and the pattern rule takes the rest of the tag so that its regular expression
+// can contain commas.
+func parseRules(tag string) ([]rule, error) {
+	var rules []rule
+	for tag != "" {
+		var part string
+		if strings.HasPrefix(tag, "pattern=") {
+			part, tag = tag, ""
+		} else {
+			part, tag, _ = strings.Cut(tag, ",")
+		}
+
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		r := rule{name: name, value: value}
+
+		switch name {
+		case "":
+			continue
+
+		case "required", "email":
+
+		case "min", "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, errors.New("invalid validation rule value").
+					WithTag("rule", name).
+					WithTag("value", value).
+					Wrap(err)
+			}
+			r.number = n
+
+		case "pattern":
+			re, err := regexp.Compile("^(?:" + value + ")$")
+			if err != nil {
+				return nil, errors.New("invalid validation rule pattern").
+					WithTag("pattern", value).
+					Wrap(err)
+			}
+			r.pattern = re
+
+		default:
+			return nil, errors.New("unknown validation rule").WithTag("rule", name)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// check returns the error message of the rule for the given field value and
+// its string representation, or an empty string when the rule passes. Rules
+// other than required pass for empty values.
+func (r rule) check(v reflect.Value, s string) string {
+	isString := v.Kind() == reflect.String
+	if r.name == "required" {
+		switch {
+		case v.Kind() == reflect.Bool && !v.Bool(),
+			strings.TrimSpace(s) == "":
+			return Messages.Required
+		}
+		return ""
+	}
+	if s == "" {
+		return ""
+	}
+
+	switch r.name {
+	case "min":
+		if isString && float64(utf8.RuneCountInString(s)) < r.number {
+			return fmt.Sprintf(Messages.MinLength, r.value)
+		}
+		if n, ok := number(v); ok && n < r.number {
+			return fmt.Sprintf(Messages.Min, r.value)
+		}
+
+	case "max":
+		if isString && float64(utf8.RuneCountInString(s)) > r.number {
+			return fmt.Sprintf(Messages.MaxLength, r.value)
+		}
+		if n, ok := number(v); ok && n > r.number {
+			return fmt.Sprintf(Messages.Max, r.value)
+		}
+
+	case "email":
+		if !emailRegexp.MatchString(s) {
+			return Messages.Email
+		}
+
+	case "pattern":
+		if !r.pattern.MatchString(s) {
+			return Messages.Pattern
+		}
+	}
+	return ""
+}
+
+func number(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+
+	default:
+		return 0, false
+	}
+}
+
+func setValue(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+
+	case reflect.Bool:
+		if s == "" || s == "on" {
+			v.SetBool(s == "on")
+			return nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s = strings.TrimSpace(s); s == "" {
+			v.SetInt(0)
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s = strings.TrimSpace(s); s == "" {
+			v.SetUint(0)
+			return nil
+		}
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		if s = strings.TrimSpace(s); s == "" {
+			v.SetFloat(0)
+			return nil
+		}
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+
+	default:
+		return errors.New("unsupported form field type").WithTag("type", v.Type())
+	}
+	return nil
+}
+
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if v.IsZero() {
+			return ""
+		}
+		return fmt.Sprint(v.Interface())
+
+	default:
+		return ""
+	}
+}
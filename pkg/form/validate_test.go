@@ -0,0 +1,180 @@
+package form
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRules(t *testing.T) {
+	t.Run("rules", func(t *testing.T) {
+		rules, err := parseRules("required, min=3,max=10.5,email,pattern=^[a-z]{1,3},[0-9]+$")
+		require.NoError(t, err)
+		require.Len(t, rules, 5)
+		require.Equal(t, "required", rules[0].name)
+		require.Equal(t, float64(3), rules[1].number)
+		require.Equal(t, 10.5, rules[2].number)
+		require.Equal(t, "email", rules[3].name)
+		require.Equal(t, "^[a-z]{1,3},[0-9]+$", rules[4].value)
+		require.True(t, rules[4].pattern.MatchString("ab,12"))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		rules, err := parseRules("")
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		_, err := parseRules("min=three")
+		require.Error(t, err)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := parseRules("pattern=[a-z")
+		require.Error(t, err)
+	})
+
+	t.Run("unknown rule", func(t *testing.T) {
+		_, err := parseRules("uppercase")
+		require.Error(t, err)
+	})
+}
+
+func TestRuleCheck(t *testing.T) {
+	utests := []struct {
+		scenario string
+		rule     string
+		value    any
+		raw      string
+		expected string
+	}{
+		{
+			scenario: "required string",
+			rule:     "required",
+			value:    " ",
+			raw:      " ",
+			expected: Messages.Required,
+		},
+		{
+			scenario: "required unchecked bool",
+			rule:     "required",
+			value:    false,
+			raw:      "false",
+			expected: Messages.Required,
+		},
+		{
+			scenario: "required number",
+			rule:     "required",
+			value:    0,
+			raw:      "0",
+		},
+		{
+			scenario: "min length",
+			rule:     "min=3",
+			value:    "hé",
+			raw:      "hé",
+			expected: fmt.Sprintf(Messages.MinLength, 3),
+		},
+		{
+			scenario: "max length",
+			rule:     "max=3",
+			value:    "héllo",
+			raw:      "héllo",
+			expected: fmt.Sprintf(Messages.MaxLength, 3),
+		},
+		{
+			scenario: "min number",
+			rule:     "min=18",
+			value:    17,
+			raw:      "17",
+			expected: fmt.Sprintf(Messages.Min, 18),
+		},
+		{
+			scenario: "max number",
+			rule:     "max=1.5",
+			value:    1.75,
+			raw:      "1.75",
+			expected: fmt.Sprintf(Messages.Max, 1.5),
+		},
+		{
+			scenario: "valid email",
+			rule:     "email",
+			value:    "max@go-app.dev",
+			raw:      "max@go-app.dev",
+		},
+		{
+			scenario: "invalid email",
+			rule:     "email",
+			value:    "max@",
+			raw:      "max@",
+			expected: Messages.Email,
+		},
+		{
+			scenario: "pattern is anchored",
+			rule:     "pattern=[0-9]+",
+			value:    "42a",
+			raw:      "42a",
+			expected: Messages.Pattern,
+		},
+		{
+			scenario: "empty value passes non required rules",
+			rule:     "email",
+			value:    "",
+			raw:      "",
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			rules, err := parseRules(u.rule)
+			require.NoError(t, err)
+			require.Equal(t, u.expected, rules[0].check(reflect.ValueOf(u.value), u.raw))
+		})
+	}
+}
+
+func TestSetValue(t *testing.T) {
+	var v struct {
+		String string
+		Bool   bool
+		Int    int8
+		Uint   uint
+		Float  float32
+		Slice  []string
+	}
+	value := reflect.ValueOf(&v).Elem()
+
+	require.NoError(t, setValue(value.Field(0), "hello"))
+	require.Equal(t, "hello", v.String)
+
+	require.NoError(t, setValue(value.Field(1), "on"))
+	require.True(t, v.Bool)
+	require.NoError(t, setValue(value.Field(1), "false"))
+	require.False(t, v.Bool)
+
+	require.NoError(t, setValue(value.Field(2), " 42 "))
+	require.Equal(t, int8(42), v.Int)
+	require.Error(t, setValue(value.Field(2), "420"))
+
+	require.NoError(t, setValue(value.Field(3), "21"))
+	require.Equal(t, uint(21), v.Uint)
+	require.Error(t, setValue(value.Field(3), "-1"))
+
+	require.NoError(t, setValue(value.Field(4), "4.2"))
+	require.Equal(t, float32(4.2), v.Float)
+	require.NoError(t, setValue(value.Field(4), ""))
+	require.Zero(t, v.Float)
+
+	require.Error(t, setValue(value.Field(5), "a"))
+}
+
+func TestFormatValue(t *testing.T) {
+	require.Equal(t, "hello", formatValue(reflect.ValueOf("hello")))
+	require.Equal(t, "true", formatValue(reflect.ValueOf(true)))
+	require.Equal(t, "42", formatValue(reflect.ValueOf(42)))
+	require.Equal(t, "4.2", formatValue(reflect.ValueOf(4.2)))
+	require.Equal(t, "", formatValue(reflect.ValueOf(0)))
+}
@@ -0,0 +1,209 @@
+// Package i18n provides message catalogs, plural rules and language
+// negotiation to translate go-app applications.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// Catalog represents a set of translated messages, grouped by language.
+type Catalog struct {
+	// The language used when a message is not translated in the requested
+	// language. Messages that are not found are returned as their key.
+	Fallback string
+
+	mutex    sync.RWMutex
+	messages map[string]map[string]Message
+}
+
+// Message represents a translated message with its plural forms. Forms are
+// selected with the plural rules of the message language. Other is used when
+// the selected form is empty. Zero, when set, is used for a count of 0 in any
+// language.
+type Message struct {
+	Zero  string `json:"zero,omitempty"`
+	One   string `json:"one,omitempty"`
+	Two   string `json:"two,omitempty"`
+	Few   string `json:"few,omitempty"`
+	Many  string `json:"many,omitempty"`
+	Other string `json:"other,omitempty"`
+}
+
+// UnmarshalJSON decodes a message from either a string or an object with its
+// plural forms.
+func (m *Message) UnmarshalJSON(b []byte) error {
+	var other string
+	if err := json.Unmarshal(b, &other); err == nil {
+		*m = Message{Other: other}
+		return nil
+	}
+
+	type message Message
+	return json.Unmarshal(b, (*message)(m))
+}
+
+func (m Message) form(c PluralCategory) string {
+	var form string
+	switch c {
+	case Zero:
+		form = m.Zero
+	case One:
+		form = m.One
+	case Two:
+		form = m.Two
+	case Few:
+		form = m.Few
+	case Many:
+		form = m.Many
+	}
+
+	if form == "" {
+		form = m.Other
+	}
+	return form
+}
+
+func (m *Message) setForm(c PluralCategory, v string) {
+	switch c {
+	case Zero:
+		m.Zero = v
+	case One:
+		m.One = v
+	case Two:
+		m.Two = v
+	case Few:
+		m.Few = v
+	case Many:
+		m.Many = v
+	default:
+		m.Other = v
+	}
+}
+
+// Set sets the message with the given key for the given language.
+func (c *Catalog) Set(lang, key string, m Message) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.messages == nil {
+		c.messages = make(map[string]map[string]Message)
+	}
+
+	lang = normalizeLang(lang)
+	messages, ok := c.messages[lang]
+	if !ok {
+		messages = make(map[string]Message)
+		c.messages[lang] = messages
+	}
+	messages[key] = m
+}
+
+// LoadJSON loads the messages for the given language from a JSON object where
+// each key is associated with either a string or an object with the plural
+// forms of the message:
+//
+//	{
+//	  "hello": "Bonjour %s",
+//	  "items": {"one": "%d élément", "other": "%d éléments"}
+//	}
+func (c *Catalog) LoadJSON(lang string, b []byte) error {
+	var messages map[string]Message
+	if err := json.Unmarshal(b, &messages); err != nil {
+		return errors.New("decoding json messages failed").
+			WithTag("lang", lang).
+			Wrap(err)
+	}
+
+	for key, m := range messages {
+		c.Set(lang, key, m)
+	}
+	return nil
+}
+
+// Langs returns the languages that have messages in the catalog.
+func (c *Catalog) Langs() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	langs := make([]string, 0, len(c.messages))
+	for lang := range c.messages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Translate returns the message with the given key in the given language.
+//
+// When the first argument is an integer, it is used as the count that selects
+// the plural form of the message. Arguments are formatted with fmt.Sprintf
+// when the message contains formatting verbs.
+func (c *Catalog) Translate(lang, key string, args ...any) string {
+	m, lang, ok := c.message(lang, key)
+	if !ok {
+		return key
+	}
+
+	format := m.Other
+	if len(args) != 0 {
+		if n, isCount := count(args[0]); isCount {
+			format = m.form(Plural(lang, n))
+			if n == 0 && m.Zero != "" {
+				format = m.Zero
+			}
+		}
+	}
+
+	if len(args) == 0 || !strings.Contains(format, "%") {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func (c *Catalog) message(lang, key string) (Message, string, bool) {
+	if c == nil {
+		return Message{}, "", false
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	lang = normalizeLang(lang)
+	for _, l := range []string{lang, baseLang(lang), normalizeLang(c.Fallback)} {
+		if m, ok := c.messages[l][key]; ok {
+			return m, l, true
+		}
+	}
+	return Message{}, "", false
+}
+
+func count(v any) (int, bool) {
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint()), true
+
+	default:
+		return 0, false
+	}
+}
+
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+}
+
+func baseLang(lang string) string {
+	if i := strings.IndexByte(lang, '-'); i > 0 {
+		return lang[:i]
+	}
+	return lang
+}
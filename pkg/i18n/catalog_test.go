@@ -0,0 +1,137 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogTranslate(t *testing.T) {
+	var c Catalog
+	c.Fallback = "en"
+
+	err := c.LoadJSON("en", []byte(`{
+		"hello": "Hello %s",
+		"title": "Welcome",
+		"items": {"zero": "No items", "one": "%d item", "other": "%d items"}
+	}`))
+	require.NoError(t, err)
+
+	err = c.LoadJSON("fr", []byte(`{
+		"hello": "Bonjour %s",
+		"items": {"one": "%d élément", "other": "%d éléments"}
+	}`))
+	require.NoError(t, err)
+
+	err = c.LoadJSON("ru", []byte(`{
+		"items": {"one": "%d предмет", "few": "%d предмета", "many": "%d предметов"}
+	}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		scenario string
+		lang     string
+		key      string
+		args     []any
+		expected string
+	}{
+		{
+			scenario: "message with argument",
+			lang:     "fr",
+			key:      "hello",
+			args:     []any{"Maxence"},
+			expected: "Bonjour Maxence",
+		},
+		{
+			scenario: "message without argument",
+			lang:     "en",
+			key:      "title",
+			expected: "Welcome",
+		},
+		{
+			scenario: "message from base language",
+			lang:     "fr-CA",
+			key:      "hello",
+			args:     []any{"Maxence"},
+			expected: "Bonjour Maxence",
+		},
+		{
+			scenario: "message from fallback language",
+			lang:     "fr",
+			key:      "title",
+			expected: "Welcome",
+		},
+		{
+			scenario: "missing message returns key",
+			lang:     "fr",
+			key:      "missing",
+			expected: "missing",
+		},
+		{
+			scenario: "english singular",
+			lang:     "en",
+			key:      "items",
+			args:     []any{1},
+			expected: "1 item",
+		},
+		{
+			scenario: "english plural",
+			lang:     "en",
+			key:      "items",
+			args:     []any{uint(2)},
+			expected: "2 items",
+		},
+		{
+			scenario: "english zero",
+			lang:     "en",
+			key:      "items",
+			args:     []any{0},
+			expected: "No items",
+		},
+		{
+			scenario: "french zero is singular",
+			lang:     "fr",
+			key:      "items",
+			args:     []any{0},
+			expected: "0 élément",
+		},
+		{
+			scenario: "russian few",
+			lang:     "ru",
+			key:      "items",
+			args:     []any{22},
+			expected: "22 предмета",
+		},
+		{
+			scenario: "russian many",
+			lang:     "ru",
+			key:      "items",
+			args:     []any{11},
+			expected: "11 предметов",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			require.Equal(t, test.expected, c.Translate(test.lang, test.key, test.args...))
+		})
+	}
+}
+
+func TestCatalogLoadInvalidJSON(t *testing.T) {
+	var c Catalog
+	err := c.LoadJSON("en", []byte(`{"hello": 42}`))
+	require.Error(t, err)
+}
+
+func TestCatalogLangs(t *testing.T) {
+	var c Catalog
+	c.Set("fr", "hello", Message{Other: "Bonjour"})
+	c.Set("en_US", "hello", Message{Other: "Hello"})
+	require.Equal(t, []string{"en-us", "fr"}, c.Langs())
+}
+
+func TestNilCatalogTranslate(t *testing.T) {
+	var c *Catalog
+	require.Equal(t, "hello", c.Translate("en", "hello"))
+}
@@ -0,0 +1,313 @@
+package i18n
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Style represents the length of a formatted date or time.
+type Style string
+
+// The date and time styles.
+const (
+	Short  Style = "short"
+	Medium Style = "medium"
+	Long   Style = "long"
+	Full   Style = "full"
+)
+
+// FormatNumber formats the given number in the given language, with up to 3
+// fraction digits.
+//
+// Formatting functions use the JavaScript Intl API in the browser. On the
+// server, they use built-in data that mirrors the output of Intl for en,
+// en-GB, fr, de, es, it, pt, nl and ja, so that prerendered pages match the
+// pages rendered by the app. Numbers in other languages are formatted with the
+// CLDR data of golang.org/x/text, and dates with the numeric patterns of the
+// CLDR root locale, which can differ from the output of browsers.
+func FormatNumber(lang string, v float64) string {
+	return formatNumber(lang, v, 0, 3)
+}
+
+// FormatDecimal formats the given number in the given language, with the
+// given number of fraction digits.
+func FormatDecimal(lang string, v float64, fractionDigits int) string {
+	return formatNumber(lang, v, fractionDigits, fractionDigits)
+}
+
+// FormatPercent formats the given ratio as a percentage in the given language,
+// e.g. 0.25 is formatted as "25%" in English.
+func FormatPercent(lang string, v float64) string {
+	return formatPercent(lang, v)
+}
+
+// FormatCurrency formats the given amount in the given language and ISO 4217
+// currency, e.g. "USD" or "EUR".
+func FormatCurrency(lang string, v float64, currency string) string {
+	return formatCurrency(lang, v, strings.ToUpper(currency))
+}
+
+// FormatDate formats the date of the given time in the given language and
+// style. The time is formatted in its own location.
+func FormatDate(lang string, t time.Time, style Style) string {
+	return formatDate(lang, t, style)
+}
+
+// FormatTime formats the time of day of the given time in the given language.
+// The Long and Full styles are formatted like Medium. The time is formatted
+// in its own location.
+func FormatTime(lang string, t time.Time, style Style) string {
+	if style != Short {
+		style = Medium
+	}
+	return formatTime(lang, t, style)
+}
+
+// Formatter formats values in a given language.
+type Formatter struct {
+	Lang string
+}
+
+// Number formats the given number. See FormatNumber.
+func (f Formatter) Number(v float64) string {
+	return FormatNumber(f.Lang, v)
+}
+
+// Decimal formats the given number with the given number of fraction digits.
+// See FormatDecimal.
+func (f Formatter) Decimal(v float64, fractionDigits int) string {
+	return FormatDecimal(f.Lang, v, fractionDigits)
+}
+
+// Percent formats the given ratio as a percentage. See FormatPercent.
+func (f Formatter) Percent(v float64) string {
+	return FormatPercent(f.Lang, v)
+}
+
+// Currency formats the given amount in the given currency. See
+// FormatCurrency.
+func (f Formatter) Currency(v float64, currency string) string {
+	return FormatCurrency(f.Lang, v, currency)
+}
+
+// Date formats the date of the given time. See FormatDate.
+func (f Formatter) Date(t time.Time, style Style) string {
+	return FormatDate(f.Lang, t, style)
+}
+
+// Time formats the time of day of the given time. See FormatTime.
+func (f Formatter) Time(t time.Time, style Style) string {
+	return FormatTime(f.Lang, t, style)
+}
+
+func goFormatNumber(lang string, v float64, minFractionDigits, maxFractionDigits int) string {
+	d, ok := lookupFormatData(lang)
+	if !ok {
+		return cldrFormatNumber(lang, v, minFractionDigits, maxFractionDigits)
+	}
+	return d.number(v, minFractionDigits, maxFractionDigits)
+}
+
+func goFormatPercent(lang string, v float64) string {
+	d, ok := lookupFormatData(lang)
+	if !ok {
+		return cldrFormatPercent(lang, v)
+	}
+	n := d.number(v*100, 0, 0)
+	return strings.Replace(d.percent, "#", n, 1)
+}
+
+func goFormatCurrency(lang string, v float64, currency string) string {
+	d, ok := lookupFormatData(lang)
+	if !ok {
+		return cldrFormatCurrency(lang, v, currency)
+	}
+
+	digits := currencyDigits(currency)
+
+	symbol, ok := d.currencies[currency]
+	if !ok {
+		symbol = currency
+	}
+
+	pattern := d.currency
+	if v < 0 && d.negativeCurrency != "" {
+		pattern = d.negativeCurrency
+	} else if v < 0 {
+		pattern = "-" + pattern
+	}
+	if !ok && strings.HasPrefix(strings.TrimPrefix(pattern, "-"), "¤#") {
+		pattern = strings.Replace(pattern, "¤#", "¤\u00a0#", 1)
+	}
+
+	n := d.number(math.Abs(v), digits, digits)
+	return strings.NewReplacer("¤", symbol, "#", n).Replace(pattern)
+}
+
+func goFormatDate(lang string, t time.Time, style Style) string {
+	d, ok := lookupFormatData(lang)
+	if !ok {
+		d = rootFormatData
+	}
+
+	var layout string
+	switch style {
+	case Short:
+		layout = d.shortDate
+	case Long:
+		layout = d.longDate
+	case Full:
+		layout = d.fullDate
+	default:
+		layout = d.mediumDate
+	}
+	return d.formatDateTime(layout, t)
+}
+
+func goFormatTime(lang string, t time.Time, style Style) string {
+	d, ok := lookupFormatData(lang)
+	if !ok {
+		d = rootFormatData
+	}
+
+	layout := d.mediumTime
+	if style == Short {
+		layout = d.shortTime
+	}
+	return d.formatDateTime(layout, t)
+}
+
+// currencyDigits returns the number of fraction digits of the given ISO 4217
+// currency.
+func currencyDigits(currency string) int {
+	switch currency {
+	case "JPY", "KRW", "VND", "CLP", "ISK":
+		return 0
+	default:
+		return 2
+	}
+}
+
+func (d formatData) number(v float64, minFractionDigits, maxFractionDigits int) string {
+	if math.IsNaN(v) {
+		return "NaN"
+	}
+	if math.IsInf(v, 0) {
+		if v < 0 {
+			return "-∞"
+		}
+		return "∞"
+	}
+
+	integer, fraction, negative := roundDecimal(v, maxFractionDigits)
+	fraction = strings.TrimRight(fraction, "0")
+	for len(fraction) < minFractionDigits {
+		fraction += "0"
+	}
+
+	var b strings.Builder
+	if negative {
+		b.WriteByte('-')
+	}
+	b.WriteString(d.group(integer))
+	if fraction != "" {
+		b.WriteString(d.decimal)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+func (d formatData) group(integer string) string {
+	minGrouping := d.minGrouping
+	if minGrouping < 1 {
+		minGrouping = 1
+	}
+	if len(integer) < 4+minGrouping-1 {
+		return integer
+	}
+
+	var b strings.Builder
+	head := len(integer) % 3
+	if head == 0 {
+		head = 3
+	}
+	b.WriteString(integer[:head])
+	for i := head; i < len(integer); i += 3 {
+		b.WriteString(d.groupSeparator)
+		b.WriteString(integer[i : i+3])
+	}
+	return b.String()
+}
+
+// roundDecimal rounds the shortest decimal representation of the given number
+// to the given number of fraction digits, with ties rounded away from zero.
+// It returns the integer and fraction digits, and whether the rounded number
+// is negative.
+func roundDecimal(v float64, fractionDigits int) (string, string, bool) {
+	negative := v < 0
+	s := strconv.FormatFloat(math.Abs(v), 'f', -1, 64)
+	integer, fraction, _ := strings.Cut(s, ".")
+
+	if len(fraction) > fractionDigits {
+		roundUp := fraction[fractionDigits] >= '5'
+		digits := []byte(integer + fraction[:fractionDigits])
+		if roundUp {
+			i := len(digits) - 1
+			for ; i >= 0; i-- {
+				if digits[i] < '9' {
+					digits[i]++
+					break
+				}
+				digits[i] = '0'
+			}
+			if i < 0 {
+				digits = append([]byte{'1'}, digits...)
+			}
+		}
+		integer = string(digits[:len(digits)-fractionDigits])
+		fraction = string(digits[len(digits)-fractionDigits:])
+	}
+
+	if strings.Trim(integer+fraction, "0") == "" {
+		negative = false
+	}
+	return integer, fraction, negative
+}
+
+func (d formatData) formatDateTime(layout string, t time.Time) string {
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+	period := "AM"
+	if t.Hour() >= 12 {
+		period = "PM"
+	}
+
+	return strings.NewReplacer(
+		"{yyyy}", strconv.Itoa(t.Year()),
+		"{yy}", twoDigits(t.Year()%100),
+		"{MMMM}", d.months[t.Month()-1],
+		"{MMM}", d.shortMonths[t.Month()-1],
+		"{MM}", twoDigits(int(t.Month())),
+		"{M}", strconv.Itoa(int(t.Month())),
+		"{dd}", twoDigits(t.Day()),
+		"{d}", strconv.Itoa(t.Day()),
+		"{EEEE}", d.weekdays[t.Weekday()],
+		"{HH}", twoDigits(t.Hour()),
+		"{H}", strconv.Itoa(t.Hour()),
+		"{h}", strconv.Itoa(hour12),
+		"{mm}", twoDigits(t.Minute()),
+		"{ss}", twoDigits(t.Second()),
+		"{a}", period,
+	).Replace(layout)
+}
+
+func twoDigits(v int) string {
+	if v < 10 {
+		return "0" + strconv.Itoa(v)
+	}
+	return strconv.Itoa(v)
+}
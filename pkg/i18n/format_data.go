@@ -0,0 +1,329 @@
+package i18n
+
+// formatData describes how numbers, currencies and dates are formatted in a
+// language. Patterns mirror the output of the JavaScript Intl API.
+type formatData struct {
+	decimal        string
+	groupSeparator string
+	minGrouping    int
+
+	percent          string
+	currency         string
+	negativeCurrency string
+	currencies       map[string]string
+
+	months      []string
+	shortMonths []string
+	weekdays    []string
+
+	shortDate  string
+	mediumDate string
+	longDate   string
+	fullDate   string
+	shortTime  string
+	mediumTime string
+}
+
+// lookupFormatData returns the built-in data of the given language, or of its
+// base language. It returns false when there is none.
+func lookupFormatData(lang string) (formatData, bool) {
+	lang = normalizeLang(lang)
+	if d, ok := formatDataByLang[lang]; ok {
+		return d, true
+	}
+	d, ok := formatDataByLang[baseLang(lang)]
+	return d, ok
+}
+
+// rootFormatData formats dates with the numeric patterns of the CLDR root
+// locale, for languages without built-in data.
+var rootFormatData = formatData{
+	decimal:        ".",
+	groupSeparator: ",",
+	percent:        "#%",
+	currency:       "¤\u00a0#",
+	months: []string{
+		"M01", "M02", "M03", "M04", "M05", "M06",
+		"M07", "M08", "M09", "M10", "M11", "M12",
+	},
+	shortMonths: []string{
+		"M01", "M02", "M03", "M04", "M05", "M06",
+		"M07", "M08", "M09", "M10", "M11", "M12",
+	},
+	weekdays:   []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	shortDate:  "{yyyy}-{MM}-{dd}",
+	mediumDate: "{yyyy}-{MM}-{dd}",
+	longDate:   "{yyyy}-{MM}-{dd}",
+	fullDate:   "{yyyy}-{MM}-{dd}",
+	shortTime:  "{HH}:{mm}",
+	mediumTime: "{HH}:{mm}:{ss}",
+}
+
+var (
+	englishMonths = []string{
+		"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December",
+	}
+
+	englishWeekdays = []string{
+		"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday",
+	}
+)
+
+var formatDataByLang = map[string]formatData{
+	"en": {
+		decimal:        ".",
+		groupSeparator: ",",
+		percent:        "#%",
+		currency:       "¤#",
+		currencies: map[string]string{
+			"USD": "$",
+			"EUR": "€",
+			"GBP": "£",
+			"JPY": "¥",
+			"CAD": "CA$",
+			"AUD": "A$",
+			"INR": "₹",
+		},
+		months: englishMonths,
+		shortMonths: []string{
+			"Jan", "Feb", "Mar", "Apr", "May", "Jun",
+			"Jul", "Aug", "Sep", "Oct", "Nov", "Dec",
+		},
+		weekdays:   englishWeekdays,
+		shortDate:  "{M}/{d}/{yy}",
+		mediumDate: "{MMM} {d}, {yyyy}",
+		longDate:   "{MMMM} {d}, {yyyy}",
+		fullDate:   "{EEEE}, {MMMM} {d}, {yyyy}",
+		shortTime:  "{h}:{mm}\u202f{a}",
+		mediumTime: "{h}:{mm}:{ss}\u202f{a}",
+	},
+
+	"en-gb": {
+		decimal:        ".",
+		groupSeparator: ",",
+		percent:        "#%",
+		currency:       "¤#",
+		currencies: map[string]string{
+			"USD": "US$",
+			"EUR": "€",
+			"GBP": "£",
+			"JPY": "JP¥",
+		},
+		months: englishMonths,
+		shortMonths: []string{
+			"Jan", "Feb", "Mar", "Apr", "May", "Jun",
+			"Jul", "Aug", "Sept", "Oct", "Nov", "Dec",
+		},
+		weekdays:   englishWeekdays,
+		shortDate:  "{dd}/{MM}/{yyyy}",
+		mediumDate: "{d} {MMM} {yyyy}",
+		longDate:   "{d} {MMMM} {yyyy}",
+		fullDate:   "{EEEE} {d} {MMMM} {yyyy}",
+		shortTime:  "{HH}:{mm}",
+		mediumTime: "{HH}:{mm}:{ss}",
+	},
+
+	"fr": {
+		decimal:        ",",
+		groupSeparator: "\u202f",
+		percent:        "#\u202f%",
+		currency:       "#\u00a0¤",
+		currencies: map[string]string{
+			"USD": "$US",
+			"EUR": "€",
+			"GBP": "£GB",
+		},
+		months: []string{
+			"janvier", "février", "mars", "avril", "mai", "juin",
+			"juillet", "août", "septembre", "octobre", "novembre", "décembre",
+		},
+		shortMonths: []string{
+			"janv.", "févr.", "mars", "avr.", "mai", "juin",
+			"juil.", "août", "sept.", "oct.", "nov.", "déc.",
+		},
+		weekdays: []string{
+			"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi",
+		},
+		shortDate:  "{dd}/{MM}/{yyyy}",
+		mediumDate: "{d} {MMM} {yyyy}",
+		longDate:   "{d} {MMMM} {yyyy}",
+		fullDate:   "{EEEE} {d} {MMMM} {yyyy}",
+		shortTime:  "{HH}:{mm}",
+		mediumTime: "{HH}:{mm}:{ss}",
+	},
+
+	"de": {
+		decimal:        ",",
+		groupSeparator: ".",
+		percent:        "#\u00a0%",
+		currency:       "#\u00a0¤",
+		currencies: map[string]string{
+			"USD": "$",
+			"EUR": "€",
+			"GBP": "£",
+			"JPY": "¥",
+		},
+		months: []string{
+			"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember",
+		},
+		shortMonths: []string{
+			"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni",
+			"Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez.",
+		},
+		weekdays: []string{
+			"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag",
+		},
+		shortDate:  "{dd}.{MM}.{yy}",
+		mediumDate: "{dd}.{MM}.{yyyy}",
+		longDate:   "{d}. {MMMM} {yyyy}",
+		fullDate:   "{EEEE}, {d}. {MMMM} {yyyy}",
+		shortTime:  "{HH}:{mm}",
+		mediumTime: "{HH}:{mm}:{ss}",
+	},
+
+	"es": {
+		decimal:        ",",
+		groupSeparator: ".",
+		minGrouping:    2,
+		percent:        "#\u00a0%",
+		currency:       "#\u00a0¤",
+		currencies: map[string]string{
+			"USD": "US$",
+			"EUR": "€",
+		},
+		months: []string{
+			"enero", "febrero", "marzo", "abril", "mayo", "junio",
+			"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre",
+		},
+		shortMonths: []string{
+			"ene", "feb", "mar", "abr", "may", "jun",
+			"jul", "ago", "sept", "oct", "nov", "dic",
+		},
+		weekdays: []string{
+			"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado",
+		},
+		shortDate:  "{d}/{M}/{yy}",
+		mediumDate: "{d} {MMM} {yyyy}",
+		longDate:   "{d} de {MMMM} de {yyyy}",
+		fullDate:   "{EEEE}, {d} de {MMMM} de {yyyy}",
+		shortTime:  "{H}:{mm}",
+		mediumTime: "{H}:{mm}:{ss}",
+	},
+
+	"it": {
+		decimal:        ",",
+		groupSeparator: ".",
+		percent:        "#%",
+		currency:       "#\u00a0¤",
+		currencies: map[string]string{
+			"EUR": "€",
+		},
+		months: []string{
+			"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno",
+			"luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre",
+		},
+		shortMonths: []string{
+			"gen", "feb", "mar", "apr", "mag", "giu",
+			"lug", "ago", "set", "ott", "nov", "dic",
+		},
+		weekdays: []string{
+			"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato",
+		},
+		shortDate:  "{dd}/{MM}/{yy}",
+		mediumDate: "{d} {MMM} {yyyy}",
+		longDate:   "{d} {MMMM} {yyyy}",
+		fullDate:   "{EEEE} {d} {MMMM} {yyyy}",
+		shortTime:  "{HH}:{mm}",
+		mediumTime: "{HH}:{mm}:{ss}",
+	},
+
+	"pt": {
+		decimal:        ",",
+		groupSeparator: ".",
+		percent:        "#%",
+		currency:       "¤\u00a0#",
+		currencies: map[string]string{
+			"BRL": "R$",
+			"USD": "US$",
+			"EUR": "€",
+		},
+		months: []string{
+			"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+			"julho", "agosto", "setembro", "outubro", "novembro", "dezembro",
+		},
+		shortMonths: []string{
+			"jan.", "fev.", "mar.", "abr.", "mai.", "jun.",
+			"jul.", "ago.", "set.", "out.", "nov.", "dez.",
+		},
+		weekdays: []string{
+			"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado",
+		},
+		shortDate:  "{dd}/{MM}/{yyyy}",
+		mediumDate: "{d} de {MMM} de {yyyy}",
+		longDate:   "{d} de {MMMM} de {yyyy}",
+		fullDate:   "{EEEE}, {d} de {MMMM} de {yyyy}",
+		shortTime:  "{HH}:{mm}",
+		mediumTime: "{HH}:{mm}:{ss}",
+	},
+
+	"nl": {
+		decimal:          ",",
+		groupSeparator:   ".",
+		percent:          "#%",
+		currency:         "¤\u00a0#",
+		negativeCurrency: "¤\u00a0-#",
+		currencies: map[string]string{
+			"USD": "US$",
+			"EUR": "€",
+			"GBP": "£",
+		},
+		months: []string{
+			"januari", "februari", "maart", "april", "mei", "juni",
+			"juli", "augustus", "september", "oktober", "november", "december",
+		},
+		shortMonths: []string{
+			"jan", "feb", "mrt", "apr", "mei", "jun",
+			"jul", "aug", "sep", "okt", "nov", "dec",
+		},
+		weekdays: []string{
+			"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag",
+		},
+		shortDate:  "{dd}-{MM}-{yyyy}",
+		mediumDate: "{d} {MMM} {yyyy}",
+		longDate:   "{d} {MMMM} {yyyy}",
+		fullDate:   "{EEEE} {d} {MMMM} {yyyy}",
+		shortTime:  "{HH}:{mm}",
+		mediumTime: "{HH}:{mm}:{ss}",
+	},
+
+	"ja": {
+		decimal:        ".",
+		groupSeparator: ",",
+		percent:        "#%",
+		currency:       "¤#",
+		currencies: map[string]string{
+			"JPY": "￥",
+			"USD": "$",
+			"EUR": "€",
+		},
+		months: []string{
+			"1月", "2月", "3月", "4月", "5月", "6月",
+			"7月", "8月", "9月", "10月", "11月", "12月",
+		},
+		shortMonths: []string{
+			"1月", "2月", "3月", "4月", "5月", "6月",
+			"7月", "8月", "9月", "10月", "11月", "12月",
+		},
+		weekdays: []string{
+			"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日",
+		},
+		shortDate:  "{yyyy}/{MM}/{dd}",
+		mediumDate: "{yyyy}/{MM}/{dd}",
+		longDate:   "{yyyy}年{M}月{d}日",
+		fullDate:   "{yyyy}年{M}月{d}日{EEEE}",
+		shortTime:  "{H}:{mm}",
+		mediumTime: "{H}:{mm}:{ss}",
+	},
+}
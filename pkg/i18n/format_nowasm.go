@@ -0,0 +1,65 @@
+//go:build !wasm
+// +build !wasm
+
+package i18n
+
+import (
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+func formatNumber(lang string, v float64, minFractionDigits, maxFractionDigits int) string {
+	return goFormatNumber(lang, v, minFractionDigits, maxFractionDigits)
+}
+
+func formatPercent(lang string, v float64) string {
+	return goFormatPercent(lang, v)
+}
+
+func formatCurrency(lang string, v float64, currency string) string {
+	return goFormatCurrency(lang, v, currency)
+}
+
+func formatDate(lang string, t time.Time, style Style) string {
+	return goFormatDate(lang, t, style)
+}
+
+func formatTime(lang string, t time.Time, style Style) string {
+	return goFormatTime(lang, t, style)
+}
+
+func cldrFormatNumber(lang string, v float64, minFractionDigits, maxFractionDigits int) string {
+	return cldrPrinter(lang).Sprint(number.Decimal(v,
+		number.MinFractionDigits(minFractionDigits),
+		number.MaxFractionDigits(maxFractionDigits),
+	))
+}
+
+func cldrFormatPercent(lang string, v float64) string {
+	return cldrPrinter(lang).Sprint(number.Percent(v, number.MaxFractionDigits(0)))
+}
+
+func cldrFormatCurrency(lang string, v float64, code string) string {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return code + "\u00a0" + cldrFormatNumber(lang, v, 2, 2)
+	}
+
+	digits := currencyDigits(code)
+	p := cldrPrinter(lang)
+	return p.Sprint(currency.NarrowSymbol(unit)) + "\u00a0" + p.Sprint(number.Decimal(v,
+		number.MinFractionDigits(digits),
+		number.MaxFractionDigits(digits),
+	))
+}
+
+// cldrPrinter returns a printer that formats values with the CLDR data of the
+// given language.
+func cldrPrinter(lang string) *message.Printer {
+	tag, _ := language.Parse(lang)
+	return message.NewPrinter(tag)
+}
@@ -0,0 +1,150 @@
+//go:build !wasm
+// +build !wasm
+
+package i18n
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		lang     string
+		value    float64
+		expected string
+	}{
+		{lang: "en", value: 1234567.891, expected: "1,234,567.891"},
+		{lang: "en", value: 0.1 + 0.2, expected: "0.3"},
+		{lang: "en", value: 1.0005, expected: "1.001"},
+		{lang: "en", value: 999.9999, expected: "1,000"},
+		{lang: "en", value: -42, expected: "-42"},
+		{lang: "en", value: -0.0001, expected: "0"},
+		{lang: "fr", value: 1234567.891, expected: "1\u202f234\u202f567,891"},
+		{lang: "de", value: 1234.5, expected: "1.234,5"},
+		{lang: "es", value: 1234.5, expected: "1234,5"},
+		{lang: "es", value: 12345.5, expected: "12.345,5"},
+		{lang: "ja", value: 1234.5, expected: "1,234.5"},
+		{lang: "xx", value: 1234.5, expected: "1,234.5"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.lang+" "+test.expected, func(t *testing.T) {
+			require.Equal(t, test.expected, FormatNumber(test.lang, test.value))
+		})
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	require.Equal(t, "1,234.50", FormatDecimal("en", 1234.5, 2))
+	require.Equal(t, "1,235", FormatDecimal("en", 1234.5, 0))
+	require.Equal(t, "0,10", FormatDecimal("fr", 0.1, 2))
+}
+
+func TestFormatPercent(t *testing.T) {
+	require.Equal(t, "26%", FormatPercent("en", 0.256))
+	require.Equal(t, "26\u202f%", FormatPercent("fr", 0.256))
+	require.Equal(t, "26\u00a0%", FormatPercent("de", 0.256))
+}
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		lang     string
+		value    float64
+		currency string
+		expected string
+	}{
+		{lang: "en", value: 1234.5, currency: "USD", expected: "$1,234.50"},
+		{lang: "en", value: -1234.5, currency: "usd", expected: "-$1,234.50"},
+		{lang: "en", value: 1234.5, currency: "JPY", expected: "¥1,235"},
+		{lang: "en", value: 1234.5, currency: "CHF", expected: "CHF\u00a01,234.50"},
+		{lang: "en-GB", value: 1234.5, currency: "USD", expected: "US$1,234.50"},
+		{lang: "fr", value: 1234.5, currency: "EUR", expected: "1\u202f234,50\u00a0€"},
+		{lang: "fr-CA", value: -1234.5, currency: "EUR", expected: "-1\u202f234,50\u00a0€"},
+		{lang: "de", value: 1234.5, currency: "EUR", expected: "1.234,50\u00a0€"},
+		{lang: "pt-BR", value: 1234.5, currency: "BRL", expected: "R$\u00a01.234,50"},
+		{lang: "nl", value: -1234.5, currency: "EUR", expected: "€\u00a0-1.234,50"},
+		{lang: "ja", value: 1234.5, currency: "JPY", expected: "￥1,235"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.lang+" "+test.expected, func(t *testing.T) {
+			require.Equal(t, test.expected, FormatCurrency(test.lang, test.value, test.currency))
+		})
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)
+
+	tests := []struct {
+		lang     string
+		style    Style
+		expected string
+	}{
+		{lang: "en", style: Short, expected: "3/5/24"},
+		{lang: "en", style: Medium, expected: "Mar 5, 2024"},
+		{lang: "en", style: Long, expected: "March 5, 2024"},
+		{lang: "en", style: Full, expected: "Tuesday, March 5, 2024"},
+		{lang: "en-GB", style: Short, expected: "05/03/2024"},
+		{lang: "fr", style: Medium, expected: "5 mars 2024"},
+		{lang: "fr", style: Full, expected: "mardi 5 mars 2024"},
+		{lang: "de", style: Short, expected: "05.03.24"},
+		{lang: "de", style: Long, expected: "5. März 2024"},
+		{lang: "es", style: Long, expected: "5 de marzo de 2024"},
+		{lang: "pt", style: Medium, expected: "5 de mar. de 2024"},
+		{lang: "nl", style: Short, expected: "05-03-2024"},
+		{lang: "ja", style: Full, expected: "2024年3月5日火曜日"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.lang+" "+string(test.style), func(t *testing.T) {
+			require.Equal(t, test.expected, FormatDate(test.lang, date, test.style))
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	afternoon := time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)
+	midnight := time.Date(2024, 3, 5, 0, 5, 0, 0, time.UTC)
+
+	require.Equal(t, "2:07\u202fPM", FormatTime("en", afternoon, Short))
+	require.Equal(t, "2:07:09\u202fPM", FormatTime("en", afternoon, Long))
+	require.Equal(t, "12:05\u202fAM", FormatTime("en", midnight, Short))
+	require.Equal(t, "14:07", FormatTime("fr", afternoon, Short))
+	require.Equal(t, "0:05", FormatTime("es", midnight, Short))
+	require.Equal(t, "00:05:00", FormatTime("de", midnight, Medium))
+}
+
+func TestFormatTimeInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	date := time.Date(2024, 3, 5, 23, 30, 0, 0, time.UTC).In(loc)
+
+	require.Equal(t, "3/6/24", FormatDate("en", date, Short))
+	require.Equal(t, "01:30", FormatTime("fr", date, Short))
+}
+
+func TestFormatter(t *testing.T) {
+	f := Formatter{Lang: "fr"}
+	date := time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)
+
+	require.Equal(t, "1,5", f.Number(1.5))
+	require.Equal(t, "1,50", f.Decimal(1.5, 2))
+	require.Equal(t, "50\u202f%", f.Percent(0.5))
+	require.Equal(t, "1,50\u00a0€", f.Currency(1.5, "EUR"))
+	require.Equal(t, "05/03/2024", f.Date(date, Short))
+	require.Equal(t, "14:07", f.Time(date, Short))
+}
+
+func TestFormatLanguageWithoutBuiltInData(t *testing.T) {
+	date := time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)
+
+	require.Equal(t, "1\u00a0234,5", FormatNumber("sv-SE", 1234.5))
+	require.Equal(t, "25\u00a0%", FormatPercent("sv-SE", 0.25))
+	require.Equal(t, "€\u00a0−1\u00a0234,50", FormatCurrency("sv-SE", -1234.5, "EUR"))
+	require.Equal(t, "2024-03-05", FormatDate("sv-SE", date, Long))
+	require.Equal(t, "14:07", FormatTime("sv-SE", date, Short))
+	require.NotEqual(t, FormatNumber("en", 1234.5), FormatNumber("sv-SE", 1234.5))
+}
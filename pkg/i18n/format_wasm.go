@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"syscall/js"
+	"time"
+)
+
+func formatNumber(lang string, v float64, minFractionDigits, maxFractionDigits int) string {
+	return intlFormat("NumberFormat", lang, map[string]any{
+		"minimumFractionDigits": minFractionDigits,
+		"maximumFractionDigits": maxFractionDigits,
+	}, v, func() string {
+		return goFormatNumber(lang, v, minFractionDigits, maxFractionDigits)
+	})
+}
+
+func formatPercent(lang string, v float64) string {
+	return intlFormat("NumberFormat", lang, map[string]any{
+		"style": "percent",
+	}, v, func() string {
+		return goFormatPercent(lang, v)
+	})
+}
+
+func formatCurrency(lang string, v float64, currency string) string {
+	return intlFormat("NumberFormat", lang, map[string]any{
+		"style":    "currency",
+		"currency": currency,
+	}, v, func() string {
+		return goFormatCurrency(lang, v, currency)
+	})
+}
+
+func formatDate(lang string, t time.Time, style Style) string {
+	return intlFormat("DateTimeFormat", lang, map[string]any{
+		"dateStyle": string(style),
+		"timeZone":  "UTC",
+	}, wallClockDate(t), func() string {
+		return goFormatDate(lang, t, style)
+	})
+}
+
+func formatTime(lang string, t time.Time, style Style) string {
+	return intlFormat("DateTimeFormat", lang, map[string]any{
+		"timeStyle": string(style),
+		"timeZone":  "UTC",
+	}, wallClockDate(t), func() string {
+		return goFormatTime(lang, t, style)
+	})
+}
+
+// intlFormat formats the given value with the given Intl formatter. It falls
+// back to the Go implementation when Intl is not available or rejects the
+// options.
+func intlFormat(formatter, lang string, options map[string]any, v any, fallback func() string) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fallback()
+		}
+	}()
+
+	intl := js.Global().Get("Intl")
+	if !intl.Truthy() {
+		return fallback()
+	}
+	return intl.Get(formatter).New(lang, options).Call("format", v).String()
+}
+
+// wallClockDate returns a JavaScript Date that represents the wall clock time
+// of the given time in UTC. Formatting it in the UTC time zone displays the
+// time in its own location, like the Go implementation.
+func wallClockDate(t time.Time) js.Value {
+	ms := js.Global().Get("Date").Call("UTC",
+		t.Year(),
+		int(t.Month())-1,
+		t.Day(),
+		t.Hour(),
+		t.Minute(),
+		t.Second(),
+		t.Nanosecond()/int(time.Millisecond),
+	)
+	return js.Global().Get("Date").New(ms)
+}
+
+// Without Intl, numbers in languages without built-in data are formatted with
+// the data of the CLDR root locale.
+
+func cldrFormatNumber(lang string, v float64, minFractionDigits, maxFractionDigits int) string {
+	return rootFormatData.number(v, minFractionDigits, maxFractionDigits)
+}
+
+func cldrFormatPercent(lang string, v float64) string {
+	return rootFormatData.number(v*100, 0, 0) + "%"
+}
+
+func cldrFormatCurrency(lang string, v float64, currency string) string {
+	digits := currencyDigits(currency)
+	n := rootFormatData.number(v, digits, digits)
+	return currency + "\u00a0" + n
+}
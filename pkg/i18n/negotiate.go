@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Negotiate returns the supported language that best matches the given
+// Accept-Language header value. Languages are matched by exact tag, then by
+// base language, e.g. "fr-CA" matches "fr" and "fr" matches "fr-FR". It
+// returns an empty string when no supported language matches.
+func Negotiate(acceptLanguage string, supported ...string) string {
+	for _, lang := range parseAcceptLanguage(acceptLanguage) {
+		if lang == "*" {
+			if len(supported) != 0 {
+				return supported[0]
+			}
+			continue
+		}
+
+		if match := matchLang(lang, supported); match != "" {
+			return match
+		}
+	}
+	return ""
+}
+
+func matchLang(lang string, supported []string) string {
+	for _, s := range supported {
+		if normalizeLang(s) == lang {
+			return s
+		}
+	}
+
+	base := baseLang(lang)
+	for _, s := range supported {
+		if normalizeLang(s) == base {
+			return s
+		}
+	}
+	for _, s := range supported {
+		if baseLang(normalizeLang(s)) == base {
+			return s
+		}
+	}
+	return ""
+}
+
+func parseAcceptLanguage(v string) []string {
+	type weightedLang struct {
+		lang   string
+		weight float64
+	}
+
+	var langs []weightedLang
+	for _, part := range strings.Split(v, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if lang = normalizeLang(strings.TrimSpace(lang)); lang == "" {
+			continue
+		}
+
+		weight := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if w, err := strconv.ParseFloat(q, 64); err == nil {
+					weight = w
+				}
+			}
+		}
+		if weight <= 0 {
+			continue
+		}
+		langs = append(langs, weightedLang{lang: lang, weight: weight})
+	}
+
+	sort.SliceStable(langs, func(a, b int) bool {
+		return langs[a].weight > langs[b].weight
+	})
+
+	res := make([]string, len(langs))
+	for i, l := range langs {
+		res[i] = l.lang
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	supported := []string{"en", "fr", "pt-BR"}
+
+	tests := []struct {
+		scenario       string
+		acceptLanguage string
+		expected       string
+	}{
+		{
+			scenario:       "exact match",
+			acceptLanguage: "fr",
+			expected:       "fr",
+		},
+		{
+			scenario:       "region falls back to base language",
+			acceptLanguage: "fr-CA,en;q=0.5",
+			expected:       "fr",
+		},
+		{
+			scenario:       "base language matches region",
+			acceptLanguage: "pt",
+			expected:       "pt-BR",
+		},
+		{
+			scenario:       "quality order",
+			acceptLanguage: "de;q=0.9, fr;q=0.7, en;q=0.8",
+			expected:       "en",
+		},
+		{
+			scenario:       "zero quality is ignored",
+			acceptLanguage: "fr;q=0, de",
+			expected:       "",
+		},
+		{
+			scenario:       "wildcard",
+			acceptLanguage: "de, *;q=0.1",
+			expected:       "en",
+		},
+		{
+			scenario:       "empty header",
+			acceptLanguage: "",
+			expected:       "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			require.Equal(t, test.expected, Negotiate(test.acceptLanguage, supported...))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package i18n
+
+// PluralCategory represents a CLDR plural category.
+type PluralCategory string
+
+// The CLDR plural categories.
+const (
+	Zero  PluralCategory = "zero"
+	One   PluralCategory = "one"
+	Two   PluralCategory = "two"
+	Few   PluralCategory = "few"
+	Many  PluralCategory = "many"
+	Other PluralCategory = "other"
+)
+
+// Plural returns the plural category of the given count in the given
+// language. Languages without a known rule use the English one.
+func Plural(lang string, n int) PluralCategory {
+	if n < 0 {
+		n = -n
+	}
+
+	switch pluralRule(lang) {
+	case "none":
+		return Other
+
+	case "french":
+		if n == 0 || n == 1 {
+			return One
+		}
+		return Other
+
+	case "slavic":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return One
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return Few
+		default:
+			return Many
+		}
+
+	case "polish":
+		switch {
+		case n == 1:
+			return One
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return Few
+		default:
+			return Many
+		}
+
+	case "czech":
+		switch {
+		case n == 1:
+			return One
+		case n >= 2 && n <= 4:
+			return Few
+		default:
+			return Other
+		}
+
+	case "hebrew":
+		switch n {
+		case 1:
+			return One
+		case 2:
+			return Two
+		default:
+			return Other
+		}
+
+	case "arabic":
+		switch {
+		case n == 0:
+			return Zero
+		case n == 1:
+			return One
+		case n == 2:
+			return Two
+		case n%100 >= 3 && n%100 <= 10:
+			return Few
+		case n%100 >= 11:
+			return Many
+		default:
+			return Other
+		}
+
+	default:
+		if n == 1 {
+			return One
+		}
+		return Other
+	}
+}
+
+// PluralCategories returns the plural categories used by the given language,
+// in the order of the gettext plural forms.
+func PluralCategories(lang string) []PluralCategory {
+	switch pluralRule(lang) {
+	case "none":
+		return []PluralCategory{Other}
+
+	case "slavic", "polish":
+		return []PluralCategory{One, Few, Many}
+
+	case "czech":
+		return []PluralCategory{One, Few, Other}
+
+	case "hebrew":
+		return []PluralCategory{One, Two, Other}
+
+	case "arabic":
+		return []PluralCategory{Zero, One, Two, Few, Many, Other}
+
+	default:
+		return []PluralCategory{One, Other}
+	}
+}
+
+func pluralRule(lang string) string {
+	switch baseLang(normalizeLang(lang)) {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms", "lo", "my":
+		return "none"
+
+	case "fr", "pt", "hi", "fa", "bn":
+		return "french"
+
+	case "ru", "uk", "be", "sr", "hr", "bs":
+		return "slavic"
+
+	case "pl":
+		return "polish"
+
+	case "cs", "sk":
+		return "czech"
+
+	case "he":
+		return "hebrew"
+
+	case "ar":
+		return "arabic"
+
+	default:
+		return "english"
+	}
+}
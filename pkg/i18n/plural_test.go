@@ -0,0 +1,52 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		lang     string
+		n        int
+		expected PluralCategory
+	}{
+		{lang: "en", n: 0, expected: Other},
+		{lang: "en", n: 1, expected: One},
+		{lang: "en-US", n: 2, expected: Other},
+		{lang: "fr", n: 0, expected: One},
+		{lang: "fr", n: 1, expected: One},
+		{lang: "fr", n: 2, expected: Other},
+		{lang: "ja", n: 1, expected: Other},
+		{lang: "ru", n: 1, expected: One},
+		{lang: "ru", n: 21, expected: One},
+		{lang: "ru", n: 3, expected: Few},
+		{lang: "ru", n: 13, expected: Many},
+		{lang: "ru", n: 5, expected: Many},
+		{lang: "pl", n: 1, expected: One},
+		{lang: "pl", n: 21, expected: Many},
+		{lang: "pl", n: 24, expected: Few},
+		{lang: "cs", n: 3, expected: Few},
+		{lang: "cs", n: 5, expected: Other},
+		{lang: "he", n: 2, expected: Two},
+		{lang: "ar", n: 0, expected: Zero},
+		{lang: "ar", n: 2, expected: Two},
+		{lang: "ar", n: 105, expected: Few},
+		{lang: "ar", n: 111, expected: Many},
+		{lang: "ar", n: 100, expected: Other},
+		{lang: "en", n: -1, expected: One},
+	}
+
+	for _, test := range tests {
+		t.Run(test.lang, func(t *testing.T) {
+			require.Equal(t, test.expected, Plural(test.lang, test.n), "n = %d", test.n)
+		})
+	}
+}
+
+func TestPluralCategories(t *testing.T) {
+	require.Equal(t, []PluralCategory{One, Other}, PluralCategories("en"))
+	require.Equal(t, []PluralCategory{One, Few, Many}, PluralCategories("ru"))
+	require.Equal(t, []PluralCategory{Other}, PluralCategories("zh-Hant"))
+}
@@ -0,0 +1,154 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// LoadPO loads the messages for the given language from a gettext PO file.
+// The msgid of an entry is used as the message key. Plural forms given with
+// msgstr[n] are mapped to the plural categories of the language, in order.
+// Message contexts are ignored, and entries without translation and fuzzy
+// entries are skipped.
+func (c *Catalog) LoadPO(lang string, b []byte) error {
+	categories := PluralCategories(lang)
+
+	var entry poEntry
+	var field *string
+	flush := func() {
+		if entry.id != "" && !entry.fuzzy {
+			if m, ok := entry.message(categories); ok {
+				c.Set(lang, entry.id, m)
+			}
+		}
+		entry = poEntry{}
+		field = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case text == "":
+			flush()
+
+		case strings.HasPrefix(text, "#,"):
+			if entry.id != "" || entry.str != nil {
+				flush()
+			}
+			entry.fuzzy = strings.Contains(text, "fuzzy")
+
+		case strings.HasPrefix(text, "#"):
+
+		case strings.HasPrefix(text, `"`):
+			if field == nil {
+				return errors.New("unexpected po string").WithTag("line", line)
+			}
+			s, err := strconv.Unquote(text)
+			if err != nil {
+				return errors.New("decoding po string failed").
+					WithTag("line", line).
+					Wrap(err)
+			}
+			*field += s
+
+		default:
+			keyword, value, _ := strings.Cut(text, " ")
+			s, err := strconv.Unquote(strings.TrimSpace(value))
+			if err != nil {
+				return errors.New("decoding po string failed").
+					WithTag("line", line).
+					WithTag("keyword", keyword).
+					Wrap(err)
+			}
+
+			switch {
+			case keyword == "msgctxt":
+				if entry.id != "" || entry.str != nil {
+					flush()
+				}
+				entry.ctx = s
+				field = &entry.ctx
+
+			case keyword == "msgid":
+				if entry.id != "" || entry.str != nil {
+					flush()
+				}
+				entry.id = s
+				field = &entry.id
+
+			case keyword == "msgid_plural":
+				entry.plural = s
+				field = &entry.plural
+
+			case keyword == "msgstr":
+				entry.str = append(entry.str, s)
+				field = &entry.str[len(entry.str)-1]
+
+			case strings.HasPrefix(keyword, "msgstr["):
+				i, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(keyword, "msgstr["), "]"))
+				if err != nil || i < 0 {
+					return errors.New("invalid po plural index").
+						WithTag("line", line).
+						WithTag("keyword", keyword)
+				}
+				for len(entry.str) <= i {
+					entry.str = append(entry.str, "")
+				}
+				entry.str[i] = s
+				field = &entry.str[i]
+
+			default:
+				return errors.New("unknown po keyword").
+					WithTag("line", line).
+					WithTag("keyword", keyword)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.New("reading po file failed").
+			WithTag("lang", lang).
+			Wrap(err)
+	}
+
+	flush()
+	return nil
+}
+
+type poEntry struct {
+	ctx    string
+	id     string
+	plural string
+	str    []string
+	fuzzy  bool
+}
+
+func (e poEntry) message(categories []PluralCategory) (Message, bool) {
+	var m Message
+	var translated bool
+
+	if e.plural == "" {
+		if len(e.str) == 0 || e.str[0] == "" {
+			return m, false
+		}
+		m.Other = e.str[0]
+		return m, true
+	}
+
+	for i, s := range e.str {
+		if s == "" || i >= len(categories) {
+			continue
+		}
+		m.setForm(categories[i], s)
+		translated = true
+	}
+	if m.Other == "" {
+		m.Other = e.str[len(e.str)-1]
+	}
+	return m, translated
+}
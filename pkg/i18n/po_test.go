@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogLoadPO(t *testing.T) {
+	po := `# French translations.
+msgid ""
+msgstr ""
+"Language: fr\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+#: main.go:12
+msgid "hello"
+msgstr "Bonjour %s"
+
+msgid "long"
+msgstr ""
+"Un message "
+"sur plusieurs lignes"
+
+msgid "items"
+msgid_plural "items"
+msgstr[0] "%d élément"
+msgstr[1] "%d éléments"
+
+#, fuzzy
+msgid "fuzzy"
+msgstr "Flou"
+
+msgid "untranslated"
+msgstr ""
+`
+
+	var c Catalog
+	err := c.LoadPO("fr", []byte(po))
+	require.NoError(t, err)
+
+	require.Equal(t, "Bonjour Maxence", c.Translate("fr", "hello", "Maxence"))
+	require.Equal(t, "Un message sur plusieurs lignes", c.Translate("fr", "long"))
+	require.Equal(t, "1 élément", c.Translate("fr", "items", 1))
+	require.Equal(t, "3 éléments", c.Translate("fr", "items", 3))
+	require.Equal(t, "fuzzy", c.Translate("fr", "fuzzy"))
+	require.Equal(t, "untranslated", c.Translate("fr", "untranslated"))
+	require.Equal(t, []string{"fr"}, c.Langs())
+}
+
+func TestCatalogLoadInvalidPO(t *testing.T) {
+	tests := []struct {
+		scenario string
+		po       string
+	}{
+		{
+			scenario: "unknown keyword",
+			po:       `msgfoo "bar"`,
+		},
+		{
+			scenario: "unquoted string",
+			po:       `msgid bar`,
+		},
+		{
+			scenario: "unexpected string",
+			po:       `"bar"`,
+		},
+		{
+			scenario: "invalid plural index",
+			po:       "msgid \"a\"\nmsgstr[x] \"b\"",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			var c Catalog
+			require.Error(t, c.LoadPO("fr", []byte(test.po)))
+		})
+	}
+}
@@ -0,0 +1,455 @@
+package realtime
+
+import (
+	"context"
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+	"github.com/whale1017/go-app/v10/pkg/webapi"
+)
+
+const (
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// Transport represents the protocol a client connects with.
+type Transport int
+
+// Constants that enumerate the transports a client can connect with.
+const (
+	// WebSocket connections receive and send messages.
+	WebSocket Transport = iota
+
+	// Server-Sent Events connections only receive messages.
+	ServerSentEvents
+)
+
+// Options describes how a client connects and reports received messages.
+type Options struct {
+	// The protocol to connect with. Defaults to WebSocket.
+	Transport Transport
+
+	// The WebSocket subprotocols.
+	Protocols []string
+
+	// The delay before the first reconnection attempt, doubled at each failed
+	// attempt up to MaxBackoff. Defaults to 500 milliseconds.
+	MinBackoff time.Duration
+
+	// The maximum delay between reconnection attempts. Defaults to 30
+	// seconds.
+	MaxBackoff time.Duration
+
+	// The number of consecutive failed reconnection attempts after which the
+	// client is closed. Reconnection is attempted indefinitely when 0.
+	MaxAttempts int
+
+	// The name of the action each received message is posted as, with the
+	// message as value. Defaults to the type of the message. The action is
+	// tagged with the message type and the URL of the connection.
+	Action string
+
+	// The name of the state set to each received message. No state is set
+	// when empty.
+	State string
+
+	// The name of the state set to the status of the connection each time it
+	// changes. No state is set when empty.
+	StatusState string
+
+	// The hub connected to in memory when the code runs on the server, such
+	// as during prerendering and tests. The client stays closed on the server
+	// when nil.
+	Local *Hub
+}
+
+// Client is a connection to a server that is automatically re-established
+// when it drops.
+type Client struct {
+	ctx     app.Context
+	url     string
+	options Options
+	cancel  func()
+
+	mutex  sync.Mutex
+	status Status
+	conn   conn
+}
+
+// conn represents an open connection.
+type conn interface {
+	// Send sends the given message.
+	Send(Message) error
+
+	// Wait blocks until the connection is closed or the context is done.
+	Wait(context.Context) error
+
+	// Close closes the connection.
+	Close()
+}
+
+type dialFunc func(ctx context.Context, url string, deliver func(Message)) (conn, error)
+
+// Connect connects to the given URL and returns the client that receives its
+// messages. The connection is opened and re-opened in the background until
+// the client is closed, which is usually done in the OnDismount method of the
+// component that connected it.
+func Connect(ctx app.Context, url string, o Options) *Client {
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = defaultMinBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = defaultMaxBackoff
+	}
+
+	c := &Client{
+		ctx:     ctx,
+		url:     url,
+		options: o,
+		status:  Connecting,
+	}
+
+	if app.IsServer && o.Local == nil {
+		c.status = Closed
+		c.cancel = func() {}
+		return c
+	}
+
+	dial, err := c.dialer()
+	if err != nil {
+		app.Log(errors.New("connecting realtime client failed").
+			WithTag("url", url).
+			Wrap(err))
+		c.status = Closed
+		c.cancel = func() {}
+		return c
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	go c.run(runCtx, dial)
+	return c
+}
+
+// Status returns the status of the connection.
+func (c *Client) Status() Status {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.status
+}
+
+// Send sends a message with the given type and the JSON encoding of the given
+// value as data. It returns an error when the connection is not open or when
+// the client connects with Server-Sent Events.
+func (c *Client) Send(typ string, v any) error {
+	m, err := NewMessage(typ, v)
+	if err != nil {
+		return errors.New("sending message failed").Wrap(err)
+	}
+
+	c.mutex.Lock()
+	conn := c.conn
+	c.mutex.Unlock()
+	if conn == nil {
+		return errors.New("sending message failed").
+			WithTag("url", c.url).
+			WithTag("type", typ).
+			Wrap(errors.New("connection is not open"))
+	}
+
+	if err := conn.Send(m); err != nil {
+		return errors.New("sending message failed").
+			WithTag("url", c.url).
+			WithTag("type", typ).
+			Wrap(err)
+	}
+	return nil
+}
+
+// Close closes the connection and stops reconnecting.
+func (c *Client) Close() {
+	c.cancel()
+	c.setStatus(Closed, nil)
+}
+
+func (c *Client) dialer() (dialFunc, error) {
+	if app.IsServer {
+		return c.options.Local.dialLocal, nil
+	}
+
+	switch c.options.Transport {
+	case ServerSentEvents:
+		if !app.Window().Get("EventSource").Truthy() {
+			return nil, errors.New("server-sent events are not supported")
+		}
+		return dialEventSource, nil
+
+	default:
+		if !app.Window().Get("WebSocket").Truthy() {
+			return nil, errors.New("websocket is not supported")
+		}
+		return c.dialWebSocket, nil
+	}
+}
+
+func (c *Client) run(ctx context.Context, dial dialFunc) {
+	for attempt := 0; ; {
+		conn, err := dial(ctx, c.url, c.deliver)
+		if err == nil {
+			attempt = 0
+			c.setStatus(Open, conn)
+			err = conn.Wait(ctx)
+			conn.Close()
+		}
+
+		if ctx.Err() != nil {
+			c.setStatus(Closed, nil)
+			return
+		}
+
+		attempt++
+		if c.options.MaxAttempts > 0 && attempt > c.options.MaxAttempts {
+			app.Log(errors.New("realtime connection failed").
+				WithTag("url", c.url).
+				WithTag("attempts", attempt).
+				Wrap(err))
+			c.setStatus(Closed, nil)
+			return
+		}
+		c.setStatus(Reconnecting, nil)
+
+		timer := time.NewTimer(backoff(attempt, c.options.MinBackoff, c.options.MaxBackoff))
+		select {
+		case <-timer.C:
+
+		case <-ctx.Done():
+			timer.Stop()
+			c.setStatus(Closed, nil)
+			return
+		}
+	}
+}
+
+func (c *Client) deliver(m Message) {
+	action := c.options.Action
+	if action == "" {
+		action = m.Type
+	}
+	c.ctx.NewActionWithValue(action, m,
+		app.T("type", m.Type),
+		app.T("url", c.url),
+	)
+
+	if c.options.State != "" {
+		c.ctx.SetState(c.options.State, m)
+	}
+}
+
+func (c *Client) setStatus(s Status, conn conn) {
+	c.mutex.Lock()
+	if c.status == Closed && s != Closed {
+		c.mutex.Unlock()
+		return
+	}
+	changed := c.status != s
+	c.status = s
+	c.conn = conn
+	c.mutex.Unlock()
+
+	if changed && c.options.StatusState != "" {
+		c.ctx.SetState(c.options.StatusState, s)
+	}
+}
+
+// backoff returns the delay before the given reconnection attempt: the
+// exponential delay between min and max, reduced by a random jitter of up to
+// a half.
+func backoff(attempt int, min, max time.Duration) time.Duration {
+	d := max
+	if attempt < 32 {
+		if exp := min << (attempt - 1); exp > 0 && exp < max {
+			d = exp
+		}
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// websocketURL returns the absolute WebSocket URL of the given URL, resolved
+// from the given page URL.
+func websocketURL(page *url.URL, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.New("parsing websocket url failed").
+			WithTag("url", rawURL).
+			Wrap(err)
+	}
+	if page != nil {
+		u = page.ResolveReference(u)
+	}
+
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+
+	case "https":
+		u.Scheme = "wss"
+	}
+	return u.String(), nil
+}
+
+type webSocketConn struct {
+	ws   *webapi.WebSocket
+	done chan error
+}
+
+func (c *Client) dialWebSocket(ctx context.Context, rawURL string, deliver func(Message)) (conn, error) {
+	u, err := websocketURL(app.Window().URL(), rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := webapi.DialWebSocket(ctx, u, c.options.Protocols...)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &webSocketConn{
+		ws:   ws,
+		done: make(chan error, 1),
+	}
+	go func() {
+		for {
+			msg, err := ws.Receive(ctx)
+			if err != nil {
+				conn.done <- err
+				return
+			}
+
+			m, err := decodeMessage(msg.Data)
+			if err != nil {
+				app.Log(errors.New("receiving websocket message failed").
+					WithTag("url", u).
+					Wrap(err))
+				continue
+			}
+			deliver(m)
+		}
+	}()
+	return conn, nil
+}
+
+func (c *webSocketConn) Send(m Message) error {
+	b, err := encodeMessage(m)
+	if err != nil {
+		return err
+	}
+	return c.ws.SendText(string(b))
+}
+
+func (c *webSocketConn) Wait(ctx context.Context) error {
+	select {
+	case err := <-c.done:
+		return err
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *webSocketConn) Close() {
+	c.ws.Close(1000, "")
+}
+
+type eventSourceConn struct {
+	source    app.Value
+	callbacks []app.Func
+	done      chan error
+}
+
+func dialEventSource(ctx context.Context, url string, deliver func(Message)) (conn, error) {
+	conn := &eventSourceConn{
+		source: app.Window().Get("EventSource").New(url),
+		done:   make(chan error, 1),
+	}
+
+	opened := make(chan struct{}, 1)
+	conn.on("open", func(app.Value) {
+		select {
+		case opened <- struct{}{}:
+		default:
+		}
+	})
+	conn.on("message", func(event app.Value) {
+		m, err := decodeMessage([]byte(event.Get("data").String()))
+		if err != nil {
+			app.Log(errors.New("receiving server-sent event failed").
+				WithTag("url", url).
+				Wrap(err))
+			return
+		}
+		deliver(m)
+	})
+	conn.on("error", func(app.Value) {
+		// The browser reconnection is disabled since the client reconnects
+		// with backoff.
+		conn.source.Call("close")
+		select {
+		case conn.done <- errors.New("server-sent events connection failed").WithTag("url", url):
+		default:
+		}
+	})
+
+	select {
+	case <-opened:
+		return conn, nil
+
+	case err := <-conn.done:
+		conn.Close()
+		return nil, err
+
+	case <-ctx.Done():
+		conn.Close()
+		return nil, ctx.Err()
+	}
+}
+
+func (c *eventSourceConn) on(event string, h func(app.Value)) {
+	fn := app.FuncOf(func(this app.Value, args []app.Value) any {
+		if len(args) != 0 {
+			h(args[0])
+		}
+		return nil
+	})
+	c.callbacks = append(c.callbacks, fn)
+	c.source.Set("on"+event, fn)
+}
+
+func (c *eventSourceConn) Send(m Message) error {
+	return errors.New("sending messages is not supported with server-sent events")
+}
+
+func (c *eventSourceConn) Wait(ctx context.Context) error {
+	select {
+	case err := <-c.done:
+		return err
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *eventSourceConn) Close() {
+	c.source.Call("close")
+	for _, event := range []string{"open", "message", "error"} {
+		c.source.Set("on"+event, nil)
+	}
+	for _, fn := range c.callbacks {
+		fn.Release()
+	}
+	c.callbacks = nil
+}
@@ -0,0 +1,196 @@
+//go:build !wasm
+// +build !wasm
+
+package realtime
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+type clientTestCompo struct {
+	app.Compo
+
+	hub      *Hub
+	client   *Client
+	messages []string
+}
+
+// OnPreRender connects the component since OnMount is only called in the
+// browser.
+func (c *clientTestCompo) OnPreRender(ctx app.Context) {
+	c.OnMount(ctx)
+}
+
+func (c *clientTestCompo) OnMount(ctx app.Context) {
+	c.client = Connect(ctx, "/live", Options{
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+		State:       "live",
+		StatusState: "live-status",
+		Local:       c.hub,
+	})
+	ctx.Handle("chat", func(ctx app.Context, a app.Action) {
+		var text string
+		if err := a.Value.(Message).Decode(&text); err != nil {
+			app.Log(err)
+			return
+		}
+		c.messages = append(c.messages, text)
+	})
+}
+
+func (c *clientTestCompo) OnDismount() {
+	c.client.Close()
+}
+
+func (c *clientTestCompo) Render() app.UI {
+	return app.Ul().Body(
+		app.Range(c.messages).Slice(func(i int) app.UI {
+			return app.Li().Text(c.messages[i])
+		}),
+	)
+}
+
+func TestClient(t *testing.T) {
+	received := make(chan Message, 1)
+	hub := &Hub{
+		OnMessage: func(r *http.Request, m Message) {
+			require.Equal(t, "/live", r.URL.Path)
+			received <- m
+		},
+	}
+	compo := &clientTestCompo{hub: hub}
+
+	e := app.NewTestEngine()
+	err := e.Load(compo)
+	require.NoError(t, err)
+	e.ConsumeAll()
+
+	require.Eventually(t, func() bool {
+		return compo.client.Status() == Open
+	}, time.Second, time.Millisecond)
+	e.ConsumeAll()
+
+	var status Status
+	e.GetState("live-status", &status)
+	require.Equal(t, Open, status)
+
+	t.Run("published messages are posted as actions and states", func(t *testing.T) {
+		err := hub.Publish("chat", "hello")
+		require.NoError(t, err)
+		e.ConsumeAll()
+
+		require.Equal(t, []string{"hello"}, compo.messages)
+		li, err := e.QueryText("hello")
+		require.NoError(t, err)
+		require.NotNil(t, li)
+
+		var m Message
+		e.GetState("live", &m)
+		require.Equal(t, "chat", m.Type)
+
+		var action app.Action
+		for _, a := range e.Actions() {
+			if a.Name == "chat" {
+				action = a
+			}
+		}
+		require.Equal(t, "/live", action.Tags.Get("url"))
+	})
+
+	t.Run("sent messages are received by the hub", func(t *testing.T) {
+		err := compo.client.Send("typing", true)
+		require.NoError(t, err)
+
+		m := <-received
+		require.Equal(t, "typing", m.Type)
+	})
+
+	t.Run("client reconnects when the connection drops", func(t *testing.T) {
+		compo.client.mutex.Lock()
+		conn := compo.client.conn
+		compo.client.mutex.Unlock()
+		conn.Close()
+
+		require.Eventually(t, func() bool {
+			compo.client.mutex.Lock()
+			defer compo.client.mutex.Unlock()
+			return compo.client.conn != nil && compo.client.conn != conn
+		}, time.Second, time.Millisecond)
+		require.Equal(t, 1, hub.Len())
+	})
+
+	t.Run("closed client disconnects", func(t *testing.T) {
+		compo.client.Close()
+		require.Equal(t, Closed, compo.client.Status())
+		require.Error(t, compo.client.Send("typing", true))
+
+		require.Eventually(t, func() bool {
+			return hub.Len() == 0
+		}, time.Second, time.Millisecond)
+	})
+}
+
+func TestClientOnServerWithoutLocalHub(t *testing.T) {
+	compo := &clientTestCompo{}
+
+	e := app.NewTestEngine()
+	err := e.Load(compo)
+	require.NoError(t, err)
+	e.ConsumeAll()
+
+	require.Equal(t, Closed, compo.client.Status())
+	require.Error(t, compo.client.Send("typing", true))
+	compo.client.Close()
+}
+
+func TestBackoff(t *testing.T) {
+	min := 100 * time.Millisecond
+	max := time.Second
+
+	utests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 1, max: min},
+		{attempt: 2, max: 2 * min},
+		{attempt: 3, max: 4 * min},
+		{attempt: 5, max: max},
+		{attempt: 100, max: max},
+	}
+
+	for _, u := range utests {
+		d := backoff(u.attempt, min, max)
+		require.GreaterOrEqual(t, int64(d), int64(u.max/2))
+		require.LessOrEqual(t, int64(d), int64(u.max))
+	}
+}
+
+func TestWebSocketURL(t *testing.T) {
+	page, _ := url.Parse("https://goapp.dev/chat")
+
+	utests := []struct {
+		url      string
+		expected string
+	}{
+		{url: "/live", expected: "wss://goapp.dev/live"},
+		{url: "live", expected: "wss://goapp.dev/live"},
+		{url: "http://localhost:8000/live", expected: "ws://localhost:8000/live"},
+		{url: "wss://example.com/live", expected: "wss://example.com/live"},
+	}
+
+	for _, u := range utests {
+		v, err := websocketURL(page, u.url)
+		require.NoError(t, err)
+		require.Equal(t, u.expected, v)
+	}
+
+	_, err := websocketURL(page, ":")
+	require.Error(t, err)
+}
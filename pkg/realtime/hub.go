@@ -0,0 +1,351 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	defaultKeepAlive  = 30 * time.Second
+	subscriberBacklog = 64
+)
+
+// Hub is an http.Handler that accepts client connections and publishes
+// messages to them. WebSocket upgrade requests are served over WebSocket,
+// other requests are served as Server-Sent Events.
+type Hub struct {
+	// The function called with the messages clients send over WebSocket, and
+	// the request that opened their connection.
+	OnMessage func(r *http.Request, m Message)
+
+	// The interval at which comments are sent to Server-Sent Events clients to
+	// keep their connection open. Defaults to 30 seconds.
+	KeepAlive time.Duration
+
+	// The function that reports whether a WebSocket connection is accepted
+	// from the origin of the given request. Defaults to accepting requests
+	// without Origin header and requests whose Origin header has the same host
+	// as the request, which prevents other sites from opening connections
+	// with the cookies of their visitors.
+	CheckOrigin func(r *http.Request) bool
+
+	mutex       sync.Mutex
+	subscribers map[*subscriber]struct{}
+	closed      bool
+}
+
+type subscriber struct {
+	deliver func(Message) bool
+	done    chan struct{}
+	once    sync.Once
+}
+
+func (s *subscriber) close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+// Publish sends a message with the given type and the JSON encoding of the
+// given value as data to all the connected clients. Clients that do not keep
+// up with published messages are disconnected.
+func (h *Hub) Publish(typ string, v any) error {
+	m, err := NewMessage(typ, v)
+	if err != nil {
+		return errors.New("publishing message failed").Wrap(err)
+	}
+
+	h.mutex.Lock()
+	subscribers := make([]*subscriber, 0, len(h.subscribers))
+	for s := range h.subscribers {
+		subscribers = append(subscribers, s)
+	}
+	h.mutex.Unlock()
+
+	for _, s := range subscribers {
+		if !s.deliver(m) {
+			h.unsubscribe(s)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of connected clients.
+func (h *Hub) Len() int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return len(h.subscribers)
+}
+
+// Close disconnects all the clients. Connections are no longer accepted once
+// the hub is closed.
+func (h *Hub) Close() {
+	h.mutex.Lock()
+	h.closed = true
+	subscribers := h.subscribers
+	h.subscribers = nil
+	h.mutex.Unlock()
+
+	for s := range subscribers {
+		s.close()
+	}
+}
+
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		websocket.Server{
+			Handshake: h.handshake,
+			Handler: func(conn *websocket.Conn) {
+				h.serveWebSocket(conn, r)
+			},
+		}.ServeHTTP(w, r)
+		return
+	}
+	h.serveEvents(w, r)
+}
+
+func (h *Hub) handshake(config *websocket.Config, r *http.Request) error {
+	checkOrigin := h.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = isSameOrigin
+	}
+
+	if !checkOrigin(r) {
+		return errors.New("websocket origin is not allowed").
+			WithTag("origin", r.Header.Get("Origin")).
+			WithTag("host", r.Host)
+	}
+	return nil
+}
+
+func isSameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+func (h *Hub) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	messages, s, err := h.subscribeQueue()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer h.unsubscribe(s)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(h.keepAlive())
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case m := <-messages:
+			b, err := encodeMessage(m)
+			if err != nil {
+				app.Log(err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", b); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-s.done:
+			return
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (h *Hub) serveWebSocket(conn *websocket.Conn, r *http.Request) {
+	defer conn.Close()
+
+	messages, s, err := h.subscribeQueue()
+	if err != nil {
+		return
+	}
+	defer h.unsubscribe(s)
+
+	go func() {
+		defer s.close()
+
+		for {
+			var b []byte
+			if err := websocket.Message.Receive(conn, &b); err != nil {
+				return
+			}
+
+			m, err := decodeMessage(b)
+			if err != nil {
+				app.Log(errors.New("receiving websocket message failed").
+					WithTag("url", r.URL.String()).
+					Wrap(err))
+				continue
+			}
+			h.receive(r, m)
+		}
+	}()
+
+	for {
+		select {
+		case m := <-messages:
+			b, err := encodeMessage(m)
+			if err != nil {
+				app.Log(err)
+				continue
+			}
+			if err := websocket.Message.Send(conn, string(b)); err != nil {
+				return
+			}
+
+		case <-s.done:
+			return
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (h *Hub) receive(r *http.Request, m Message) {
+	if h.OnMessage != nil {
+		h.OnMessage(r, m)
+	}
+}
+
+// subscribeQueue subscribes a client whose messages are queued in the
+// returned channel.
+func (h *Hub) subscribeQueue() (<-chan Message, *subscriber, error) {
+	messages := make(chan Message, subscriberBacklog)
+	s, err := h.subscribe(func(m Message) bool {
+		select {
+		case messages <- m:
+			return true
+		default:
+			return false
+		}
+	})
+	return messages, s, err
+}
+
+func (h *Hub) subscribe(deliver func(Message) bool) (*subscriber, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closed {
+		return nil, errors.New("hub is closed")
+	}
+
+	s := &subscriber{
+		deliver: deliver,
+		done:    make(chan struct{}),
+	}
+	if h.subscribers == nil {
+		h.subscribers = make(map[*subscriber]struct{})
+	}
+	h.subscribers[s] = struct{}{}
+	return s, nil
+}
+
+func (h *Hub) unsubscribe(s *subscriber) {
+	h.mutex.Lock()
+	delete(h.subscribers, s)
+	h.mutex.Unlock()
+	s.close()
+}
+
+func (h *Hub) keepAlive() time.Duration {
+	if h.KeepAlive <= 0 {
+		return defaultKeepAlive
+	}
+	return h.KeepAlive
+}
+
+// localConn is a connection to a hub in the same program, used in place of
+// network connections on the server.
+type localConn struct {
+	hub        *Hub
+	subscriber *subscriber
+	request    *http.Request
+}
+
+func (h *Hub) dialLocal(ctx context.Context, url string, deliver func(Message)) (conn, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.New("creating local request failed").Wrap(err)
+	}
+
+	s, err := h.subscribe(func(m Message) bool {
+		deliver(m)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &localConn{
+		hub:        h,
+		subscriber: s,
+		request:    r,
+	}, nil
+}
+
+func (c *localConn) Send(m Message) error {
+	select {
+	case <-c.subscriber.done:
+		return errors.New("connection is closed")
+
+	default:
+		c.hub.receive(c.request, m)
+		return nil
+	}
+}
+
+func (c *localConn) Wait(ctx context.Context) error {
+	select {
+	case <-c.subscriber.done:
+		return errors.New("connection closed by the hub")
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *localConn) Close() {
+	c.hub.unsubscribe(c.subscriber)
+}
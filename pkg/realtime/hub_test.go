@@ -0,0 +1,153 @@
+//go:build !wasm
+// +build !wasm
+
+package realtime
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestHubServerSentEvents(t *testing.T) {
+	hub := &Hub{KeepAlive: 10 * time.Millisecond}
+	s := httptest.NewServer(hub)
+	defer s.Close()
+
+	res, err := http.Get(s.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	events := bufio.NewReader(res.Body)
+	readData := func() string {
+		for {
+			line, err := events.ReadString('\n')
+			require.NoError(t, err)
+			if strings.HasPrefix(line, "data: ") {
+				return strings.TrimSpace(strings.TrimPrefix(line, "data: "))
+			}
+		}
+	}
+
+	require.Eventually(t, func() bool {
+		return hub.Len() == 1
+	}, time.Second, time.Millisecond)
+
+	err = hub.Publish("chat", "hello")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"chat","data":"hello"}`, readData())
+
+	hub.Close()
+	require.Eventually(t, func() bool {
+		_, err := events.ReadString('\n')
+		return err != nil
+	}, time.Second, time.Millisecond)
+
+	res, err = http.Get(s.URL)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+}
+
+func TestHubWebSocket(t *testing.T) {
+	received := make(chan Message, 1)
+	hub := &Hub{
+		OnMessage: func(r *http.Request, m Message) {
+			received <- m
+		},
+	}
+	s := httptest.NewServer(hub)
+	defer s.Close()
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(s.URL, "http"), "", s.URL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	err = websocket.Message.Send(conn, `{"type":"typing","data":true}`)
+	require.NoError(t, err)
+
+	m := <-received
+	require.Equal(t, "typing", m.Type)
+	require.JSONEq(t, "true", string(m.Data))
+
+	require.Eventually(t, func() bool {
+		return hub.Len() == 1
+	}, time.Second, time.Millisecond)
+
+	err = hub.Publish("chat", "hello")
+	require.NoError(t, err)
+
+	var b string
+	err = websocket.Message.Receive(conn, &b)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"chat","data":"hello"}`, b)
+
+	conn.Close()
+	require.Eventually(t, func() bool {
+		return hub.Len() == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestHubWebSocketOrigin(t *testing.T) {
+	hub := &Hub{}
+	s := httptest.NewServer(hub)
+	defer s.Close()
+	wsURL := "ws" + strings.TrimPrefix(s.URL, "http")
+
+	t.Run("foreign origin is rejected", func(t *testing.T) {
+		_, err := websocket.Dial(wsURL, "", "https://evil.example.com")
+		require.Error(t, err)
+		require.Zero(t, hub.Len())
+	})
+
+	t.Run("same origin is accepted", func(t *testing.T) {
+		conn, err := websocket.Dial(wsURL, "", s.URL)
+		require.NoError(t, err)
+		conn.Close()
+	})
+
+	t.Run("origin accepted by check origin is accepted", func(t *testing.T) {
+		hub.CheckOrigin = func(r *http.Request) bool {
+			return r.Header.Get("Origin") == "https://app.example.com"
+		}
+		defer func() {
+			hub.CheckOrigin = nil
+		}()
+
+		conn, err := websocket.Dial(wsURL, "", "https://app.example.com")
+		require.NoError(t, err)
+		conn.Close()
+
+		_, err = websocket.Dial(wsURL, "", s.URL)
+		require.Error(t, err)
+	})
+}
+
+func TestHubDisconnectsSlowClients(t *testing.T) {
+	hub := &Hub{}
+	messages, s, err := hub.subscribeQueue()
+	require.NoError(t, err)
+
+	for i := 0; i < subscriberBacklog; i++ {
+		require.NoError(t, hub.Publish("count", i))
+	}
+	require.Equal(t, 1, hub.Len())
+
+	require.NoError(t, hub.Publish("count", subscriberBacklog))
+	require.Zero(t, hub.Len())
+	require.Len(t, messages, subscriberBacklog)
+
+	select {
+	case <-s.done:
+	default:
+		t.Fatal("slow subscriber is not closed")
+	}
+}
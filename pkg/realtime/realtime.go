@@ -0,0 +1,145 @@
+// Package realtime provides real-time messaging between a go-app app and its
+// server, over WebSocket or Server-Sent Events.
+//
+// On the client, Connect opens a connection that is automatically
+// re-established with exponential backoff when it drops. Received messages are
+// posted as actions and can be stored in a state:
+//
+//	func (c *chat) OnMount(ctx app.Context) {
+//		c.client = realtime.Connect(ctx, "/live", realtime.Options{
+//			State: "live",
+//		})
+//		ctx.Handle("chat", c.onChatMessage)
+//	}
+//
+//	func (c *chat) OnDismount() {
+//		c.client.Close()
+//	}
+//
+//	func (c *chat) onChatMessage(ctx app.Context, a app.Action) {
+//		var msg ChatMessage
+//		if err := a.Value.(realtime.Message).Decode(&msg); err != nil {
+//			app.Log(err)
+//			return
+//		}
+//		c.messages = append(c.messages, msg)
+//	}
+//
+// On the server, a Hub accepts connections from clients and publishes
+// messages to them. It is served next to the app handler:
+//
+//	hub := &realtime.Hub{}
+//	http.Handle("/", &app.Handler{Name: "Chat"})
+//	http.Handle("/live", hub)
+//
+//	hub.Publish("chat", ChatMessage{Text: "hello"})
+//
+// Messages are framed as JSON objects with a type and data:
+//
+//	{"type": "chat", "data": {"text": "hello"}}
+//
+// Connections are not opened on the server. During prerendering and tests,
+// clients instead connect in memory to the hub set in Options.Local, if any.
+package realtime
+
+import (
+	"encoding/json"
+
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// Message represents a message exchanged between a client and a server.
+type Message struct {
+	// The type of the message, used as the name of the action the message is
+	// posted as.
+	Type string `json:"type"`
+
+	// The JSON encoded data of the message.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// NewMessage returns a message with the given type and the JSON encoding of
+// the given value as data.
+func NewMessage(typ string, v any) (Message, error) {
+	if typ == "" {
+		return Message{}, errors.New("message type is empty")
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Message{}, errors.New("encoding message data failed").
+			WithTag("type", typ).
+			Wrap(err)
+	}
+
+	return Message{
+		Type: typ,
+		Data: data,
+	}, nil
+}
+
+// Decode stores the data of the message into the value pointed to by v.
+func (m Message) Decode(v any) error {
+	if err := json.Unmarshal(m.Data, v); err != nil {
+		return errors.New("decoding message data failed").
+			WithTag("type", m.Type).
+			Wrap(err)
+	}
+	return nil
+}
+
+func encodeMessage(m Message) ([]byte, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, errors.New("encoding message failed").
+			WithTag("type", m.Type).
+			Wrap(err)
+	}
+	return b, nil
+}
+
+func decodeMessage(b []byte) (Message, error) {
+	var m Message
+	if err := json.Unmarshal(b, &m); err != nil {
+		return Message{}, errors.New("decoding message failed").Wrap(err)
+	}
+	if m.Type == "" {
+		return Message{}, errors.New("decoding message failed").
+			Wrap(errors.New("message type is empty"))
+	}
+	return m, nil
+}
+
+// Status represents the status of a client connection.
+type Status int
+
+// Constants that enumerate the statuses of a client connection.
+const (
+	// The client is opening its first connection.
+	Connecting Status = iota
+
+	// The connection is open and messages are received.
+	Open
+
+	// The connection dropped and the client is waiting to re-open it.
+	Reconnecting
+
+	// The client is closed and no longer receives messages.
+	Closed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Connecting:
+		return "connecting"
+
+	case Open:
+		return "open"
+
+	case Reconnecting:
+		return "reconnecting"
+
+	default:
+		return "closed"
+	}
+}
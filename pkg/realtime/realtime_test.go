@@ -0,0 +1,58 @@
+package realtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage(t *testing.T) {
+	t.Run("encode and decode", func(t *testing.T) {
+		type chat struct {
+			Text string `json:"text"`
+		}
+
+		m, err := NewMessage("chat", chat{Text: "hello"})
+		require.NoError(t, err)
+
+		b, err := encodeMessage(m)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"type":"chat","data":{"text":"hello"}}`, string(b))
+
+		m, err = decodeMessage(b)
+		require.NoError(t, err)
+		require.Equal(t, "chat", m.Type)
+
+		var v chat
+		err = m.Decode(&v)
+		require.NoError(t, err)
+		require.Equal(t, "hello", v.Text)
+	})
+
+	t.Run("message without type returns an error", func(t *testing.T) {
+		_, err := NewMessage("", nil)
+		require.Error(t, err)
+
+		_, err = decodeMessage([]byte(`{"data":42}`))
+		require.Error(t, err)
+	})
+
+	t.Run("invalid message returns an error", func(t *testing.T) {
+		_, err := NewMessage("chat", func() {})
+		require.Error(t, err)
+
+		_, err = decodeMessage([]byte(`{`))
+		require.Error(t, err)
+
+		var s string
+		err = Message{Type: "chat", Data: []byte(`42`)}.Decode(&s)
+		require.Error(t, err)
+	})
+}
+
+func TestStatusString(t *testing.T) {
+	require.Equal(t, "connecting", Connecting.String())
+	require.Equal(t, "open", Open.String())
+	require.Equal(t, "reconnecting", Reconnecting.String())
+	require.Equal(t, "closed", Closed.String())
+}
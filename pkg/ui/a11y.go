@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"github.com/google/uuid"
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+// focusableSelector matches the elements that can receive the keyboard focus.
+const focusableSelector = `a[href], area[href], button:not([disabled]), ` +
+	`input:not([disabled]):not([type="hidden"]), select:not([disabled]), ` +
+	`textarea:not([disabled]), iframe, [contenteditable="true"], ` +
+	`[tabindex]:not([tabindex="-1"])`
+
+func newElementID(component string) string {
+	return "goapp-" + component + "-" + uuid.NewString()
+}
+
+func activeElement() app.Value {
+	if app.IsServer {
+		return nil
+	}
+	return app.Window().Get("document").Get("activeElement")
+}
+
+// focus moves the keyboard focus to the element with the given id.
+func focus(id string) {
+	if app.IsServer {
+		return
+	}
+	if elem := app.Window().GetElementByID(id); elem.Truthy() {
+		elem.Call("focus")
+	}
+}
+
+// restoreFocus moves the keyboard focus back to the given element, typically
+// the one that was focused before a dialog or a menu is opened.
+func restoreFocus(elem app.Value) {
+	if app.IsServer || elem == nil || !elem.Truthy() {
+		return
+	}
+	elem.Call("focus")
+}
+
+func focusables(containerID string) []app.Value {
+	if app.IsServer {
+		return nil
+	}
+
+	container := app.Window().GetElementByID(containerID)
+	if !container.Truthy() {
+		return nil
+	}
+
+	elems := container.Call("querySelectorAll", focusableSelector)
+	focusables := make([]app.Value, 0, elems.Length())
+	for i := 0; i < elems.Length(); i++ {
+		focusables = append(focusables, elems.Index(i))
+	}
+	return focusables
+}
+
+// focusFirst moves the keyboard focus to the first focusable element within
+// the element with the given id. It reports whether an element is focused.
+func focusFirst(containerID string) bool {
+	elems := focusables(containerID)
+	if len(elems) == 0 {
+		return false
+	}
+	elems[0].Call("focus")
+	return true
+}
+
+// trapFocus keeps the keyboard focus within the element with the given id when
+// the tab key is pressed.
+func trapFocus(containerID string, e app.Event) {
+	elems := focusables(containerID)
+	if len(elems) == 0 {
+		e.PreventDefault()
+		return
+	}
+
+	first := elems[0]
+	last := elems[len(elems)-1]
+	active := activeElement()
+
+	if e.Get("shiftKey").Bool() {
+		if active.Equal(first) || active.Equal(app.Window().GetElementByID(containerID)) {
+			e.PreventDefault()
+			last.Call("focus")
+		}
+		return
+	}
+
+	if active.Equal(last) {
+		e.PreventDefault()
+		first.Call("focus")
+	}
+}
+
+// focusLeaves reports whether the focus moves out of the element that handles
+// the given focusout event.
+func focusLeaves(ctx app.Context, e app.Event) bool {
+	related := e.Get("relatedTarget")
+	return !related.Truthy() || !ctx.JSSrc().Call("contains", related).Bool()
+}
+
+// eventKey returns the key of a keyboard event.
+func eventKey(e app.Event) string {
+	return e.Get("key").String()
+}
+
+// nextIndex returns the index of the next enabled item from the given index in
+// the given direction, wrapping around. A negative index starts from the
+// beginning or the end of the items. It returns -1 when all the items are
+// disabled.
+func nextIndex(from, direction, count int, disabled func(int) bool) int {
+	if count == 0 {
+		return -1
+	}
+
+	i := from
+	if i < 0 && direction < 0 {
+		i = count
+	}
+	for n := 0; n < count; n++ {
+		i = (i + direction + count) % count
+		if !disabled(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// firstIndex returns the index of the first enabled item, or -1.
+func firstIndex(count int, disabled func(int) bool) int {
+	return nextIndex(-1, 1, count, disabled)
+}
+
+// lastIndex returns the index of the last enabled item, or -1.
+func lastIndex(count int, disabled func(int) bool) int {
+	return nextIndex(count, -1, count, disabled)
+}
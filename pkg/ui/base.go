@@ -24,8 +24,8 @@ type IBase interface {
 // Base creates a base for content.
 func Base() IBase {
 	return &base{
-		hpadding: BaseHPadding,
-		vpadding: 12,
+		hpadding: spacingVar(SpacingBaseHPadding, BaseHPadding),
+		vpadding: spacingVar(SpacingBaseVPadding, BaseVPadding),
 	}
 }
 
@@ -36,8 +36,8 @@ type base struct {
 	Iclass   string
 	Icontent []app.UI
 
-	hpadding int
-	vpadding int
+	hpadding string
+	vpadding string
 	width    int
 }
 
@@ -77,9 +77,9 @@ func (b *base) Render() app.UI {
 				Style("position", "relative").
 				Style("top", "0").
 				Style("left", "0").
-				Style("height", fmt.Sprintf("calc(100%s - %vpx)", "%", b.vpadding*2)).
-				Style("width", fmt.Sprintf("calc(100%s - %vpx)", "%", b.hpadding*2)).
-				Style("padding", fmt.Sprintf("%vpx %vpx", b.vpadding, b.hpadding)).
+				Style("height", fmt.Sprintf("calc(100%% - 2 * %s)", b.vpadding)).
+				Style("width", fmt.Sprintf("calc(100%% - 2 * %s)", b.hpadding)).
+				Style("padding", b.vpadding+" "+b.hpadding).
 				Style("overflow", "hidden").
 				Body(b.Icontent...),
 		)
@@ -88,9 +88,9 @@ func (b *base) Render() app.UI {
 func (b *base) resize(ctx app.Context) {
 	w, _ := ctx.Page().Size()
 	if w <= 480 {
-		b.hpadding = BaseMobileHPadding
+		b.hpadding = spacingVar(SpacingBaseMobileHPadding, BaseMobileHPadding)
 	} else {
-		b.hpadding = BaseHPadding
+		b.hpadding = spacingVar(SpacingBaseHPadding, BaseHPadding)
 	}
 
 	if w != b.width {
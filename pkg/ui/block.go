@@ -38,7 +38,7 @@ func Block() IBlock {
 		Ialignment:       stretch,
 		ImaxContentWidth: BlockContentWidth,
 		Ipadding:         true,
-		padding:          BlockPadding,
+		padding:          spacingVar(SpacingBlockPadding, BlockPadding),
 	}
 }
 
@@ -52,7 +52,7 @@ type block struct {
 	Ipadding         bool
 	Icontent         []app.UI
 
-	padding int
+	padding string
 	width   int
 }
 
@@ -110,8 +110,8 @@ func (b *block) Render() app.UI {
 		Center().
 		Content(
 			app.Div().
-				Style("padding", pxToString(b.padding)).
-				Style("width", fmt.Sprintf("calc(100%s - %vpx)", "%", b.padding*2)).
+				Style("padding", b.padding).
+				Style("width", fmt.Sprintf("calc(100%% - 2 * %s)", b.padding)).
 				Style("max-width", pxToString(b.ImaxContentWidth)).
 				Body(b.Icontent...),
 		)
@@ -137,12 +137,12 @@ func (b *block) Render() app.UI {
 func (b *block) resize(ctx app.Context) {
 	w, _ := ctx.Page().Size()
 
-	var padding int
+	padding := "0px"
 	if b.Ipadding {
 		if w <= 480 {
-			padding = BlockMobilePadding
+			padding = spacingVar(SpacingBlockMobilePadding, BlockMobilePadding)
 		} else {
-			padding = BlockPadding
+			padding = spacingVar(SpacingBlockPadding, BlockPadding)
 		}
 	}
 
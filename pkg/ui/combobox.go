@@ -0,0 +1,295 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+// ICombobox is the interface that describes a text input with a list of
+// suggested options.
+type ICombobox interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) ICombobox
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) ICombobox
+
+	// Sets the accessible name of the input.
+	Label(v string) ICombobox
+
+	// Sets the text displayed when the input is empty.
+	Placeholder(v string) ICombobox
+
+	// Sets the value.
+	Value(v string) ICombobox
+
+	// Sets the suggested options. Options that contain the typed text are
+	// displayed.
+	Options(v ...string) ICombobox
+
+	// Sets the handler called when the value is changed, either by typing or
+	// by selecting an option.
+	OnChange(h func(ctx app.Context, v string)) ICombobox
+}
+
+// Combobox creates a text input with a list of suggested options. The options
+// are navigated with the up and down arrow keys, selected with the enter key,
+// and the list is closed with the escape key.
+func Combobox() ICombobox {
+	return &combobox{
+		id:     newElementID("combobox"),
+		active: -1,
+	}
+}
+
+type combobox struct {
+	app.Compo
+
+	Iid          string
+	Iclass       string
+	Ilabel       string
+	Iplaceholder string
+	Ivalue       string
+	Ioptions     []string
+	IonChange    func(app.Context, string)
+
+	id          string
+	initialized bool
+	value       string
+	lastIValue  string
+	open        bool
+	active      int
+}
+
+func (c *combobox) ID(v string) ICombobox {
+	c.Iid = v
+	return c
+}
+
+func (c *combobox) Class(v string) ICombobox {
+	c.Iclass = app.AppendClass(c.Iclass, v)
+	return c
+}
+
+func (c *combobox) Label(v string) ICombobox {
+	c.Ilabel = v
+	return c
+}
+
+func (c *combobox) Placeholder(v string) ICombobox {
+	c.Iplaceholder = v
+	return c
+}
+
+func (c *combobox) Value(v string) ICombobox {
+	c.Ivalue = v
+	return c
+}
+
+func (c *combobox) Options(v ...string) ICombobox {
+	c.Ioptions = v
+	return c
+}
+
+func (c *combobox) OnChange(h func(ctx app.Context, v string)) ICombobox {
+	c.IonChange = h
+	return c
+}
+
+func (c *combobox) Render() app.UI {
+	if !c.initialized || c.Ivalue != c.lastIValue {
+		c.initialized = true
+		c.value = c.Ivalue
+		c.lastIValue = c.Ivalue
+	}
+
+	options := c.filteredOptions()
+	expanded := c.open && len(options) != 0
+
+	input := app.Input().
+		ID(c.inputID()).
+		Type("text").
+		Role("combobox").
+		Value(c.value).
+		AutoComplete(false).
+		Aria("autocomplete", "list").
+		Aria("expanded", expanded).
+		Aria("controls", c.listID()).
+		Style("width", "100%").
+		Style("box-sizing", "border-box").
+		OnInput(c.onInput).
+		OnKeyDown(c.onKeyDown).
+		OnBlur(c.onBlur)
+	if c.Iplaceholder != "" {
+		input = input.Placeholder(c.Iplaceholder)
+	}
+	if c.Ilabel != "" {
+		input = input.Aria("label", c.Ilabel)
+	}
+	if expanded && c.active >= 0 && c.active < len(options) {
+		input = input.Aria("activedescendant", c.optionID(c.active))
+	}
+
+	list := app.Ul().
+		ID(c.listID()).
+		Role("listbox").
+		Style("position", "absolute").
+		Style("top", "100%").
+		Style("left", "0").
+		Style("z-index", "1000").
+		Style("box-sizing", "border-box").
+		Style("width", "100%").
+		Style("max-height", "240px").
+		Style("overflow-y", "auto").
+		Style("margin", "4px 0 0").
+		Style("padding", spacingVar(SpacingSmall, BaseVPadding/2)+" 0").
+		Style("list-style", "none").
+		Style("border", "1px solid "+Var(ColorBorder)).
+		Style("border-radius", "4px").
+		Style("background-color", Var(ColorSurface)).
+		Style("color", Var(ColorText))
+	if c.Ilabel != "" {
+		list = list.Aria("label", c.Ilabel)
+	}
+	if !expanded {
+		list = list.Hidden(true)
+	}
+
+	return app.Div().
+		DataSet("goapp-ui", "combobox").
+		ID(c.Iid).
+		Class(c.Iclass).
+		Style("position", "relative").
+		Body(
+			input,
+			list.Body(
+				app.Range(options).Slice(func(i int) app.UI {
+					isActive := i == c.active
+
+					background := "transparent"
+					if isActive {
+						background = Var(ColorBorder)
+					}
+
+					return app.Li().
+						ID(c.optionID(i)).
+						Role("option").
+						Aria("selected", isActive).
+						Style("padding", spacingVar(SpacingSmall, BaseVPadding/2)+" "+spacingVar(SpacingMedium, BaseVPadding)).
+						Style("background-color", background).
+						Style("cursor", "pointer").
+						OnMouseDown(func(ctx app.Context, e app.Event) {
+							e.PreventDefault()
+							c.selectOption(ctx, options[i])
+						}).
+						Text(options[i])
+				}),
+			),
+		)
+}
+
+func (c *combobox) inputID() string {
+	return c.id + "-input"
+}
+
+func (c *combobox) listID() string {
+	return c.id + "-list"
+}
+
+func (c *combobox) optionID(i int) string {
+	return c.id + "-option-" + strconv.Itoa(i)
+}
+
+// filteredOptions returns the options that contain the current value, ignoring
+// case.
+func (c *combobox) filteredOptions() []string {
+	value := strings.ToLower(strings.TrimSpace(c.value))
+	if value == "" {
+		return c.Ioptions
+	}
+
+	options := make([]string, 0, len(c.Ioptions))
+	for _, o := range c.Ioptions {
+		if strings.Contains(strings.ToLower(o), value) {
+			options = append(options, o)
+		}
+	}
+	return options
+}
+
+func (c *combobox) setValue(ctx app.Context, v string) {
+	if v == c.value {
+		return
+	}
+
+	c.value = v
+	if c.IonChange != nil {
+		c.IonChange(ctx, v)
+	}
+}
+
+func (c *combobox) selectOption(ctx app.Context, v string) {
+	c.open = false
+	c.active = -1
+	c.setValue(ctx, v)
+}
+
+func (c *combobox) onInput(ctx app.Context, e app.Event) {
+	c.open = true
+	c.active = -1
+	c.setValue(ctx, ctx.JSSrc().Get("value").String())
+}
+
+func (c *combobox) onBlur(ctx app.Context, e app.Event) {
+	c.open = false
+	c.active = -1
+}
+
+func (c *combobox) onKeyDown(ctx app.Context, e app.Event) {
+	options := c.filteredOptions()
+	count := len(options)
+	noneDisabled := func(int) bool { return false }
+
+	switch eventKey(e) {
+	case "ArrowDown":
+		e.PreventDefault()
+		if !c.open {
+			c.open = true
+			if e.Get("altKey").Bool() {
+				return
+			}
+		}
+		c.active = nextIndex(c.active, 1, count, noneDisabled)
+
+	case "ArrowUp":
+		e.PreventDefault()
+		if !c.open {
+			c.open = true
+			c.active = lastIndex(count, noneDisabled)
+			return
+		}
+		if e.Get("altKey").Bool() {
+			c.open = false
+			return
+		}
+		c.active = nextIndex(c.active, -1, count, noneDisabled)
+
+	case "Enter":
+		if c.open && c.active >= 0 && c.active < count {
+			e.PreventDefault()
+			c.selectOption(ctx, options[c.active])
+		}
+
+	case "Escape":
+		if c.open {
+			e.PreventDefault()
+			e.StopImmediatePropagation()
+			c.open = false
+			c.active = -1
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package ui
+
+import (
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+// IDialog is the interface that describes a modal dialog.
+type IDialog interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IDialog
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IDialog
+
+	// Sets whether the dialog is displayed.
+	Open(v bool) IDialog
+
+	// Sets the title. It is used as the accessible name of the dialog.
+	Title(v string) IDialog
+
+	// Makes the dialog an alert dialog, announced as an urgent message that
+	// requires a response.
+	Alert() IDialog
+
+	// Sets the content.
+	Content(elems ...app.UI) IDialog
+
+	// Sets the handler called when the dialog is dismissed with the escape key
+	// or by clicking outside of it. The dialog is closed by setting Open to
+	// false.
+	OnClose(h app.EventHandler) IDialog
+}
+
+// Dialog creates a modal dialog. The keyboard focus is moved to the dialog
+// when it is opened, kept within it while it is displayed, and restored to the
+// previously focused element when it is closed.
+func Dialog() IDialog {
+	return &dialog{
+		id: newElementID("dialog"),
+	}
+}
+
+type dialog struct {
+	app.Compo
+
+	Iid      string
+	Iclass   string
+	Iopen    bool
+	Ititle   string
+	Ialert   bool
+	Icontent []app.UI
+	IonClose app.EventHandler
+
+	id          string
+	opened      bool
+	returnFocus app.Value
+}
+
+func (d *dialog) ID(v string) IDialog {
+	d.Iid = v
+	return d
+}
+
+func (d *dialog) Class(v string) IDialog {
+	d.Iclass = app.AppendClass(d.Iclass, v)
+	return d
+}
+
+func (d *dialog) Open(v bool) IDialog {
+	d.Iopen = v
+	return d
+}
+
+func (d *dialog) Title(v string) IDialog {
+	d.Ititle = v
+	return d
+}
+
+func (d *dialog) Alert() IDialog {
+	d.Ialert = true
+	return d
+}
+
+func (d *dialog) Content(elems ...app.UI) IDialog {
+	d.Icontent = app.FilterUIElems(elems...)
+	return d
+}
+
+func (d *dialog) OnClose(h app.EventHandler) IDialog {
+	d.IonClose = h
+	return d
+}
+
+func (d *dialog) OnMount(ctx app.Context) {
+	d.refresh(ctx)
+}
+
+func (d *dialog) OnUpdate(ctx app.Context) {
+	d.refresh(ctx)
+}
+
+func (d *dialog) OnDismount() {
+	if d.opened {
+		d.opened = false
+		restoreFocus(d.returnFocus)
+		d.returnFocus = nil
+	}
+}
+
+func (d *dialog) Render() app.UI {
+	if !d.Iopen {
+		return app.Div().
+			DataSet("goapp-ui", "dialog").
+			ID(d.Iid).
+			Class(d.Iclass).
+			Hidden(true)
+	}
+
+	role := "dialog"
+	if d.Ialert {
+		role = "alertdialog"
+	}
+
+	panel := app.Div().
+		ID(d.id).
+		Role(role).
+		Aria("modal", true).
+		TabIndex(-1).
+		Style("position", "relative").
+		Style("max-width", "calc(100% - 2 * "+spacingVar(SpacingLarge, BlockPadding)+")").
+		Style("max-height", "calc(100% - 2 * "+spacingVar(SpacingLarge, BlockPadding)+")").
+		Style("overflow", "auto").
+		Style("box-sizing", "border-box").
+		Style("padding", spacingVar(SpacingLarge, BlockPadding)).
+		Style("border-radius", "8px").
+		Style("background-color", Var(ColorSurface)).
+		Style("color", Var(ColorText)).
+		Style("outline", "none").
+		OnKeyDown(d.onKeyDown)
+	if d.Ititle != "" {
+		panel = panel.Aria("labelledby", d.titleID())
+	}
+
+	return app.Div().
+		DataSet("goapp-ui", "dialog").
+		ID(d.Iid).
+		Class(d.Iclass).
+		Style("position", "fixed").
+		Style("top", "0").
+		Style("left", "0").
+		Style("width", "100%").
+		Style("height", "100%").
+		Style("z-index", "1000").
+		Style("display", "flex").
+		Style("align-items", "center").
+		Style("justify-content", "center").
+		Style("background-color", "rgba(0, 0, 0, 0.4)").
+		OnClick(d.onBackdropClick).
+		Body(
+			panel.Body(append([]app.UI{
+				app.If(d.Ititle != "", func() app.UI {
+					return app.H2().
+						ID(d.titleID()).
+						Style("margin-top", "0").
+						Style("font-family", Var(FontFamilyHeading)).
+						Text(d.Ititle)
+				}),
+			}, d.Icontent...)...),
+		)
+}
+
+func (d *dialog) titleID() string {
+	return d.id + "-title"
+}
+
+func (d *dialog) refresh(ctx app.Context) {
+	if d.Iopen == d.opened {
+		return
+	}
+
+	d.opened = d.Iopen
+	if d.opened {
+		d.returnFocus = activeElement()
+		ctx.Defer(func(app.Context) {
+			if !focusFirst(d.id) {
+				focus(d.id)
+			}
+		})
+		return
+	}
+
+	restoreFocus(d.returnFocus)
+	d.returnFocus = nil
+}
+
+func (d *dialog) onKeyDown(ctx app.Context, e app.Event) {
+	switch eventKey(e) {
+	case "Escape":
+		e.StopImmediatePropagation()
+		d.close(ctx, e)
+
+	case "Tab":
+		trapFocus(d.id, e)
+	}
+}
+
+func (d *dialog) onBackdropClick(ctx app.Context, e app.Event) {
+	if e.Get("target").Equal(ctx.JSSrc()) {
+		d.close(ctx, e)
+	}
+}
+
+func (d *dialog) close(ctx app.Context, e app.Event) {
+	if d.IonClose != nil {
+		d.IonClose(ctx, e)
+	}
+}
@@ -0,0 +1,282 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+// MenuItem describes an item of a menu.
+type MenuItem struct {
+	// The text displayed by the item.
+	Label string
+
+	// Reports whether the item cannot be selected.
+	Disabled bool
+
+	// The handler called when the item is selected.
+	OnSelect app.EventHandler
+}
+
+// IMenu is the interface that describes a dropdown menu opened by a button.
+type IMenu interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IMenu
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IMenu
+
+	// Sets the text of the button that opens the menu.
+	Label(v string) IMenu
+
+	// Sets the items.
+	Items(v ...MenuItem) IMenu
+}
+
+// Menu creates a dropdown menu opened by a button. Once opened, the items are
+// navigated with the arrow, home and end keys or by typing the first character
+// of their label, selected with the enter and space keys, and the menu is
+// closed with the escape key.
+func Menu() IMenu {
+	return &menu{
+		id: newElementID("menu"),
+	}
+}
+
+type menu struct {
+	app.Compo
+
+	Iid    string
+	Iclass string
+	Ilabel string
+	Iitems []MenuItem
+
+	id     string
+	open   bool
+	active int
+}
+
+func (m *menu) ID(v string) IMenu {
+	m.Iid = v
+	return m
+}
+
+func (m *menu) Class(v string) IMenu {
+	m.Iclass = app.AppendClass(m.Iclass, v)
+	return m
+}
+
+func (m *menu) Label(v string) IMenu {
+	m.Ilabel = v
+	return m
+}
+
+func (m *menu) Items(v ...MenuItem) IMenu {
+	m.Iitems = v
+	return m
+}
+
+func (m *menu) Render() app.UI {
+	list := app.Ul().
+		ID(m.listID()).
+		Role("menu").
+		Aria("labelledby", m.buttonID()).
+		Style("position", "absolute").
+		Style("top", "100%").
+		Style("left", "0").
+		Style("z-index", "1000").
+		Style("min-width", "100%").
+		Style("margin", "4px 0 0").
+		Style("padding", spacingVar(SpacingSmall, BaseVPadding/2)+" 0").
+		Style("list-style", "none").
+		Style("border", "1px solid "+Var(ColorBorder)).
+		Style("border-radius", "4px").
+		Style("background-color", Var(ColorSurface)).
+		Style("color", Var(ColorText)).
+		OnKeyDown(m.onKeyDown)
+	if !m.open {
+		list = list.Hidden(true)
+	}
+
+	return app.Div().
+		DataSet("goapp-ui", "menu").
+		ID(m.Iid).
+		Class(m.Iclass).
+		Style("position", "relative").
+		Style("display", "inline-block").
+		On("focusout", m.onFocusOut).
+		Body(
+			app.Button().
+				ID(m.buttonID()).
+				Type("button").
+				Aria("haspopup", "menu").
+				Aria("expanded", m.open).
+				Aria("controls", m.listID()).
+				OnClick(m.onButtonClick).
+				OnKeyDown(m.onButtonKeyDown).
+				Text(m.Ilabel),
+			list.Body(
+				app.Range(m.Iitems).Slice(func(i int) app.UI {
+					item := m.Iitems[i]
+
+					li := app.Li().
+						ID(m.itemID(i)).
+						Role("menuitem").
+						TabIndex(-1).
+						Style("padding", spacingVar(SpacingSmall, BaseVPadding/2)+" "+spacingVar(SpacingMedium, BaseVPadding)).
+						Style("white-space", "nowrap").
+						Style("cursor", "pointer").
+						OnClick(func(ctx app.Context, e app.Event) {
+							m.selectItem(ctx, e, i)
+						}).
+						Text(item.Label)
+					if item.Disabled {
+						li = li.
+							Aria("disabled", true).
+							Style("cursor", "default").
+							Style("opacity", "0.5")
+					}
+					return li
+				}),
+			),
+		)
+}
+
+func (m *menu) buttonID() string {
+	return m.id + "-button"
+}
+
+func (m *menu) listID() string {
+	return m.id + "-list"
+}
+
+func (m *menu) itemID(i int) string {
+	return m.id + "-item-" + strconv.Itoa(i)
+}
+
+func (m *menu) disabled(i int) bool {
+	return m.Iitems[i].Disabled
+}
+
+func (m *menu) openAt(ctx app.Context, i int) {
+	if i < 0 {
+		return
+	}
+
+	m.open = true
+	m.activate(ctx, i)
+}
+
+func (m *menu) activate(ctx app.Context, i int) {
+	if i < 0 {
+		return
+	}
+
+	m.active = i
+	ctx.Defer(func(app.Context) {
+		focus(m.itemID(i))
+	})
+}
+
+func (m *menu) close(ctx app.Context, focusButton bool) {
+	m.open = false
+	if focusButton {
+		ctx.Defer(func(app.Context) {
+			focus(m.buttonID())
+		})
+	}
+}
+
+func (m *menu) selectItem(ctx app.Context, e app.Event, i int) {
+	item := m.Iitems[i]
+	if item.Disabled {
+		return
+	}
+
+	m.close(ctx, true)
+	if item.OnSelect != nil {
+		item.OnSelect(ctx, e)
+	}
+}
+
+func (m *menu) onButtonClick(ctx app.Context, e app.Event) {
+	if m.open {
+		m.close(ctx, false)
+		return
+	}
+	m.openAt(ctx, firstIndex(len(m.Iitems), m.disabled))
+}
+
+func (m *menu) onButtonKeyDown(ctx app.Context, e app.Event) {
+	switch eventKey(e) {
+	case "ArrowDown":
+		e.PreventDefault()
+		m.openAt(ctx, firstIndex(len(m.Iitems), m.disabled))
+
+	case "ArrowUp":
+		e.PreventDefault()
+		m.openAt(ctx, lastIndex(len(m.Iitems), m.disabled))
+	}
+}
+
+func (m *menu) onKeyDown(ctx app.Context, e app.Event) {
+	count := len(m.Iitems)
+
+	switch key := eventKey(e); key {
+	case "ArrowDown":
+		e.PreventDefault()
+		m.activate(ctx, nextIndex(m.active, 1, count, m.disabled))
+
+	case "ArrowUp":
+		e.PreventDefault()
+		m.activate(ctx, nextIndex(m.active, -1, count, m.disabled))
+
+	case "Home":
+		e.PreventDefault()
+		m.activate(ctx, firstIndex(count, m.disabled))
+
+	case "End":
+		e.PreventDefault()
+		m.activate(ctx, lastIndex(count, m.disabled))
+
+	case "Escape":
+		e.PreventDefault()
+		e.StopImmediatePropagation()
+		m.close(ctx, true)
+
+	case "Tab":
+		m.close(ctx, false)
+
+	case "Enter", " ":
+		e.PreventDefault()
+		if m.active >= 0 && m.active < count {
+			m.selectItem(ctx, e, m.active)
+		}
+
+	default:
+		if utf8.RuneCountInString(key) == 1 && key != " " {
+			m.activate(ctx, m.indexByPrefix(key))
+		}
+	}
+}
+
+// indexByPrefix returns the index of the next enabled item after the active one
+// whose label starts with the given prefix, or -1.
+func (m *menu) indexByPrefix(prefix string) int {
+	count := len(m.Iitems)
+	prefix = strings.ToLower(prefix)
+
+	return nextIndex(m.active, 1, count, func(i int) bool {
+		return m.disabled(i) || !strings.HasPrefix(strings.ToLower(m.Iitems[i].Label), prefix)
+	})
+}
+
+func (m *menu) onFocusOut(ctx app.Context, e app.Event) {
+	if m.open && focusLeaves(ctx, e) {
+		m.close(ctx, false)
+	}
+}
@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"strconv"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+// Tab describes a tab of a tabs component.
+type Tab struct {
+	// The text displayed by the tab.
+	Label string
+
+	// Reports whether the tab cannot be selected.
+	Disabled bool
+
+	// The content displayed when the tab is selected.
+	Content []app.UI
+}
+
+// ITabs is the interface that describes a set of tabs that display one panel
+// of content at a time.
+type ITabs interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) ITabs
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) ITabs
+
+	// Sets the accessible name of the tab list.
+	Label(v string) ITabs
+
+	// Sets the tabs.
+	Tabs(v ...Tab) ITabs
+
+	// Sets the index of the selected tab. Default is 0.
+	Selected(i int) ITabs
+
+	// Sets the handler called when a tab is selected by the user.
+	OnChange(h func(ctx app.Context, i int)) ITabs
+}
+
+// Tabs creates a set of tabs that display one panel of content at a time. Tabs
+// are selected by clicking on them or with the arrow, home and end keys once
+// the tab list is focused.
+func Tabs() ITabs {
+	return &tabs{
+		id: newElementID("tabs"),
+	}
+}
+
+type tabs struct {
+	app.Compo
+
+	Iid       string
+	Iclass    string
+	Ilabel    string
+	Itabs     []Tab
+	Iselected int
+	IonChange func(app.Context, int)
+
+	id            string
+	initialized   bool
+	selected      int
+	lastISelected int
+}
+
+func (t *tabs) ID(v string) ITabs {
+	t.Iid = v
+	return t
+}
+
+func (t *tabs) Class(v string) ITabs {
+	t.Iclass = app.AppendClass(t.Iclass, v)
+	return t
+}
+
+func (t *tabs) Label(v string) ITabs {
+	t.Ilabel = v
+	return t
+}
+
+func (t *tabs) Tabs(v ...Tab) ITabs {
+	t.Itabs = v
+	return t
+}
+
+func (t *tabs) Selected(i int) ITabs {
+	t.Iselected = i
+	return t
+}
+
+func (t *tabs) OnChange(h func(ctx app.Context, i int)) ITabs {
+	t.IonChange = h
+	return t
+}
+
+func (t *tabs) Render() app.UI {
+	if !t.initialized || t.Iselected != t.lastISelected {
+		t.initialized = true
+		t.selected = t.Iselected
+		t.lastISelected = t.Iselected
+	}
+
+	selected := t.selected
+	if selected < 0 || selected >= len(t.Itabs) {
+		selected = 0
+	}
+
+	tablist := app.Div().
+		Role("tablist").
+		Style("display", "flex").
+		Style("border-bottom", "1px solid "+Var(ColorBorder)).
+		OnKeyDown(t.onKeyDown)
+	if t.Ilabel != "" {
+		tablist = tablist.Aria("label", t.Ilabel)
+	}
+
+	return app.Div().
+		DataSet("goapp-ui", "tabs").
+		ID(t.Iid).
+		Class(t.Iclass).
+		Body(
+			tablist.Body(
+				app.Range(t.Itabs).Slice(func(i int) app.UI {
+					isSelected := i == selected
+
+					tabIndex := -1
+					if isSelected {
+						tabIndex = 0
+					}
+
+					borderColor := "transparent"
+					if isSelected {
+						borderColor = Var(ColorPrimary)
+					}
+
+					tab := app.Button().
+						ID(t.tabID(i)).
+						Type("button").
+						Role("tab").
+						Aria("selected", isSelected).
+						Aria("controls", t.panelID(i)).
+						TabIndex(tabIndex).
+						Style("padding", spacingVar(SpacingSmall, BaseVPadding/2)+" "+spacingVar(SpacingMedium, BaseVPadding)).
+						Style("border", "none").
+						Style("border-bottom", "2px solid "+borderColor).
+						Style("background", "none").
+						Style("color", "inherit").
+						Style("font", "inherit").
+						Style("cursor", "pointer").
+						OnClick(func(ctx app.Context, e app.Event) {
+							t.selectTab(ctx, i)
+						}).
+						Text(t.Itabs[i].Label)
+					if t.Itabs[i].Disabled {
+						tab = tab.
+							Aria("disabled", true).
+							Style("cursor", "default").
+							Style("opacity", "0.5")
+					}
+					return tab
+				}),
+			),
+			app.Range(t.Itabs).Slice(func(i int) app.UI {
+				panel := app.Div().
+					ID(t.panelID(i)).
+					Role("tabpanel").
+					Aria("labelledby", t.tabID(i)).
+					TabIndex(0).
+					Style("padding-top", spacingVar(SpacingMedium, BaseVPadding))
+				if i != selected {
+					return panel.Hidden(true)
+				}
+				return panel.Body(t.Itabs[i].Content...)
+			}),
+		)
+}
+
+func (t *tabs) tabID(i int) string {
+	return t.id + "-tab-" + strconv.Itoa(i)
+}
+
+func (t *tabs) panelID(i int) string {
+	return t.id + "-panel-" + strconv.Itoa(i)
+}
+
+func (t *tabs) disabled(i int) bool {
+	return t.Itabs[i].Disabled
+}
+
+func (t *tabs) selectTab(ctx app.Context, i int) {
+	if i < 0 || i >= len(t.Itabs) || t.disabled(i) {
+		return
+	}
+
+	ctx.Defer(func(app.Context) {
+		focus(t.tabID(i))
+	})
+	if i == t.selected {
+		return
+	}
+
+	t.selected = i
+	if t.IonChange != nil {
+		t.IonChange(ctx, i)
+	}
+}
+
+func (t *tabs) onKeyDown(ctx app.Context, e app.Event) {
+	count := len(t.Itabs)
+
+	switch eventKey(e) {
+	case "ArrowRight":
+		e.PreventDefault()
+		t.selectTab(ctx, nextIndex(t.selected, 1, count, t.disabled))
+
+	case "ArrowLeft":
+		e.PreventDefault()
+		t.selectTab(ctx, nextIndex(t.selected, -1, count, t.disabled))
+
+	case "Home":
+		e.PreventDefault()
+		t.selectTab(ctx, firstIndex(count, t.disabled))
+
+	case "End":
+		e.PreventDefault()
+		t.selectTab(ctx, lastIndex(count, t.disabled))
+	}
+}
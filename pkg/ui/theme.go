@@ -0,0 +1,408 @@
+package ui
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+// ThemeState is the state that stores the theme mode selected with
+// SetThemeMode. It is persisted in the local storage.
+const ThemeState = "/goapp/ui/theme"
+
+// ThemeMode represents the color scheme used by theme providers.
+type ThemeMode string
+
+// The theme modes.
+const (
+	// SystemMode follows the prefers-color-scheme setting of the user.
+	SystemMode ThemeMode = "system"
+
+	// LightMode always uses the light theme.
+	LightMode ThemeMode = "light"
+
+	// DarkMode always uses the dark theme.
+	DarkMode ThemeMode = "dark"
+)
+
+// SetThemeMode switches the theme mode of all the theme providers and persists
+// it so that it is restored when the app is loaded again.
+func SetThemeMode(ctx app.Context, v ThemeMode) {
+	ctx.SetState(ThemeState, v).Persist()
+}
+
+// GetThemeMode returns the theme mode selected with SetThemeMode. It returns
+// SystemMode when no mode has been selected.
+func GetThemeMode(ctx app.Context) ThemeMode {
+	var mode ThemeMode
+	ctx.GetState(ThemeState, &mode)
+	if mode == "" {
+		mode = SystemMode
+	}
+	return mode
+}
+
+// The theme tokens. Each token is a CSS custom property set by theme providers
+// and can be used in styles with Var, e.g. Var(ColorPrimary).
+const (
+	ColorBackground    = "color-background"
+	ColorSurface       = "color-surface"
+	ColorText          = "color-text"
+	ColorTextSecondary = "color-text-secondary"
+	ColorPrimary       = "color-primary"
+	ColorOnPrimary     = "color-on-primary"
+	ColorBorder        = "color-border"
+
+	SpacingSmall              = "spacing-small"
+	SpacingMedium             = "spacing-medium"
+	SpacingLarge              = "spacing-large"
+	SpacingBlockPadding       = "spacing-block-padding"
+	SpacingBlockMobilePadding = "spacing-block-mobile-padding"
+	SpacingBaseHPadding       = "spacing-base-h-padding"
+	SpacingBaseMobileHPadding = "spacing-base-mobile-h-padding"
+	SpacingBaseVPadding       = "spacing-base-v-padding"
+
+	FontFamily        = "font-family"
+	FontFamilyHeading = "font-family-heading"
+	FontSize          = "font-size"
+	LineHeight        = "line-height"
+)
+
+// Var returns the CSS var() function that refers to the given theme token.
+func Var(token string) string {
+	return "var(" + tokenProperty(token) + ")"
+}
+
+func tokenProperty(token string) string {
+	return "--goapp-ui-" + token
+}
+
+func spacingVar(token string, px int) string {
+	return "var(" + tokenProperty(token) + ", " + pxToString(px) + ")"
+}
+
+// Theme describes the colors, spacing and typography of the ui components.
+type Theme struct {
+	// The colors.
+	Colors ThemeColors
+
+	// The spacing. Zero values default to the package spacing variables,
+	// such as BlockPadding.
+	Spacing ThemeSpacing
+
+	// The typography.
+	Typography ThemeTypography
+}
+
+// ThemeColors describes the colors of a theme.
+type ThemeColors struct {
+	// The color of OS-level UI elements, such as the browser toolbar, when
+	// the theme is active. Page.SetThemeColor is not called when empty.
+	Theme string
+
+	// The page background color.
+	Background string
+
+	// The background color of elevated elements, such as menus and dialogs.
+	Surface string
+
+	// The text color.
+	Text string
+
+	// The color of secondary text, such as hints and captions.
+	TextSecondary string
+
+	// The accent color of interactive elements.
+	Primary string
+
+	// The color of text displayed on the primary color.
+	OnPrimary string
+
+	// The color of borders and separators.
+	Border string
+}
+
+// ThemeSpacing describes the spacing of a theme, in px.
+type ThemeSpacing struct {
+	Small              int
+	Medium             int
+	Large              int
+	BlockPadding       int
+	BlockMobilePadding int
+	BaseHPadding       int
+	BaseMobileHPadding int
+	BaseVPadding       int
+}
+
+// ThemeTypography describes the fonts of a theme.
+type ThemeTypography struct {
+	// The font family of the text.
+	FontFamily string
+
+	// The font family of the headings. Defaults to FontFamily.
+	HeadingFontFamily string
+
+	// The font size of the text in px.
+	FontSize int
+
+	// The line height of the text, e.g. "1.5".
+	LineHeight string
+}
+
+var (
+	// The default light theme.
+	DefaultLightTheme = Theme{
+		Colors: ThemeColors{
+			Theme:         "#ffffff",
+			Background:    "#ffffff",
+			Surface:       "#f4f4f5",
+			Text:          "#1f1f1f",
+			TextSecondary: "#5f6368",
+			Primary:       "#1a73e8",
+			OnPrimary:     "#ffffff",
+			Border:        "#dadce0",
+		},
+		Typography: ThemeTypography{
+			FontFamily: "system-ui, -apple-system, 'Segoe UI', Roboto, sans-serif",
+			FontSize:   16,
+			LineHeight: "1.5",
+		},
+	}
+
+	// The default dark theme.
+	DefaultDarkTheme = Theme{
+		Colors: ThemeColors{
+			Theme:         "#2d2c2c",
+			Background:    "#2d2c2c",
+			Surface:       "#3a3939",
+			Text:          "#e8eaed",
+			TextSecondary: "#9aa0a6",
+			Primary:       "#8ab4f8",
+			OnPrimary:     "#202124",
+			Border:        "#5f6368",
+		},
+		Typography: ThemeTypography{
+			FontFamily: "system-ui, -apple-system, 'Segoe UI', Roboto, sans-serif",
+			FontSize:   16,
+			LineHeight: "1.5",
+		},
+	}
+)
+
+func (t Theme) properties() []style {
+	px := func(v, defaultValue int) string {
+		if v == 0 {
+			v = defaultValue
+		}
+		return pxToString(v)
+	}
+
+	headingFontFamily := t.Typography.HeadingFontFamily
+	if headingFontFamily == "" {
+		headingFontFamily = t.Typography.FontFamily
+	}
+
+	properties := []style{
+		{key: ColorBackground, value: t.Colors.Background},
+		{key: ColorSurface, value: t.Colors.Surface},
+		{key: ColorText, value: t.Colors.Text},
+		{key: ColorTextSecondary, value: t.Colors.TextSecondary},
+		{key: ColorPrimary, value: t.Colors.Primary},
+		{key: ColorOnPrimary, value: t.Colors.OnPrimary},
+		{key: ColorBorder, value: t.Colors.Border},
+		{key: SpacingSmall, value: px(t.Spacing.Small, BaseVPadding/2)},
+		{key: SpacingMedium, value: px(t.Spacing.Medium, BaseVPadding)},
+		{key: SpacingLarge, value: px(t.Spacing.Large, BlockPadding)},
+		{key: SpacingBlockPadding, value: px(t.Spacing.BlockPadding, BlockPadding)},
+		{key: SpacingBlockMobilePadding, value: px(t.Spacing.BlockMobilePadding, BlockMobilePadding)},
+		{key: SpacingBaseHPadding, value: px(t.Spacing.BaseHPadding, BaseHPadding)},
+		{key: SpacingBaseMobileHPadding, value: px(t.Spacing.BaseMobileHPadding, BaseMobileHPadding)},
+		{key: SpacingBaseVPadding, value: px(t.Spacing.BaseVPadding, BaseVPadding)},
+		{key: FontFamily, value: t.Typography.FontFamily},
+		{key: FontFamilyHeading, value: headingFontFamily},
+		{key: LineHeight, value: t.Typography.LineHeight},
+	}
+	if t.Typography.FontSize != 0 {
+		properties = append(properties, style{key: FontSize, value: pxToString(t.Typography.FontSize)})
+	}
+	return properties
+}
+
+func (t Theme) css(colorScheme string) string {
+	var b strings.Builder
+	for _, p := range t.properties() {
+		if p.value != "" {
+			fmt.Fprintf(&b, "%s:%s;", tokenProperty(p.key), p.value)
+		}
+	}
+	b.WriteString("color-scheme:" + colorScheme + ";")
+	return b.String()
+}
+
+// IThemeProvider is the interface that describes a component that applies a
+// light or dark theme to its content.
+type IThemeProvider interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) IThemeProvider
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) IThemeProvider
+
+	// Sets the light theme. Default is DefaultLightTheme.
+	Light(v Theme) IThemeProvider
+
+	// Sets the dark theme. Default is DefaultDarkTheme.
+	Dark(v Theme) IThemeProvider
+
+	// Sets the content.
+	Content(v ...app.UI) IThemeProvider
+}
+
+// ThemeProvider creates a component that sets the theme tokens of its content
+// and the page theme color. The light or dark theme is selected from the mode
+// set with SetThemeMode, which defaults to the system color scheme.
+func ThemeProvider() IThemeProvider {
+	return &themeProvider{
+		Ilight: DefaultLightTheme,
+		Idark:  DefaultDarkTheme,
+	}
+}
+
+type themeProvider struct {
+	app.Compo
+
+	Iid      string
+	Iclass   string
+	Ilight   Theme
+	Idark    Theme
+	Icontent []app.UI
+
+	mode          ThemeMode
+	systemDark    bool
+	colorScheme   app.Value
+	onColorScheme app.Func
+}
+
+func (p *themeProvider) ID(v string) IThemeProvider {
+	p.Iid = v
+	return p
+}
+
+func (p *themeProvider) Class(v string) IThemeProvider {
+	p.Iclass = app.AppendClass(p.Iclass, v)
+	return p
+}
+
+func (p *themeProvider) Light(v Theme) IThemeProvider {
+	p.Ilight = v
+	return p
+}
+
+func (p *themeProvider) Dark(v Theme) IThemeProvider {
+	p.Idark = v
+	return p
+}
+
+func (p *themeProvider) Content(v ...app.UI) IThemeProvider {
+	p.Icontent = app.FilterUIElems(v...)
+	return p
+}
+
+func (p *themeProvider) OnMount(ctx app.Context) {
+	ctx.ObserveState(ThemeState, &p.mode).OnChange(func() {
+		p.setThemeColor(ctx)
+	})
+
+	if colorScheme := app.Window().Call("matchMedia", "(prefers-color-scheme: dark)"); colorScheme.Truthy() {
+		p.colorScheme = colorScheme
+		p.systemDark = colorScheme.Get("matches").Bool()
+		p.onColorScheme = app.FuncOf(func(this app.Value, args []app.Value) any {
+			systemDark := args[0].Get("matches").Bool()
+			ctx.Dispatch(func(ctx app.Context) {
+				p.systemDark = systemDark
+				p.setThemeColor(ctx)
+			})
+			return nil
+		})
+		colorScheme.Call("addEventListener", "change", p.onColorScheme)
+	}
+
+	p.setThemeColor(ctx)
+}
+
+func (p *themeProvider) OnUpdate(ctx app.Context) {
+	p.setThemeColor(ctx)
+}
+
+func (p *themeProvider) OnDismount() {
+	if p.onColorScheme != nil {
+		p.colorScheme.Call("removeEventListener", "change", p.onColorScheme)
+		p.onColorScheme.Release()
+		p.onColorScheme = nil
+	}
+}
+
+func (p *themeProvider) Render() app.UI {
+	mode := p.mode
+	if mode == "" {
+		mode = SystemMode
+	}
+
+	id := p.themeID()
+	selector := "[data-goapp-ui-theme=" + id + "]"
+
+	return app.Div().
+		DataSet("goapp-ui", "theme-provider").
+		DataSet("goapp-ui-theme", id).
+		DataSet("goapp-ui-theme-mode", mode).
+		ID(p.Iid).
+		Class(p.Iclass).
+		Style("background-color", Var(ColorBackground)).
+		Style("color", Var(ColorText)).
+		Style("font-family", Var(FontFamily)).
+		Style("font-size", Var(FontSize)).
+		Style("line-height", Var(LineHeight)).
+		Body(append([]app.UI{
+			app.Raw("<style>" +
+				selector + "{" + p.Ilight.css("light") + "}" +
+				selector + "[data-goapp-ui-theme-mode=dark]{" + p.Idark.css("dark") + "}" +
+				"@media (prefers-color-scheme: dark){" +
+				selector + "[data-goapp-ui-theme-mode=system]{" + p.Idark.css("dark") + "}" +
+				"}" +
+				"</style>"),
+		}, p.Icontent...)...)
+}
+
+func (p *themeProvider) themeID() string {
+	h := fnv.New32a()
+	h.Write([]byte(p.Ilight.css("light")))
+	h.Write([]byte(p.Idark.css("dark")))
+	return "t" + strconv.FormatUint(uint64(h.Sum32()), 36)
+}
+
+func (p *themeProvider) active() Theme {
+	switch p.mode {
+	case LightMode:
+		return p.Ilight
+
+	case DarkMode:
+		return p.Idark
+
+	default:
+		if p.systemDark {
+			return p.Idark
+		}
+		return p.Ilight
+	}
+}
+
+func (p *themeProvider) setThemeColor(ctx app.Context) {
+	if color := p.active().Colors.Theme; color != "" {
+		ctx.Page().SetThemeColor(color)
+	}
+}
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+// ITooltip is the interface that describes a tooltip that displays a text when
+// its content is hovered or focused.
+type ITooltip interface {
+	app.UI
+
+	// Sets the ID.
+	ID(v string) ITooltip
+
+	// Sets the class. Multiple classes can be defined by successive calls.
+	Class(v string) ITooltip
+
+	// Sets the text displayed by the tooltip.
+	Text(v string) ITooltip
+
+	// Sets the content that triggers the tooltip. The text is set as the
+	// accessible description of the first element, which should be focusable.
+	Content(elems ...app.UI) ITooltip
+}
+
+// Tooltip creates a tooltip that displays a text when its content is hovered or
+// focused. It is hidden with the escape key.
+func Tooltip() ITooltip {
+	return &tooltip{
+		id: newElementID("tooltip"),
+	}
+}
+
+type tooltip struct {
+	app.Compo
+
+	Iid      string
+	Iclass   string
+	Itext    string
+	Icontent []app.UI
+
+	id      string
+	visible bool
+}
+
+func (t *tooltip) ID(v string) ITooltip {
+	t.Iid = v
+	return t
+}
+
+func (t *tooltip) Class(v string) ITooltip {
+	t.Iclass = app.AppendClass(t.Iclass, v)
+	return t
+}
+
+func (t *tooltip) Text(v string) ITooltip {
+	t.Itext = v
+	return t
+}
+
+func (t *tooltip) Content(elems ...app.UI) ITooltip {
+	t.Icontent = app.FilterUIElems(elems...)
+	return t
+}
+
+func (t *tooltip) OnMount(ctx app.Context) {
+	t.describe(ctx)
+}
+
+func (t *tooltip) OnUpdate(ctx app.Context) {
+	t.describe(ctx)
+}
+
+func (t *tooltip) Render() app.UI {
+	tip := app.Span().
+		ID(t.id).
+		Role("tooltip").
+		Style("position", "absolute").
+		Style("bottom", "100%").
+		Style("left", "50%").
+		Style("transform", "translateX(-50%)").
+		Style("z-index", "1000").
+		Style("margin-bottom", "4px").
+		Style("padding", "4px 8px").
+		Style("border-radius", "4px").
+		Style("background-color", Var(ColorText)).
+		Style("color", Var(ColorBackground)).
+		Style("font-size", "0.875em").
+		Style("white-space", "nowrap").
+		Style("pointer-events", "none").
+		Text(t.Itext)
+	if !t.visible {
+		tip = tip.Hidden(true)
+	}
+
+	return app.Span().
+		DataSet("goapp-ui", "tooltip").
+		ID(t.Iid).
+		Class(t.Iclass).
+		Style("position", "relative").
+		Style("display", "inline-block").
+		OnMouseEnter(t.show).
+		OnMouseLeave(t.hide).
+		On("focusin", t.show).
+		On("focusout", t.hide).
+		OnKeyDown(t.onKeyDown).
+		Body(append(append([]app.UI{}, t.Icontent...), tip)...)
+}
+
+// describe sets the tooltip as the accessible description of the first element
+// of the content once it is rendered.
+func (t *tooltip) describe(ctx app.Context) {
+	ctx.Defer(func(app.Context) {
+		tip := app.Window().GetElementByID(t.id)
+		if !tip.Truthy() {
+			return
+		}
+
+		if elem := tip.Get("parentElement").Get("firstElementChild"); elem.Truthy() && !elem.Equal(tip) {
+			elem.Call("setAttribute", "aria-describedby", t.id)
+		}
+	})
+}
+
+func (t *tooltip) show(ctx app.Context, e app.Event) {
+	t.visible = t.Itext != ""
+}
+
+func (t *tooltip) hide(ctx app.Context, e app.Event) {
+	t.visible = false
+}
+
+func (t *tooltip) onKeyDown(ctx app.Context, e app.Event) {
+	if t.visible && eventKey(e) == "Escape" {
+		t.visible = false
+	}
+}
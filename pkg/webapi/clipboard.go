@@ -0,0 +1,37 @@
+package webapi
+
+import (
+	"context"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// ReadClipboardText returns the text content of the system clipboard. The
+// browser may prompt the user for permission.
+func ReadClipboardText(ctx context.Context) (string, error) {
+	clipboard, err := global("clipboard", "navigator", "clipboard")
+	if err != nil {
+		return "", err
+	}
+
+	v, err := app.Await(ctx, clipboard.Call("readText"))
+	if err != nil {
+		return "", errors.New("reading clipboard text failed").Wrap(err)
+	}
+	return v.String(), nil
+}
+
+// WriteClipboardText replaces the content of the system clipboard with the
+// given text.
+func WriteClipboardText(ctx context.Context, text string) error {
+	clipboard, err := global("clipboard", "navigator", "clipboard")
+	if err != nil {
+		return err
+	}
+
+	if _, err := app.Await(ctx, clipboard.Call("writeText", text)); err != nil {
+		return errors.New("writing clipboard text failed").Wrap(err)
+	}
+	return nil
+}
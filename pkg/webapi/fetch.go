@@ -0,0 +1,123 @@
+package webapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// Request describes an HTTP request sent with the Fetch API.
+type Request struct {
+	// The HTTP method. Defaults to GET.
+	Method string
+
+	// The URL of the requested resource. Relative URLs are resolved from the
+	// current page.
+	URL string
+
+	// The headers sent with the request.
+	Header http.Header
+
+	// The body of the request.
+	Body []byte
+
+	// Whether cookies and credentials are sent with the request: "omit",
+	// "same-origin" or "include". Defaults to "same-origin".
+	Credentials string
+}
+
+// Response describes the response to a request sent with the Fetch API.
+type Response struct {
+	// The URL of the response, after redirections.
+	URL string
+
+	// The HTTP status code and its message.
+	StatusCode int
+	Status     string
+
+	// The headers of the response that are exposed to the page.
+	Header http.Header
+
+	// The body of the response.
+	Body []byte
+}
+
+// Fetch sends the given request and returns its response. Responses with an
+// error status code are returned without error. The request is aborted when
+// the context is done.
+func Fetch(ctx context.Context, r Request) (Response, error) {
+	fetch, err := global("fetch", "fetch")
+	if err != nil {
+		return Response{}, err
+	}
+
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	headers := make(map[string]any, len(r.Header))
+	for k, v := range r.Header {
+		headers[k] = strings.Join(v, ", ")
+	}
+
+	controller := app.Window().Get("AbortController").New()
+	options := map[string]any{
+		"method":  method,
+		"headers": headers,
+		"signal":  controller.Get("signal"),
+	}
+	if r.Credentials != "" {
+		options["credentials"] = r.Credentials
+	}
+	if len(r.Body) != 0 {
+		body := app.Window().Get("Uint8Array").New(len(r.Body))
+		app.CopyBytesToJS(body, r.Body)
+		options["body"] = body
+	}
+
+	abort := func(err error) (Response, error) {
+		if ctx.Err() != nil {
+			controller.Call("abort")
+		}
+		return Response{}, errors.New("fetching resource failed").
+			WithTag("method", method).
+			WithTag("url", r.URL).
+			Wrap(err)
+	}
+
+	res, err := app.Await(ctx, fetch.Invoke(r.URL, options))
+	if err != nil {
+		return abort(err)
+	}
+
+	buffer, err := app.Await(ctx, res.Call("arrayBuffer"))
+	if err != nil {
+		return abort(err)
+	}
+	body := make([]byte, buffer.Get("byteLength").Int())
+	app.CopyBytesToGo(body, app.Window().Get("Uint8Array").New(buffer))
+
+	return Response{
+		URL:        res.Get("url").String(),
+		StatusCode: res.Get("status").Int(),
+		Status:     res.Get("statusText").String(),
+		Header:     headerOf(res.Get("headers")),
+		Body:       body,
+	}, nil
+}
+
+func headerOf(headers app.Value) http.Header {
+	header := make(http.Header)
+	forEach := app.FuncOf(func(this app.Value, args []app.Value) any {
+		header.Add(arg(args, 1).String(), arg(args, 0).String())
+		return nil
+	})
+	defer forEach.Release()
+
+	headers.Call("forEach", forEach)
+	return header
+}
@@ -0,0 +1,170 @@
+package webapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// Position represents the location of the device.
+type Position struct {
+	// The latitude and longitude, in decimal degrees.
+	Latitude  float64
+	Longitude float64
+
+	// The accuracy of the latitude and longitude, in meters.
+	Accuracy float64
+
+	// The altitude above sea level and its accuracy, in meters. Zero when the
+	// device does not provide it.
+	Altitude         float64
+	AltitudeAccuracy float64
+
+	// The direction the device is moving to, in degrees clockwise from true
+	// north, and its speed, in meters per second. Zero when the device does
+	// not provide them.
+	Heading float64
+	Speed   float64
+
+	// The time at which the location was retrieved.
+	Timestamp time.Time
+}
+
+// PositionOptions configures how the location of the device is retrieved.
+type PositionOptions struct {
+	// Requests the most accurate location the device can provide, which can
+	// be slower and consume more power.
+	HighAccuracy bool
+
+	// The maximum duration to wait for a location. No limit when zero.
+	Timeout time.Duration
+
+	// The maximum age of a cached location that is acceptable. A new location
+	// is always retrieved when zero.
+	MaximumAge time.Duration
+}
+
+func (o PositionOptions) toJS() map[string]any {
+	options := map[string]any{
+		"enableHighAccuracy": o.HighAccuracy,
+		"maximumAge":         o.MaximumAge.Milliseconds(),
+	}
+	if o.Timeout > 0 {
+		options["timeout"] = o.Timeout.Milliseconds()
+	}
+	return options
+}
+
+// CurrentPosition returns the current location of the device. The browser may
+// prompt the user for permission.
+func CurrentPosition(ctx context.Context, o PositionOptions) (Position, error) {
+	geolocation, err := global("geolocation", "navigator", "geolocation")
+	if err != nil {
+		return Position{}, err
+	}
+
+	type result struct {
+		position Position
+		err      error
+	}
+	results := make(chan result, 1)
+
+	var onSuccess, onError app.Func
+	settle := func(r result) {
+		results <- r
+		onSuccess.Release()
+		onError.Release()
+	}
+	onSuccess = app.FuncOf(func(this app.Value, args []app.Value) any {
+		settle(result{position: positionOf(arg(args, 0))})
+		return nil
+	})
+	onError = app.FuncOf(func(this app.Value, args []app.Value) any {
+		settle(result{err: geolocationError(arg(args, 0))})
+		return nil
+	})
+	geolocation.Call("getCurrentPosition", onSuccess, onError, o.toJS())
+
+	select {
+	case r := <-results:
+		if r.err != nil {
+			return Position{}, errors.New("getting current position failed").Wrap(r.err)
+		}
+		return r.position, nil
+
+	case <-ctx.Done():
+		return Position{}, errors.New("getting current position canceled").Wrap(ctx.Err())
+	}
+}
+
+// WatchPosition calls the given handler on the UI goroutine each time the
+// location of the device changes, or with an error when the location cannot
+// be retrieved. The returned function stops watching the location.
+func WatchPosition(ctx app.Context, o PositionOptions, h func(app.Context, Position, error)) (stop func(), err error) {
+	geolocation, err := global("geolocation", "navigator", "geolocation")
+	if err != nil {
+		return nil, err
+	}
+
+	onSuccess := app.FuncOf(func(this app.Value, args []app.Value) any {
+		position := positionOf(arg(args, 0))
+		ctx.Dispatch(func(ctx app.Context) {
+			h(ctx, position, nil)
+		})
+		return nil
+	})
+	onError := app.FuncOf(func(this app.Value, args []app.Value) any {
+		err := errors.New("watching position failed").Wrap(geolocationError(arg(args, 0)))
+		ctx.Dispatch(func(ctx app.Context) {
+			h(ctx, Position{}, err)
+		})
+		return nil
+	})
+	id := geolocation.Call("watchPosition", onSuccess, onError, o.toJS())
+
+	return stopper(func() {
+		geolocation.Call("clearWatch", id)
+		onSuccess.Release()
+		onError.Release()
+	}), nil
+}
+
+func positionOf(v app.Value) Position {
+	coords := v.Get("coords")
+	return Position{
+		Latitude:         coords.Get("latitude").Float(),
+		Longitude:        coords.Get("longitude").Float(),
+		Accuracy:         coords.Get("accuracy").Float(),
+		Altitude:         optionalFloat(coords.Get("altitude")),
+		AltitudeAccuracy: optionalFloat(coords.Get("altitudeAccuracy")),
+		Heading:          optionalFloat(coords.Get("heading")),
+		Speed:            optionalFloat(coords.Get("speed")),
+		Timestamp:        time.UnixMilli(int64(v.Get("timestamp").Float())),
+	}
+}
+
+func optionalFloat(v app.Value) float64 {
+	if v.Type() != app.TypeNumber || v.IsNaN() {
+		return 0
+	}
+	return v.Float()
+}
+
+func geolocationError(v app.Value) error {
+	err := app.ErrorOf(v)
+	switch v.Get("code").Int() {
+	case 1:
+		return errors.New("permission denied").Wrap(err)
+
+	case 2:
+		return errors.New("position unavailable").Wrap(err)
+
+	case 3:
+		return errors.New("timeout").Wrap(err)
+
+	default:
+		return err
+	}
+}
@@ -0,0 +1,37 @@
+package webapi
+
+import (
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+// MatchMedia reports whether the page matches the given media query, e.g.
+// "(prefers-color-scheme: dark)" or "(max-width: 600px)".
+func MatchMedia(query string) (bool, error) {
+	matchMedia, err := global("matchMedia", "matchMedia")
+	if err != nil {
+		return false, err
+	}
+	return matchMedia.Invoke(query).Get("matches").Bool(), nil
+}
+
+// ObserveMedia calls the given handler on the UI goroutine each time the page
+// starts or stops matching the given media query. The returned function stops
+// the observation.
+func ObserveMedia(ctx app.Context, query string, h func(app.Context, bool)) (stop func(), err error) {
+	matchMedia, err := global("matchMedia", "matchMedia")
+	if err != nil {
+		return nil, err
+	}
+
+	list := matchMedia.Invoke(query)
+	onChange := app.FuncOf(func(this app.Value, args []app.Value) any {
+		dispatch(ctx, h, arg(args, 0).Get("matches").Bool())
+		return nil
+	})
+	list.Call("addEventListener", "change", onChange)
+
+	return stopper(func() {
+		list.Call("removeEventListener", "change", onChange)
+		onChange.Release()
+	}), nil
+}
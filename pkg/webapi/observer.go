@@ -0,0 +1,129 @@
+package webapi
+
+import (
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+// IntersectionOptions configures an intersection observation.
+type IntersectionOptions struct {
+	// The element used as viewport to check the visibility of the target.
+	// Defaults to the browser viewport when nil.
+	Root app.Wrapper
+
+	// The margin around the root, with the syntax of the CSS margin property,
+	// e.g. "10px 20px". Defaults to "0px".
+	RootMargin string
+
+	// The ratios of visibility of the target at which the handler is called,
+	// between 0.0 and 1.0. Defaults to 0.0, which calls the handler as soon
+	// as one pixel of the target becomes visible or hidden.
+	Thresholds []float64
+}
+
+func (o IntersectionOptions) toJS() map[string]any {
+	options := make(map[string]any)
+	if o.Root != nil {
+		options["root"] = o.Root.JSValue()
+	}
+	if o.RootMargin != "" {
+		options["rootMargin"] = o.RootMargin
+	}
+	if len(o.Thresholds) != 0 {
+		thresholds := make([]any, len(o.Thresholds))
+		for i, t := range o.Thresholds {
+			thresholds[i] = t
+		}
+		options["threshold"] = thresholds
+	}
+	return options
+}
+
+// IntersectionEntry describes a change of the intersection between an
+// observed element and its root.
+type IntersectionEntry struct {
+	// The observed element.
+	Target app.Value
+
+	// Reports whether the target intersects with the root.
+	IsIntersecting bool
+
+	// The ratio of the target that is visible, between 0.0 and 1.0.
+	IntersectionRatio float64
+
+	// The bounds of the target, of its visible part and of the root.
+	BoundingClientRect Rect
+	IntersectionRect   Rect
+	RootBounds         Rect
+}
+
+// ObserveIntersection calls the given handler on the UI goroutine each time
+// the visibility of the given element within its root crosses one of the
+// option thresholds. The returned function stops the observation.
+func ObserveIntersection(ctx app.Context, target app.Wrapper, o IntersectionOptions, h func(app.Context, IntersectionEntry)) (stop func(), err error) {
+	constructor, err := global("IntersectionObserver", "IntersectionObserver")
+	if err != nil {
+		return nil, err
+	}
+
+	callback := app.FuncOf(func(this app.Value, args []app.Value) any {
+		entries := arg(args, 0)
+		for i := 0; i < entries.Length(); i++ {
+			entry := entries.Index(i)
+			dispatch(ctx, h, IntersectionEntry{
+				Target:             entry.Get("target"),
+				IsIntersecting:     entry.Get("isIntersecting").Bool(),
+				IntersectionRatio:  entry.Get("intersectionRatio").Float(),
+				BoundingClientRect: rectOf(entry.Get("boundingClientRect")),
+				IntersectionRect:   rectOf(entry.Get("intersectionRect")),
+				RootBounds:         rectOf(entry.Get("rootBounds")),
+			})
+		}
+		return nil
+	})
+
+	observer := constructor.New(callback, o.toJS())
+	observer.Call("observe", target.JSValue())
+
+	return stopper(func() {
+		observer.Call("disconnect")
+		callback.Release()
+	}), nil
+}
+
+// ResizeEntry describes a change of the size of an observed element.
+type ResizeEntry struct {
+	// The observed element.
+	Target app.Value
+
+	// The content box of the target.
+	ContentRect Rect
+}
+
+// ObserveResize calls the given handler on the UI goroutine each time the size
+// of the given element changes. The returned function stops the observation.
+func ObserveResize(ctx app.Context, target app.Wrapper, h func(app.Context, ResizeEntry)) (stop func(), err error) {
+	constructor, err := global("ResizeObserver", "ResizeObserver")
+	if err != nil {
+		return nil, err
+	}
+
+	callback := app.FuncOf(func(this app.Value, args []app.Value) any {
+		entries := arg(args, 0)
+		for i := 0; i < entries.Length(); i++ {
+			entry := entries.Index(i)
+			dispatch(ctx, h, ResizeEntry{
+				Target:      entry.Get("target"),
+				ContentRect: rectOf(entry.Get("contentRect")),
+			})
+		}
+		return nil
+	})
+
+	observer := constructor.New(callback)
+	observer.Call("observe", target.JSValue())
+
+	return stopper(func() {
+		observer.Call("disconnect")
+		callback.Release()
+	}), nil
+}
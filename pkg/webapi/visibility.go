@@ -0,0 +1,48 @@
+package webapi
+
+import (
+	"github.com/whale1017/go-app/v10/pkg/app"
+)
+
+// VisibilityState represents the visibility of the page.
+type VisibilityState string
+
+// Constants that enumerate the visibility states.
+const (
+	// Visible indicates that the page content is at least partially visible.
+	Visible VisibilityState = "visible"
+
+	// Hidden indicates that the page content is not visible to the user, such
+	// as when its tab is in the background or when the window is minimized.
+	Hidden VisibilityState = "hidden"
+)
+
+// Visibility returns the visibility state of the page.
+func Visibility() (VisibilityState, error) {
+	document, err := global("visibility", "document")
+	if err != nil {
+		return "", err
+	}
+	return VisibilityState(document.Get("visibilityState").String()), nil
+}
+
+// ObserveVisibility calls the given handler on the UI goroutine each time the
+// visibility state of the page changes. The returned function stops the
+// observation.
+func ObserveVisibility(ctx app.Context, h func(app.Context, VisibilityState)) (stop func(), err error) {
+	document, err := global("visibility", "document")
+	if err != nil {
+		return nil, err
+	}
+
+	onChange := app.FuncOf(func(this app.Value, args []app.Value) any {
+		dispatch(ctx, h, VisibilityState(document.Get("visibilityState").String()))
+		return nil
+	})
+	document.Call("addEventListener", "visibilitychange", onChange)
+
+	return stopper(func() {
+		document.Call("removeEventListener", "visibilitychange", onChange)
+		onChange.Release()
+	}), nil
+}
@@ -0,0 +1,94 @@
+// Package webapi provides typed bindings for common Web APIs: Clipboard,
+// Geolocation, Fetch, WebSocket, media queries, IntersectionObserver,
+// ResizeObserver and page visibility.
+//
+// Functions that wait for the browser take a context.Context and return when
+// the operation completes or when the context is done. They block the calling
+// goroutine and are meant to be called within app.Context.Async. Observers
+// take an app.Context and call their handler on the UI goroutine, like
+// app.Context.Dispatch does. They return a function that stops the
+// observation, which is usually called in the OnDismount method of the
+// component that started it.
+//
+// Web APIs are not available on the server and in browsers that do not
+// implement them. Functions then return an error for which IsNotSupported
+// reports true, instead of zero values.
+package webapi
+
+import (
+	"sync"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+const notSupportedError = "not-supported"
+
+// IsNotSupported reports whether the given error is returned because a Web
+// API is not available, such as on the server.
+func IsNotSupported(err error) bool {
+	return errors.HasType(err, notSupportedError)
+}
+
+// Rect represents the position and the size of a rectangle, in pixels.
+type Rect struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+func rectOf(v app.Value) Rect {
+	if !v.Truthy() {
+		return Rect{}
+	}
+	return Rect{
+		X:      v.Get("x").Float(),
+		Y:      v.Get("y").Float(),
+		Width:  v.Get("width").Float(),
+		Height: v.Get("height").Float(),
+	}
+}
+
+// global returns the JavaScript value at the given property path of the
+// browser window. It returns a not supported error when the value is not
+// available.
+func global(api string, path ...string) (app.Value, error) {
+	if app.IsServer {
+		return nil, errors.New("web api is not supported on the server").
+			WithType(notSupportedError).
+			WithTag("api", api)
+	}
+
+	v := app.Window().JSValue()
+	for _, p := range path {
+		if v = v.Get(p); !v.Truthy() {
+			return nil, errors.New("web api is not supported by the browser").
+				WithType(notSupportedError).
+				WithTag("api", api)
+		}
+	}
+	return v, nil
+}
+
+func arg(args []app.Value, i int) app.Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return app.Undefined()
+}
+
+// dispatch calls the given handler on the UI goroutine of the given context.
+func dispatch[T any](ctx app.Context, h func(app.Context, T), v T) {
+	ctx.Dispatch(func(ctx app.Context) {
+		h(ctx, v)
+	})
+}
+
+// stopper returns a function that calls the given function once.
+func stopper(stop func()) func() {
+	var once sync.Once
+	return func() {
+		once.Do(stop)
+	}
+}
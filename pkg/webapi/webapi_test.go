@@ -0,0 +1,161 @@
+//go:build !wasm
+// +build !wasm
+
+package webapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whale1017/go-app/v10/pkg/app"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+func TestServerStubs(t *testing.T) {
+	ctx := context.Background()
+	var appCtx app.Context
+
+	utests := []struct {
+		scenario string
+		call     func() error
+	}{
+		{
+			scenario: "read clipboard text",
+			call: func() error {
+				_, err := ReadClipboardText(ctx)
+				return err
+			},
+		},
+		{
+			scenario: "write clipboard text",
+			call: func() error {
+				return WriteClipboardText(ctx, "hello")
+			},
+		},
+		{
+			scenario: "current position",
+			call: func() error {
+				_, err := CurrentPosition(ctx, PositionOptions{})
+				return err
+			},
+		},
+		{
+			scenario: "watch position",
+			call: func() error {
+				_, err := WatchPosition(appCtx, PositionOptions{}, func(app.Context, Position, error) {})
+				return err
+			},
+		},
+		{
+			scenario: "fetch",
+			call: func() error {
+				_, err := Fetch(ctx, Request{URL: "/hello"})
+				return err
+			},
+		},
+		{
+			scenario: "dial websocket",
+			call: func() error {
+				_, err := DialWebSocket(ctx, "wss://go-app.dev/ws")
+				return err
+			},
+		},
+		{
+			scenario: "match media",
+			call: func() error {
+				_, err := MatchMedia("(prefers-color-scheme: dark)")
+				return err
+			},
+		},
+		{
+			scenario: "observe media",
+			call: func() error {
+				_, err := ObserveMedia(appCtx, "(prefers-color-scheme: dark)", func(app.Context, bool) {})
+				return err
+			},
+		},
+		{
+			scenario: "observe intersection",
+			call: func() error {
+				_, err := ObserveIntersection(appCtx, app.Div(), IntersectionOptions{}, func(app.Context, IntersectionEntry) {})
+				return err
+			},
+		},
+		{
+			scenario: "observe resize",
+			call: func() error {
+				_, err := ObserveResize(appCtx, app.Div(), func(app.Context, ResizeEntry) {})
+				return err
+			},
+		},
+		{
+			scenario: "visibility",
+			call: func() error {
+				_, err := Visibility()
+				return err
+			},
+		},
+		{
+			scenario: "observe visibility",
+			call: func() error {
+				_, err := ObserveVisibility(appCtx, func(app.Context, VisibilityState) {})
+				return err
+			},
+		},
+	}
+
+	for _, u := range utests {
+		t.Run(u.scenario, func(t *testing.T) {
+			err := u.call()
+			require.Error(t, err)
+			require.True(t, IsNotSupported(err))
+			t.Log(err)
+		})
+	}
+}
+
+func TestIsNotSupported(t *testing.T) {
+	require.False(t, IsNotSupported(nil))
+	require.False(t, IsNotSupported(errors.New("test")))
+	require.True(t, IsNotSupported(errors.New("test").WithType(notSupportedError)))
+	require.True(t, IsNotSupported(errors.New("wrap").Wrap(errors.New("test").WithType(notSupportedError))))
+}
+
+func TestPositionOptionsToJS(t *testing.T) {
+	require.Equal(t, map[string]any{
+		"enableHighAccuracy": false,
+		"maximumAge":         int64(0),
+	}, PositionOptions{}.toJS())
+
+	require.Equal(t, map[string]any{
+		"enableHighAccuracy": true,
+		"maximumAge":         int64(60000),
+		"timeout":            int64(5000),
+	}, PositionOptions{
+		HighAccuracy: true,
+		Timeout:      5 * time.Second,
+		MaximumAge:   time.Minute,
+	}.toJS())
+}
+
+func TestIntersectionOptionsToJS(t *testing.T) {
+	require.Empty(t, IntersectionOptions{}.toJS())
+
+	require.Equal(t, map[string]any{
+		"rootMargin": "10px",
+		"threshold":  []any{0.0, 0.5, 1.0},
+	}, IntersectionOptions{
+		RootMargin: "10px",
+		Thresholds: []float64{0, 0.5, 1},
+	}.toJS())
+}
+
+func TestStopper(t *testing.T) {
+	var calls int
+	stop := stopper(func() { calls++ })
+	stop()
+	stop()
+	require.Equal(t, 1, calls)
+}
@@ -0,0 +1,204 @@
+package webapi
+
+import (
+	"context"
+	"sync"
+
+	"github.com/whale1017/go-app/v10/pkg/app"
+	"github.com/whale1017/go-app/v10/pkg/errors"
+)
+
+// MessageType represents the type of a WebSocket message.
+type MessageType int
+
+// Constants that enumerate the WebSocket message types.
+const (
+	TextMessage MessageType = iota
+	BinaryMessage
+)
+
+// Message represents a message received from a WebSocket.
+type Message struct {
+	Type MessageType
+	Data []byte
+}
+
+// WebSocket represents a connection opened with the WebSocket API.
+type WebSocket struct {
+	conn      app.Value
+	callbacks []app.Func
+
+	mu       sync.Mutex
+	messages []Message
+	notify   chan struct{}
+	closeErr error
+}
+
+// DialWebSocket opens a WebSocket connection to the given URL with the given
+// subprotocols, and waits for the connection to be established. The
+// connection is closed when the context is done before.
+func DialWebSocket(ctx context.Context, url string, protocols ...string) (*WebSocket, error) {
+	websocket, err := global("websocket", "WebSocket")
+	if err != nil {
+		return nil, err
+	}
+
+	jsProtocols := make([]any, len(protocols))
+	for i, p := range protocols {
+		jsProtocols[i] = p
+	}
+
+	ws := &WebSocket{
+		conn:   websocket.New(url, jsProtocols),
+		notify: make(chan struct{}, 1),
+	}
+	ws.conn.Set("binaryType", "arraybuffer")
+
+	opened := make(chan struct{}, 1)
+	ws.on("open", func(app.Value) {
+		opened <- struct{}{}
+	})
+	ws.on("message", ws.onMessage)
+	ws.on("close", ws.onClose)
+
+	select {
+	case <-opened:
+		return ws, nil
+
+	case <-ws.notify:
+		ws.mu.Lock()
+		err := ws.closeErr
+		ws.mu.Unlock()
+		return nil, errors.New("dialing websocket failed").
+			WithTag("url", url).
+			Wrap(err)
+
+	case <-ctx.Done():
+		ws.conn.Call("close")
+		return nil, errors.New("dialing websocket canceled").
+			WithTag("url", url).
+			Wrap(ctx.Err())
+	}
+}
+
+// Protocol returns the subprotocol selected by the server.
+func (ws *WebSocket) Protocol() string {
+	return ws.conn.Get("protocol").String()
+}
+
+// SendText sends the given text message.
+func (ws *WebSocket) SendText(text string) error {
+	if err := ws.err(); err != nil {
+		return err
+	}
+	ws.conn.Call("send", text)
+	return nil
+}
+
+// SendBinary sends the given binary message.
+func (ws *WebSocket) SendBinary(data []byte) error {
+	if err := ws.err(); err != nil {
+		return err
+	}
+
+	b := app.Window().Get("Uint8Array").New(len(data))
+	app.CopyBytesToJS(b, data)
+	ws.conn.Call("send", b)
+	return nil
+}
+
+// Receive waits for the next message. It returns an error when the connection
+// is closed and all the received messages have been returned, or when the
+// context is done.
+func (ws *WebSocket) Receive(ctx context.Context) (Message, error) {
+	for {
+		ws.mu.Lock()
+		if len(ws.messages) != 0 {
+			m := ws.messages[0]
+			ws.messages = ws.messages[1:]
+			ws.mu.Unlock()
+			return m, nil
+		}
+		err := ws.closeErr
+		ws.mu.Unlock()
+
+		if err != nil {
+			return Message{}, err
+		}
+
+		select {
+		case <-ws.notify:
+
+		case <-ctx.Done():
+			return Message{}, errors.New("receiving websocket message canceled").Wrap(ctx.Err())
+		}
+	}
+}
+
+// Close closes the connection with the given status code and reason. Status
+// code 1000 indicates a normal closure.
+func (ws *WebSocket) Close(code int, reason string) error {
+	if err := ws.err(); err != nil {
+		return err
+	}
+	ws.conn.Call("close", code, reason)
+	return nil
+}
+
+func (ws *WebSocket) on(event string, h func(app.Value)) {
+	fn := app.FuncOf(func(this app.Value, args []app.Value) any {
+		h(arg(args, 0))
+		return nil
+	})
+	ws.callbacks = append(ws.callbacks, fn)
+	ws.conn.Set("on"+event, fn)
+}
+
+func (ws *WebSocket) onMessage(event app.Value) {
+	data := event.Get("data")
+
+	m := Message{Type: TextMessage}
+	if data.Type() == app.TypeString {
+		m.Data = []byte(data.String())
+	} else {
+		m.Type = BinaryMessage
+		m.Data = make([]byte, data.Get("byteLength").Int())
+		app.CopyBytesToGo(m.Data, app.Window().Get("Uint8Array").New(data))
+	}
+
+	ws.mu.Lock()
+	ws.messages = append(ws.messages, m)
+	ws.mu.Unlock()
+	ws.signal()
+}
+
+func (ws *WebSocket) onClose(event app.Value) {
+	ws.mu.Lock()
+	ws.closeErr = errors.New("websocket closed").
+		WithTag("code", event.Get("code").Int()).
+		WithTag("reason", event.Get("reason").String()).
+		WithTag("clean", event.Get("wasClean").Bool())
+	ws.mu.Unlock()
+	ws.signal()
+
+	for _, event := range []string{"open", "message", "close"} {
+		ws.conn.Set("on"+event, nil)
+	}
+	for _, fn := range ws.callbacks {
+		fn.Release()
+	}
+	ws.callbacks = nil
+}
+
+func (ws *WebSocket) signal() {
+	select {
+	case ws.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (ws *WebSocket) err() error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.closeErr
+}